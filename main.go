@@ -67,6 +67,9 @@ func run() int {
 		if _, isNotInSync := err.(cmderrors.InfrastructureNotInSync); isNotInSync {
 			return 1
 		}
+		if failed, isFailed := err.(cmderrors.FailedConditions); isFailed {
+			return failed.ExitCode
+		}
 		if cmd.IsReportingEnabled(&driftctlCmd.Command) {
 			sentry.CaptureException(err)
 		}
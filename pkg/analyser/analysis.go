@@ -7,16 +7,19 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/r3labs/diff/v2"
 
 	"github.com/snyk/driftctl/pkg/alerter"
+	"github.com/snyk/driftctl/pkg/filter"
 	"github.com/snyk/driftctl/pkg/resource"
 )
 
 type Change struct {
 	diff.Change
-	Computed   bool `json:"computed"`
-	JsonString bool `json:"-"`
+	Computed   bool     `json:"computed"`
+	JsonString bool     `json:"-"`
+	Severity   Severity `json:"severity,omitempty"`
 }
 
 type Changelog []Change
@@ -26,43 +29,118 @@ type Difference struct {
 	Changelog Changelog
 }
 
+// Duplicate is a cloud resource claimed by more than one resource of the IaC
+type Duplicate struct {
+	// Res is the resource found on the cloud provider
+	Res *resource.Resource
+	// Claims are the IaC resources corresponding to Res, in the order they were read
+	Claims []*resource.Resource
+}
+
 type Summary struct {
-	TotalResources int `json:"total_resources"`
-	TotalDrifted   int `json:"total_changed"`
-	TotalUnmanaged int `json:"total_unmanaged"`
-	TotalDeleted   int `json:"total_missing"`
-	TotalManaged   int `json:"total_managed"`
+	TotalResources  int `json:"total_resources"`
+	TotalDrifted    int `json:"total_changed"`
+	TotalUnmanaged  int `json:"total_unmanaged"`
+	TotalDeleted    int `json:"total_missing"`
+	TotalManaged    int `json:"total_managed"`
+	TotalDuplicated int `json:"total_duplicated,omitempty"`
+}
+
+type Provider struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// ProviderSummary breaks the analysis down to resources of a single provider
+type ProviderSummary struct {
+	Provider
+	Coverage int     `json:"coverage"`
+	Summary  Summary `json:"summary"`
+}
+
+// FailedCondition is a fail condition given to the scan that the analysis meets
+type FailedCondition struct {
+	Condition string `json:"condition"`
+	Message   string `json:"message"`
+	ExitCode  int    `json:"exit_code"`
 }
 
 type Analysis struct {
-	unmanaged       []*resource.Resource
-	managed         []*resource.Resource
-	deleted         []*resource.Resource
-	differences     []Difference
-	options         AnalyzerOptions
-	summary         Summary
-	alerts          alerter.Alerts
-	Duration        time.Duration
-	Date            time.Time
-	ProviderName    string
-	ProviderVersion string
+	unmanaged        []*resource.Resource
+	managed          []*resource.Resource
+	deleted          []*resource.Resource
+	differences      []Difference
+	duplicated       []Duplicate
+	options          AnalyzerOptions
+	summary          Summary
+	alerts           alerter.Alerts
+	failedConditions []FailedCondition
+	ignoreRules      []filter.IgnoreRule
+	Duration         time.Duration
+	Date             time.Time
+	Providers        []Provider
+}
+
+// JSON schema versions of a serialized analysis
+const (
+	// SchemaVersion1 only identifies resources
+	SchemaVersion1 = 1
+	// SchemaVersion2 keeps attributes and full Terraform addresses of every resource, it round-trips losslessly
+	SchemaVersion2 = 2
+)
+
+type serializableChange struct {
+	diff.Change
+	Computed bool     `json:"computed"`
+	Severity Severity `json:"severity,omitempty"`
+	// Only serialized in the version 2
+	JsonString bool `json:"json_string,omitempty"`
+}
+
+func newSerializableChangelog(changelog Changelog, withJsonString bool) []serializableChange {
+	changes := make([]serializableChange, 0, len(changelog))
+	for _, change := range changelog {
+		c := serializableChange{Change: change.Change, Computed: change.Computed, Severity: change.Severity}
+		if withJsonString {
+			c.JsonString = change.JsonString
+		}
+		changes = append(changes, c)
+	}
+	return changes
 }
 
 type serializableDifference struct {
 	Res       resource.SerializableResource `json:"res"`
-	Changelog Changelog                     `json:"changelog"`
+	Changelog []serializableChange          `json:"changelog"`
+}
+
+type serializableDuplicate struct {
+	Res resource.SerializableResource `json:"res"`
+	// Claims always hold their address, whatever the schema version
+	Claims []resource.SerializableResource `json:"claims"`
 }
 
 type serializableAnalysis struct {
-	Summary         Summary                                `json:"summary"`
-	Managed         []resource.SerializableResource        `json:"managed"`
-	Unmanaged       []resource.SerializableResource        `json:"unmanaged"`
-	Deleted         []resource.SerializableResource        `json:"missing"`
-	Differences     []serializableDifference               `json:"differences"`
-	Coverage        int                                    `json:"coverage"`
-	Alerts          map[string][]alerter.SerializableAlert `json:"alerts"`
-	ProviderName    string                                 `json:"provider_name"`
-	ProviderVersion string                                 `json:"provider_version"`
+	// Not serialized in the version 1
+	SchemaVersion int                                    `json:"schema_version,omitempty"`
+	Summary       Summary                                `json:"summary"`
+	Managed       []resource.SerializableResource        `json:"managed"`
+	Unmanaged     []resource.SerializableResource        `json:"unmanaged"`
+	Deleted       []resource.SerializableResource        `json:"missing"`
+	Differences   []serializableDifference               `json:"differences"`
+	Duplicated    []serializableDuplicate                `json:"duplicated,omitempty"`
+	Coverage      int                                    `json:"coverage"`
+	Alerts        map[string][]alerter.SerializableAlert `json:"alerts"`
+	Providers     []ProviderSummary                      `json:"providers"`
+	// Only set when fail conditions are given to the scan
+	FailedConditions []FailedCondition `json:"failed_conditions,omitempty"`
+	// Only serialized in the version 2
+	Date     *time.Time    `json:"date,omitempty"`
+	Duration time.Duration `json:"duration,omitempty"`
+	Deep     bool          `json:"deep,omitempty"`
+	// Only written when a single provider is scanned, as before several providers could be scanned at once
+	ProviderName    string `json:"provider_name,omitempty"`
+	ProviderVersion string `json:"provider_version,omitempty"`
 }
 
 type GenDriftIgnoreOptions struct {
@@ -78,22 +156,57 @@ func NewAnalysis(options AnalyzerOptions) *Analysis {
 }
 
 func (a Analysis) MarshalJSON() ([]byte, error) {
+	return a.MarshalJSONVersion(SchemaVersion1)
+}
+
+// MarshalJSONVersion serializes the analysis with the given schema version
+func (a Analysis) MarshalJSONVersion(version int) ([]byte, error) {
+	if version != SchemaVersion1 && version != SchemaVersion2 {
+		return nil, errors.Errorf("unsupported JSON schema version %d", version)
+	}
+	v2 := version == SchemaVersion2
+	serialize := func(res *resource.Resource) resource.SerializableResource {
+		serializable := resource.NewSerializableResource(res)
+		if v2 {
+			serializable.Attributes = res.Attributes()
+			serializable.Address = res.Address()
+			if src, ok := res.Source.(interface{ InstanceKey() string }); ok && serializable.Source != nil {
+				serializable.Source.Key = src.InstanceKey()
+			}
+		}
+		return *serializable
+	}
+
 	bla := serializableAnalysis{}
 	for _, m := range a.managed {
-		bla.Managed = append(bla.Managed, *resource.NewSerializableResource(m))
+		bla.Managed = append(bla.Managed, serialize(m))
 	}
 	for _, u := range a.unmanaged {
-		bla.Unmanaged = append(bla.Unmanaged, *resource.NewSerializableResource(u))
+		res := serialize(u)
+		// Attributes read in deep mode allow to generate configuration of unmanaged resources
+		if a.options.Deep {
+			res.Attributes = u.Attributes()
+		}
+		bla.Unmanaged = append(bla.Unmanaged, res)
 	}
 	for _, d := range a.deleted {
-		bla.Deleted = append(bla.Deleted, *resource.NewSerializableResource(d))
+		bla.Deleted = append(bla.Deleted, serialize(d))
 	}
 	for _, di := range a.differences {
 		bla.Differences = append(bla.Differences, serializableDifference{
-			Res:       *resource.NewSerializableResource(di.Res),
-			Changelog: di.Changelog,
+			Res:       serialize(di.Res),
+			Changelog: newSerializableChangelog(di.Changelog, v2),
 		})
 	}
+	for _, du := range a.duplicated {
+		duplicate := serializableDuplicate{Res: serialize(du.Res)}
+		for _, claim := range du.Claims {
+			c := serialize(claim)
+			c.Address = claim.Address()
+			duplicate.Claims = append(duplicate.Claims, c)
+		}
+		bla.Duplicated = append(bla.Duplicated, duplicate)
+	}
 	if len(a.alerts) > 0 {
 		bla.Alerts = make(map[string][]alerter.SerializableAlert)
 		for k, v := range a.alerts {
@@ -104,8 +217,19 @@ func (a Analysis) MarshalJSON() ([]byte, error) {
 	}
 	bla.Summary = a.summary
 	bla.Coverage = a.Coverage()
-	bla.ProviderName = a.ProviderName
-	bla.ProviderVersion = a.ProviderVersion
+	bla.Providers = a.ProviderSummaries()
+	if len(a.Providers) == 1 {
+		bla.ProviderName = a.Providers[0].Name
+		bla.ProviderVersion = a.Providers[0].Version
+	}
+	bla.FailedConditions = a.failedConditions
+	if v2 {
+		bla.SchemaVersion = SchemaVersion2
+		date := a.Date
+		bla.Date = &date
+		bla.Duration = a.Duration
+		bla.Deep = a.options.Deep
+	}
 
 	return json.Marshal(bla)
 }
@@ -115,33 +239,47 @@ func (a *Analysis) UnmarshalJSON(bytes []byte) error {
 	if err := json.Unmarshal(bytes, &bla); err != nil {
 		return err
 	}
+	if bla.SchemaVersion > SchemaVersion2 {
+		return errors.Errorf("unsupported JSON schema version %d", bla.SchemaVersion)
+	}
+	if bla.SchemaVersion == SchemaVersion2 {
+		a.options.Deep = bla.Deep
+		a.Duration = bla.Duration
+		if bla.Date != nil {
+			a.Date = *bla.Date
+		}
+	}
 	for _, u := range bla.Unmanaged {
-		a.AddUnmanaged(&resource.Resource{
-			Id:   u.Id,
-			Type: u.Type,
-		})
+		a.AddUnmanaged(deserializeResource(u))
 	}
 	for _, d := range bla.Deleted {
-		a.AddDeleted(&resource.Resource{
-			Id:   d.Id,
-			Type: d.Type,
-		})
+		a.AddDeleted(deserializeResource(d))
 	}
 	for _, m := range bla.Managed {
-		a.AddManaged(&resource.Resource{
-			Id:   m.Id,
-			Type: m.Type,
-		})
+		a.AddManaged(deserializeResource(m))
 	}
 	for _, di := range bla.Differences {
+		changelog := make(Changelog, 0, len(di.Changelog))
+		for _, change := range di.Changelog {
+			changelog = append(changelog, Change{
+				Change:     change.Change,
+				Computed:   change.Computed,
+				JsonString: change.JsonString,
+				Severity:   change.Severity,
+			})
+		}
 		a.AddDifference(Difference{
-			Res: &resource.Resource{
-				Id:   di.Res.Id,
-				Type: di.Res.Type,
-			},
-			Changelog: di.Changelog,
+			Res:       deserializeResource(di.Res),
+			Changelog: changelog,
 		})
 	}
+	for _, du := range bla.Duplicated {
+		duplicate := Duplicate{Res: deserializeResource(du.Res)}
+		for _, claim := range du.Claims {
+			duplicate.Claims = append(duplicate.Claims, deserializeResource(claim))
+		}
+		a.AddDuplicated(duplicate)
+	}
 	if len(bla.Alerts) > 0 {
 		a.alerts = make(alerter.Alerts)
 		for k, v := range bla.Alerts {
@@ -152,13 +290,36 @@ func (a *Analysis) UnmarshalJSON(bytes []byte) error {
 			}
 		}
 	}
-	a.ProviderName = bla.ProviderName
-	a.ProviderVersion = bla.ProviderVersion
+	for _, p := range bla.Providers {
+		a.Providers = append(a.Providers, p.Provider)
+	}
+	a.failedConditions = bla.FailedConditions
+	// Analysis written by previous versions only hold a single provider
+	if len(bla.Providers) == 0 && bla.ProviderName != "" {
+		a.Providers = append(a.Providers, Provider{
+			Name:    bla.ProviderName,
+			Version: bla.ProviderVersion,
+		})
+	}
 	return nil
 }
 
+func deserializeResource(res resource.SerializableResource) *resource.Resource {
+	r := &resource.Resource{
+		Id:      res.Id,
+		Type:    res.Type,
+		Region:  res.Region,
+		Account: res.Account,
+		Attrs:   res.Attributes,
+	}
+	if res.Source != nil {
+		r.Source = res.Source
+	}
+	return r
+}
+
 func (a *Analysis) IsSync() bool {
-	return a.summary.TotalDrifted == 0 && a.summary.TotalUnmanaged == 0 && a.summary.TotalDeleted == 0
+	return a.summary.TotalDrifted == 0 && a.summary.TotalUnmanaged == 0 && a.summary.TotalDeleted == 0 && a.summary.TotalDuplicated == 0
 }
 
 func (a *Analysis) Options() AnalyzerOptions {
@@ -188,17 +349,89 @@ func (a *Analysis) AddDifference(diffs ...Difference) {
 	a.summary.TotalDrifted += len(diffs)
 }
 
+// AddDuplicated records managed resources claimed by several IaC resources, they are not counted twice in
+// total resources as they are already added as managed
+func (a *Analysis) AddDuplicated(duplicates ...Duplicate) {
+	a.duplicated = append(a.duplicated, duplicates...)
+	a.summary.TotalDuplicated += len(duplicates)
+}
+
 func (a *Analysis) SetAlerts(alerts alerter.Alerts) {
 	a.alerts = alerts
 }
 
+func (a *Analysis) SetFailedConditions(conditions []FailedCondition) {
+	a.failedConditions = conditions
+}
+
+func (a *Analysis) FailedConditions() []FailedCondition {
+	return a.failedConditions
+}
+
+func (a *Analysis) SetIgnoreRules(rules []filter.IgnoreRule) {
+	a.ignoreRules = rules
+}
+
+// IgnoreRules returns driftignore rules of the scan, see filter.DriftIgnore.Rules. They are not part of the JSON output
+// and only recorded in history
+func (a *Analysis) IgnoreRules() []filter.IgnoreRule {
+	return a.ignoreRules
+}
+
 func (a *Analysis) Coverage() int {
-	if a.summary.TotalResources > 0 {
-		return int((float32(a.summary.TotalManaged) / float32(a.summary.TotalResources)) * 100.0)
+	return coverage(a.summary)
+}
+
+func coverage(summary Summary) int {
+	if summary.TotalResources > 0 {
+		return int((float32(summary.TotalManaged) / float32(summary.TotalResources)) * 100.0)
 	}
 	return 0
 }
 
+// ProviderSummaries computes summary and coverage of each scanned provider
+func (a *Analysis) ProviderSummaries() []ProviderSummary {
+	if len(a.Providers) == 0 {
+		return nil
+	}
+
+	summaries := make([]ProviderSummary, 0, len(a.Providers))
+	for _, provider := range a.Providers {
+		summary := Summary{
+			TotalManaged:   countProviderResources(provider.Name, a.managed),
+			TotalUnmanaged: countProviderResources(provider.Name, a.unmanaged),
+			TotalDeleted:   countProviderResources(provider.Name, a.deleted),
+		}
+		summary.TotalResources = summary.TotalManaged + summary.TotalUnmanaged + summary.TotalDeleted
+		for _, d := range a.differences {
+			if resource.ResourceType(d.Res.ResourceType()).Provider() == provider.Name {
+				summary.TotalDrifted++
+			}
+		}
+		for _, d := range a.duplicated {
+			if resource.ResourceType(d.Res.ResourceType()).Provider() == provider.Name {
+				summary.TotalDuplicated++
+			}
+		}
+		summaries = append(summaries, ProviderSummary{
+			Provider: provider,
+			Coverage: coverage(summary),
+			Summary:  summary,
+		})
+	}
+	return summaries
+}
+
+func countProviderResources(provider string, resources []*resource.Resource) int {
+	count := 0
+	for _, res := range resources {
+		if resource.ResourceType(res.ResourceType()).Provider() == provider {
+			count++
+		}
+	}
+	return count
+}
+
 func (a *Analysis) Managed() []*resource.Resource {
 	return a.managed
 }
@@ -215,6 +448,10 @@ func (a *Analysis) Differences() []Difference {
 	return a.differences
 }
 
+func (a *Analysis) Duplicated() []Duplicate {
+	return a.duplicated
+}
+
 func (a *Analysis) Summary() Summary {
 	return a.summary
 }
@@ -227,6 +464,12 @@ func (a *Analysis) SortResources() {
 	a.unmanaged = resource.Sort(a.unmanaged)
 	a.deleted = resource.Sort(a.deleted)
 	a.differences = SortDifferences(a.differences)
+	sort.SliceStable(a.duplicated, func(i, j int) bool {
+		if a.duplicated[i].Res.ResourceType() != a.duplicated[j].Res.ResourceType() {
+			return a.duplicated[i].Res.ResourceType() < a.duplicated[j].Res.ResourceType()
+		}
+		return a.duplicated[i].Res.ResourceId() < a.duplicated[j].Res.ResourceId()
+	})
 }
 
 func (a *Analysis) DriftIgnoreList(opts GenDriftIgnoreOptions) (int, string) {
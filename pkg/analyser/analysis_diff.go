@@ -0,0 +1,209 @@
+package analyser
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/snyk/driftctl/pkg/resource"
+)
+
+// AnalysisDiff holds what changed between two analysis of the same infrastructure
+type AnalysisDiff struct {
+	Previous *Analysis
+	Current  *Analysis
+	// Resources not covered by IaC that were not reported as such previously
+	Unmanaged []*resource.Resource
+	// Resources previously unmanaged that became managed by IaC
+	Managed []*resource.Resource
+	// Resources that went missing since the previous analysis
+	Deleted []*resource.Resource
+	// Changes detected on resources that were not there previously
+	IntroducedDifferences []Difference
+	// Changes previously detected that are gone
+	FixedDifferences []Difference
+}
+
+type serializableAnalysisDiff struct {
+	PreviousCoverage      int                             `json:"previous_coverage"`
+	Coverage              int                             `json:"coverage"`
+	CoverageDelta         int                             `json:"coverage_delta"`
+	Unmanaged             []resource.SerializableResource `json:"unmanaged"`
+	Managed               []resource.SerializableResource `json:"managed"`
+	Deleted               []resource.SerializableResource `json:"missing"`
+	IntroducedDifferences []serializableDifference        `json:"introduced_differences"`
+	FixedDifferences      []serializableDifference        `json:"fixed_differences"`
+}
+
+func NewAnalysisDiff(previous, current *Analysis) *AnalysisDiff {
+	d := &AnalysisDiff{
+		Previous:              previous,
+		Current:               current,
+		Unmanaged:             make([]*resource.Resource, 0),
+		Managed:               make([]*resource.Resource, 0),
+		Deleted:               make([]*resource.Resource, 0),
+		IntroducedDifferences: make([]Difference, 0),
+		FixedDifferences:      make([]Difference, 0),
+	}
+
+	previousUnmanaged := newResourceIndex(previous.Unmanaged())
+	for _, res := range current.Unmanaged() {
+		if !previousUnmanaged.contains(res) {
+			d.Unmanaged = append(d.Unmanaged, res)
+		}
+	}
+
+	// Resources newly created along with their IaC were not unmanaged before, they did not become managed
+	for _, res := range current.Managed() {
+		if previousUnmanaged.contains(res) {
+			d.Managed = append(d.Managed, res)
+		}
+	}
+
+	previousDeleted := newResourceIndex(previous.Deleted())
+	for _, res := range current.Deleted() {
+		if !previousDeleted.contains(res) {
+			d.Deleted = append(d.Deleted, res)
+		}
+	}
+
+	d.IntroducedDifferences = subtractDifferences(current.Differences(), previous.Differences())
+	d.FixedDifferences = subtractDifferences(previous.Differences(), current.Differences())
+
+	d.Unmanaged = resource.Sort(d.Unmanaged)
+	d.Managed = resource.Sort(d.Managed)
+	d.Deleted = resource.Sort(d.Deleted)
+	d.IntroducedDifferences = SortDifferences(d.IntroducedDifferences)
+	d.FixedDifferences = SortDifferences(d.FixedDifferences)
+
+	return d
+}
+
+func (d *AnalysisDiff) CoverageDelta() int {
+	return d.Current.Coverage() - d.Previous.Coverage()
+}
+
+// IsEmpty returns true when nothing changed between the two analysis
+func (d *AnalysisDiff) IsEmpty() bool {
+	return len(d.Unmanaged) == 0 &&
+		len(d.Managed) == 0 &&
+		len(d.Deleted) == 0 &&
+		len(d.IntroducedDifferences) == 0 &&
+		len(d.FixedDifferences) == 0 &&
+		d.CoverageDelta() == 0
+}
+
+func (d AnalysisDiff) MarshalJSON() ([]byte, error) {
+	bla := serializableAnalysisDiff{
+		PreviousCoverage:      d.Previous.Coverage(),
+		Coverage:              d.Current.Coverage(),
+		CoverageDelta:         d.CoverageDelta(),
+		Unmanaged:             make([]resource.SerializableResource, 0, len(d.Unmanaged)),
+		Managed:               make([]resource.SerializableResource, 0, len(d.Managed)),
+		Deleted:               make([]resource.SerializableResource, 0, len(d.Deleted)),
+		IntroducedDifferences: make([]serializableDifference, 0, len(d.IntroducedDifferences)),
+		FixedDifferences:      make([]serializableDifference, 0, len(d.FixedDifferences)),
+	}
+	for _, u := range d.Unmanaged {
+		bla.Unmanaged = append(bla.Unmanaged, *resource.NewSerializableResource(u))
+	}
+	for _, m := range d.Managed {
+		bla.Managed = append(bla.Managed, *resource.NewSerializableResource(m))
+	}
+	for _, de := range d.Deleted {
+		bla.Deleted = append(bla.Deleted, *resource.NewSerializableResource(de))
+	}
+	for _, di := range d.IntroducedDifferences {
+		bla.IntroducedDifferences = append(bla.IntroducedDifferences, serializableDifference{
+			Res:       *resource.NewSerializableResource(di.Res),
+			Changelog: newSerializableChangelog(di.Changelog, false),
+		})
+	}
+	for _, di := range d.FixedDifferences {
+		bla.FixedDifferences = append(bla.FixedDifferences, serializableDifference{
+			Res:       *resource.NewSerializableResource(di.Res),
+			Changelog: newSerializableChangelog(di.Changelog, false),
+		})
+	}
+	return json.Marshal(bla)
+}
+
+// subtractDifferences returns changes of diffs that are not part of other
+func subtractDifferences(diffs, other []Difference) []Difference {
+	otherByResource := make(map[string][]Difference, len(other))
+	for _, d := range other {
+		key := resourceKey(d.Res)
+		otherByResource[key] = append(otherByResource[key], d)
+	}
+
+	result := make([]Difference, 0)
+	for _, d := range diffs {
+		var otherChangelog Changelog
+		for _, o := range otherByResource[resourceKey(d.Res)] {
+			if sameResource(o.Res, d.Res) {
+				otherChangelog = o.Changelog
+				break
+			}
+		}
+
+		changelog := make(Changelog, 0, len(d.Changelog))
+		for _, change := range d.Changelog {
+			if !containsChange(otherChangelog, change) {
+				changelog = append(changelog, change)
+			}
+		}
+		if len(changelog) > 0 {
+			result = append(result, Difference{Res: d.Res, Changelog: changelog})
+		}
+	}
+	return result
+}
+
+func containsChange(changelog Changelog, change Change) bool {
+	for _, c := range changelog {
+		if c.Type == change.Type &&
+			strings.Join(c.Path, ".") == strings.Join(change.Path, ".") &&
+			reflect.DeepEqual(c.From, change.From) &&
+			reflect.DeepEqual(c.To, change.To) {
+			return true
+		}
+	}
+	return false
+}
+
+type resourceIndex map[string][]*resource.Resource
+
+func newResourceIndex(resources []*resource.Resource) resourceIndex {
+	index := make(resourceIndex, len(resources))
+	for _, res := range resources {
+		key := resourceKey(res)
+		index[key] = append(index[key], res)
+	}
+	return index
+}
+
+func (i resourceIndex) contains(res *resource.Resource) bool {
+	for _, r := range i[resourceKey(res)] {
+		if sameResource(r, res) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameResource tells whether two resources of different analysis are the same.
+// Resources read from IaC may not know their identifier, scan results do not
+// contain their attributes so they are told apart using their IaC address.
+func sameResource(r, res *resource.Resource) bool {
+	if r.ResourceId() == "" && res.ResourceId() == "" && r.Src() != nil && res.Src() != nil {
+		return r.ResourceType() == res.ResourceType() &&
+			r.Src().Source() == res.Src().Source() &&
+			r.SourceString() == res.SourceString()
+	}
+	return r.Equal(res)
+}
+
+func resourceKey(res *resource.Resource) string {
+	return fmt.Sprintf("%s.%s", res.ResourceType(), res.ResourceId())
+}
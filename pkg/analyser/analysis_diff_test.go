@@ -0,0 +1,189 @@
+package analyser
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/r3labs/diff/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/snyk/driftctl/pkg/resource"
+)
+
+func TestNewAnalysisDiff(t *testing.T) {
+	bucket := &resource.Resource{Id: "bucket", Type: "aws_s3_bucket"}
+	user := &resource.Resource{Id: "user", Type: "aws_iam_user"}
+	role := &resource.Resource{Id: "role", Type: "aws_iam_role"}
+	queue := &resource.Resource{Id: "queue", Type: "aws_sqs_queue"}
+
+	aclChange := Change{Change: diff.Change{Type: diff.UPDATE, Path: []string{"acl"}, From: "private", To: "public-read"}}
+	tagChange := Change{Change: diff.Change{Type: diff.CREATE, Path: []string{"tags", "Env"}, From: nil, To: "prod"}}
+
+	tests := []struct {
+		name                      string
+		previous                  func(a *Analysis)
+		current                   func(a *Analysis)
+		wantUnmanaged             []*resource.Resource
+		wantManaged               []*resource.Resource
+		wantDeleted               []*resource.Resource
+		wantIntroducedDifferences []Difference
+		wantFixedDifferences      []Difference
+		wantCoverageDelta         int
+		wantEmpty                 bool
+	}{
+		{
+			name: "identical analysis",
+			previous: func(a *Analysis) {
+				a.AddManaged(bucket)
+				a.AddUnmanaged(user)
+				a.AddDifference(Difference{Res: bucket, Changelog: Changelog{aclChange}})
+			},
+			current: func(a *Analysis) {
+				a.AddManaged(bucket)
+				a.AddUnmanaged(user)
+				a.AddDifference(Difference{Res: bucket, Changelog: Changelog{aclChange}})
+			},
+			wantUnmanaged:             []*resource.Resource{},
+			wantManaged:               []*resource.Resource{},
+			wantDeleted:               []*resource.Resource{},
+			wantIntroducedDifferences: []Difference{},
+			wantFixedDifferences:      []Difference{},
+			wantEmpty:                 true,
+		},
+		{
+			name: "resources that became managed or unmanaged",
+			previous: func(a *Analysis) {
+				a.AddManaged(bucket)
+				a.AddUnmanaged(user, queue)
+			},
+			current: func(a *Analysis) {
+				a.AddManaged(bucket, user)
+				a.AddUnmanaged(queue, role)
+			},
+			wantUnmanaged:             []*resource.Resource{role},
+			wantManaged:               []*resource.Resource{user},
+			wantDeleted:               []*resource.Resource{},
+			wantIntroducedDifferences: []Difference{},
+			wantFixedDifferences:      []Difference{},
+			wantCoverageDelta:         17,
+		},
+		{
+			name: "new managed resources that were not unmanaged before",
+			previous: func(a *Analysis) {
+				a.AddManaged(bucket)
+			},
+			current: func(a *Analysis) {
+				a.AddManaged(bucket, user)
+			},
+			wantUnmanaged:             []*resource.Resource{},
+			wantManaged:               []*resource.Resource{},
+			wantDeleted:               []*resource.Resource{},
+			wantIntroducedDifferences: []Difference{},
+			wantFixedDifferences:      []Difference{},
+			wantEmpty:                 true,
+		},
+		{
+			name: "new missing resources",
+			previous: func(a *Analysis) {
+				a.AddManaged(bucket, user)
+				a.AddDeleted(role)
+			},
+			current: func(a *Analysis) {
+				a.AddManaged(bucket)
+				a.AddDeleted(role, user)
+			},
+			wantUnmanaged:             []*resource.Resource{},
+			wantManaged:               []*resource.Resource{},
+			wantDeleted:               []*resource.Resource{user},
+			wantIntroducedDifferences: []Difference{},
+			wantFixedDifferences:      []Difference{},
+			wantCoverageDelta:         -33,
+		},
+		{
+			name: "introduced and fixed changes",
+			previous: func(a *Analysis) {
+				a.AddManaged(bucket, user)
+				a.AddDifference(Difference{Res: bucket, Changelog: Changelog{aclChange}})
+				a.AddDifference(Difference{Res: user, Changelog: Changelog{tagChange}})
+			},
+			current: func(a *Analysis) {
+				a.AddManaged(bucket, user)
+				a.AddDifference(Difference{Res: bucket, Changelog: Changelog{aclChange, tagChange}})
+			},
+			wantUnmanaged: []*resource.Resource{},
+			wantManaged:   []*resource.Resource{},
+			wantDeleted:   []*resource.Resource{},
+			wantIntroducedDifferences: []Difference{
+				{Res: bucket, Changelog: Changelog{tagChange}},
+			},
+			wantFixedDifferences: []Difference{
+				{Res: user, Changelog: Changelog{tagChange}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := &Analysis{}
+			tt.previous(previous)
+			current := &Analysis{}
+			tt.current(current)
+
+			got := NewAnalysisDiff(previous, current)
+			assert.Equal(t, tt.wantUnmanaged, got.Unmanaged)
+			assert.Equal(t, tt.wantManaged, got.Managed)
+			assert.Equal(t, tt.wantDeleted, got.Deleted)
+			assert.Equal(t, tt.wantIntroducedDifferences, got.IntroducedDifferences)
+			assert.Equal(t, tt.wantFixedDifferences, got.FixedDifferences)
+			assert.Equal(t, tt.wantCoverageDelta, got.CoverageDelta())
+			assert.Equal(t, tt.wantEmpty, got.IsEmpty())
+		})
+	}
+}
+
+func TestNewAnalysisDiff_UnknownIdentifier(t *testing.T) {
+	// Resources read from a Terraform configuration may not know their identifier yet
+	admins := func() *resource.Resource {
+		return &resource.Resource{
+			Id:     "",
+			Type:   "github_team",
+			Source: resource.NewTerraformConfigSource("tfconfig://infra", "", `team["admins"]`),
+		}
+	}
+	developers := &resource.Resource{
+		Id:     "",
+		Type:   "github_team",
+		Source: resource.NewTerraformConfigSource("tfconfig://infra", "", `team["developers"]`),
+	}
+
+	previous := &Analysis{}
+	previous.AddDeleted(admins())
+
+	current := &Analysis{}
+	current.AddDeleted(admins(), developers)
+
+	got := NewAnalysisDiff(previous, current)
+	assert.Equal(t, []*resource.Resource{developers}, got.Deleted)
+}
+
+func TestAnalysisDiff_MarshalJSON(t *testing.T) {
+	previous := &Analysis{}
+	previous.AddManaged(&resource.Resource{Id: "bucket", Type: "aws_s3_bucket"})
+	previous.AddUnmanaged(&resource.Resource{Id: "user", Type: "aws_iam_user"})
+
+	current := &Analysis{}
+	current.AddManaged(&resource.Resource{Id: "bucket", Type: "aws_s3_bucket"})
+	current.AddManaged(&resource.Resource{Id: "user", Type: "aws_iam_user"})
+
+	got, err := json.Marshal(NewAnalysisDiff(previous, current))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"previous_coverage": 50,
+		"coverage": 100,
+		"coverage_delta": 50,
+		"unmanaged": [],
+		"managed": [{"id": "user", "type": "aws_iam_user"}],
+		"missing": [],
+		"introduced_differences": [],
+		"fixed_differences": []
+	}`, string(got))
+}
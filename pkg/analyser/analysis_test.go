@@ -0,0 +1,243 @@
+package analyser
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/r3labs/diff/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/snyk/driftctl/pkg/filter"
+	"github.com/snyk/driftctl/pkg/resource"
+)
+
+func TestAnalysis_MarshalJSON_UnmanagedAttributes(t *testing.T) {
+	tests := []struct {
+		name    string
+		options AnalyzerOptions
+		want    *resource.Attributes
+	}{
+		{
+			name:    "attributes are not written without deep mode",
+			options: AnalyzerOptions{},
+			want:    nil,
+		},
+		{
+			name:    "attributes are written in deep mode",
+			options: AnalyzerOptions{Deep: true},
+			want:    &resource.Attributes{"name": "driftctl", "path": "/"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := NewAnalysis(tt.options)
+			analysis.AddUnmanaged(&resource.Resource{
+				Id:    "driftctl",
+				Type:  "aws_iam_user",
+				Attrs: &resource.Attributes{"name": "driftctl", "path": "/"},
+			})
+
+			content, err := json.Marshal(analysis)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := &Analysis{}
+			if err := json.Unmarshal(content, got); err != nil {
+				t.Fatal(err)
+			}
+			if assert.Len(t, got.Unmanaged(), 1) {
+				assert.Equal(t, tt.want, got.Unmanaged()[0].Attributes())
+			}
+		})
+	}
+}
+
+func TestAnalysis_MarshalJSONVersion(t *testing.T) {
+	analysis := NewAnalysis(AnalyzerOptions{Deep: true})
+	analysis.Date = time.Date(2021, 10, 13, 10, 0, 0, 0, time.UTC)
+	analysis.Duration = 12 * time.Second
+	managed := &resource.Resource{
+		Id:     "bucket",
+		Type:   "aws_s3_bucket",
+		Attrs:  &resource.Attributes{"bucket": "bucket", "policy": `{"Version":"2012-10-17"}`},
+		Region: "us-east-1",
+		Source: &resource.TerraformStateSource{
+			State:  "tfstate://terraform.tfstate",
+			Module: "module.storage",
+			Name:   "bucket",
+			Key:    "[0]",
+		},
+	}
+	analysis.AddManaged(managed)
+	analysis.AddDeleted(&resource.Resource{
+		Id:     "deleted",
+		Type:   "aws_s3_bucket",
+		Attrs:  &resource.Attributes{"bucket": "deleted"},
+		Source: &resource.TerraformStateSource{State: "tfstate://terraform.tfstate", Name: "deleted", Key: `["a"]`},
+	})
+	analysis.AddUnmanaged(&resource.Resource{Id: "user", Type: "aws_iam_user", Attrs: &resource.Attributes{"name": "user"}})
+	analysis.AddDifference(Difference{
+		Res: managed,
+		Changelog: Changelog{
+			{Change: diff.Change{Type: diff.UPDATE, Path: []string{"policy"}, From: `{"Version":"2012-10-17"}`, To: "{}"}, JsonString: true},
+			{Change: diff.Change{Type: diff.CREATE, Path: []string{"tags", "Env"}, To: "prod"}, Computed: true},
+		},
+	})
+
+	t.Run("version 2 round-trips losslessly", func(t *testing.T) {
+		content, err := analysis.MarshalJSONVersion(SchemaVersion2)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := &Analysis{}
+		if err := json.Unmarshal(content, got); err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, analysis.Date.Equal(got.Date))
+		assert.Equal(t, analysis.Duration, got.Duration)
+		assert.Equal(t, analysis.Options(), got.Options())
+		assert.Equal(t, analysis.Summary(), got.Summary())
+		if assert.Len(t, got.Managed(), 1) {
+			assert.Equal(t, managed.Attributes(), got.Managed()[0].Attributes())
+			assert.Equal(t, "module.storage.aws_s3_bucket.bucket[0]", got.Managed()[0].Address())
+			assert.Equal(t, "us-east-1", got.Managed()[0].Region)
+		}
+		if assert.Len(t, got.Deleted(), 1) {
+			assert.Equal(t, `aws_s3_bucket.deleted["a"]`, got.Deleted()[0].Address())
+			assert.Equal(t, "tfstate://terraform.tfstate", got.Deleted()[0].Source.Source())
+		}
+		if assert.Len(t, got.Differences(), 1) {
+			assert.Equal(t, analysis.Differences()[0].Changelog, got.Differences()[0].Changelog)
+			assert.Equal(t, managed.Attributes(), got.Differences()[0].Res.Attributes())
+		}
+
+		// Serializing again what was read gives the same content
+		again, err := got.MarshalJSONVersion(SchemaVersion2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.JSONEq(t, string(content), string(again))
+	})
+
+	t.Run("version 1 is left unchanged", func(t *testing.T) {
+		content, err := json.Marshal(analysis)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.NotContains(t, string(content), "schema_version")
+		assert.NotContains(t, string(content), "address")
+		assert.NotContains(t, string(content), "json_string")
+
+		got := &Analysis{}
+		if err := json.Unmarshal(content, got); err != nil {
+			t.Fatal(err)
+		}
+		if assert.Len(t, got.Managed(), 1) {
+			assert.Nil(t, got.Managed()[0].Attributes())
+		}
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		_, err := analysis.MarshalJSONVersion(3)
+		assert.EqualError(t, err, "unsupported JSON schema version 3")
+
+		err = json.Unmarshal([]byte(`{"schema_version": 3}`), &Analysis{})
+		assert.EqualError(t, err, "unsupported JSON schema version 3")
+	})
+}
+
+func TestAnalysis_MarshalJSON_Duplicated(t *testing.T) {
+	analysis := NewAnalysis(AnalyzerOptions{})
+	bucket := &resource.Resource{Id: "bucket", Type: "aws_s3_bucket"}
+	claims := []*resource.Resource{
+		{Id: "bucket", Type: "aws_s3_bucket", Source: resource.NewTerraformStateSource("tfstate://first.tfstate", "", "bucket")},
+		{Id: "bucket", Type: "aws_s3_bucket", Source: resource.NewTerraformStateSource("tfstate://second.tfstate", "module.copy", "bucket")},
+	}
+	analysis.AddManaged(claims[0])
+	analysis.AddDuplicated(Duplicate{Res: bucket, Claims: claims})
+
+	content, err := json.Marshal(analysis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, string(content), `"address":"module.copy.aws_s3_bucket.bucket"`)
+
+	got := &Analysis{}
+	if err := json.Unmarshal(content, got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, analysis.Summary(), got.Summary())
+	if assert.Len(t, got.Duplicated(), 1) && assert.Len(t, got.Duplicated()[0].Claims, 2) {
+		assert.Equal(t, "bucket", got.Duplicated()[0].Res.ResourceId())
+		assert.Equal(t, "tfstate://second.tfstate", got.Duplicated()[0].Claims[1].Src().Source())
+		assert.Equal(t, "module.copy.aws_s3_bucket.bucket", got.Duplicated()[0].Claims[1].Address())
+	}
+}
+
+func TestAnalysis_MarshalJSON_IgnoreRules(t *testing.T) {
+	expires := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	analysis := NewAnalysis(AnalyzerOptions{})
+	analysis.SetIgnoreRules([]filter.IgnoreRule{
+		{Pattern: "aws_s3_bucket.legacy", Line: 1, Expires: &expires, Owner: "team-storage", Reason: "migration", Matches: 3},
+		{Pattern: "aws_iam_user.*", Line: 2},
+	})
+
+	// Rules are only recorded in history
+	content, err := json.Marshal(analysis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotContains(t, string(content), "ignore_rules")
+
+	content, err = analysis.MarshalJSONVersion(SchemaVersion2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotContains(t, string(content), "ignore_rules")
+}
+
+func TestAnalysis_MarshalJSON_ProviderName(t *testing.T) {
+	cases := []struct {
+		name      string
+		providers []Provider
+		contains  string
+		excludes  string
+	}{
+		{
+			name:      "single provider",
+			providers: []Provider{{Name: "aws", Version: "3.19.0"}},
+			contains:  `"provider_name":"aws","provider_version":"3.19.0"`,
+		},
+		{
+			name:      "several providers",
+			providers: []Provider{{Name: "aws", Version: "3.19.0"}, {Name: "github", Version: "4.4.0"}},
+			excludes:  `"provider_name"`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			analysis := NewAnalysis(AnalyzerOptions{})
+			analysis.Providers = c.providers
+
+			content, err := json.Marshal(analysis)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.contains != "" {
+				assert.Contains(t, string(content), c.contains)
+			}
+			if c.excludes != "" {
+				assert.NotContains(t, string(content), c.excludes)
+			}
+
+			got := &Analysis{}
+			if err := json.Unmarshal(content, got); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, c.providers, got.Providers)
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package analyser
 
 import (
+	"fmt"
+	"reflect"
+
 	"github.com/r3labs/diff/v2"
 	"github.com/snyk/driftctl/pkg/filter"
 	resourceaws "github.com/snyk/driftctl/pkg/resource/aws"
@@ -23,6 +26,28 @@ func (u *UnmanagedSecurityGroupRulesAlert) ShouldIgnoreResource() bool {
 	return false
 }
 
+// UnidentifiableResourceAlert is raised for resources declared without identifier that cannot be matched before apply
+type UnidentifiableResourceAlert struct {
+	res *resource.Resource
+}
+
+func newUnidentifiableResourceAlert(res *resource.Resource) *UnidentifiableResourceAlert {
+	return &UnidentifiableResourceAlert{res}
+}
+
+func (u *UnidentifiableResourceAlert) Message() string {
+	return fmt.Sprintf(
+		"Unable to match %s from %s, its identifier is only known after apply and %s resources have no identifying attributes. The matching resource may be reported as unmanaged",
+		u.res.Address(),
+		u.res.Src().Source(),
+		u.res.ResourceType(),
+	)
+}
+
+func (u *UnidentifiableResourceAlert) ShouldIgnoreResource() bool {
+	return false
+}
+
 type ComputedDiffAlert struct{}
 
 func NewComputedDiffAlert() *ComputedDiffAlert {
@@ -39,6 +64,10 @@ func (c *ComputedDiffAlert) ShouldIgnoreResource() bool {
 
 type AnalyzerOptions struct {
 	Deep bool
+	// Changes of a lower severity are not reported
+	MinSeverity Severity
+	// Rules classifying changes on top of builtin ones, only builtin rules are used when nil
+	SeverityRules *SeverityRules
 }
 
 type Analyzer struct {
@@ -56,28 +85,61 @@ func (a Analyzer) Analyze(remoteResources, resourcesFromState []*resource.Resour
 
 	// Iterate on remote resources and filter ignored resources
 	filteredRemoteResource := make([]*resource.Resource, 0, len(remoteResources))
+	ignoredRemoteResource := make([]*resource.Resource, 0)
 	for _, remoteRes := range remoteResources {
-		if a.filter.IsResourceIgnored(remoteRes) || a.alerter.IsResourceIgnored(remoteRes) {
+		if a.filter.IsResourceIgnored(remoteRes) {
+			ignoredRemoteResource = append(ignoredRemoteResource, remoteRes)
+			continue
+		}
+		if a.alerter.IsResourceIgnored(remoteRes) {
 			continue
 		}
 		filteredRemoteResource = append(filteredRemoteResource, remoteRes)
 	}
 
+	index := newRemoteResourceIndex(filteredRemoteResource)
+	// Rules on attributes may ignore a remote resource but not its IaC counterpart, or the other way around
+	ignoredIndex := newRemoteResourceIndex(ignoredRemoteResource)
+
+	// Remote resources managed by more than one IaC resource
+	duplicates := make(map[*resource.Resource]*Duplicate)
+	duplicated := make([]*resource.Resource, 0)
+
 	haveComputedDiff := false
 	for _, stateRes := range resourcesFromState {
-		i, remoteRes, found := findCorrespondingRes(filteredRemoteResource, stateRes)
-
-		if a.filter.IsResourceIgnored(stateRes) || a.alerter.IsResourceIgnored(stateRes) {
+		if a.alerter.IsResourceIgnored(stateRes) {
+			continue
+		}
+		if a.filter.IsResourceIgnored(stateRes) {
+			// Do not report the remote counterpart as unmanaged
+			index.Match(stateRes)
+			continue
+		}
+		// They would be reported as missing while their counterpart is unmanaged
+		if stateRes.IsUnidentifiable() {
+			a.alerter.SendAlert(stateRes.ResourceType(), newUnidentifiableResourceAlert(stateRes))
 			continue
 		}
 
+		// Matched resources are marked as managed, so it will remain only unmanaged ones
+		remoteRes, found := index.Match(stateRes)
 		if !found {
+			// The resource exists but another IaC resource already manages it
+			if remoteRes, managedBy, claimed := index.Matched(stateRes); claimed {
+				if _, exist := duplicates[remoteRes]; !exist {
+					duplicates[remoteRes] = &Duplicate{Res: remoteRes, Claims: []*resource.Resource{managedBy}}
+					duplicated = append(duplicated, remoteRes)
+				}
+				duplicates[remoteRes].Claims = append(duplicates[remoteRes].Claims, stateRes)
+				continue
+			}
+			if _, ignored := ignoredIndex.Match(stateRes); ignored {
+				continue
+			}
 			analysis.AddDeleted(stateRes)
 			continue
 		}
 
-		// Remove managed resources, so it will remain only unmanaged ones
-		filteredRemoteResource = removeResourceByIndex(i, filteredRemoteResource)
 		analysis.AddManaged(stateRes)
 
 		// Stop there if we are not in deep mode, we do not want to compute diffs
@@ -97,6 +159,8 @@ func (a Analyzer) Analyze(remoteResources, resourcesFromState []*resource.Resour
 			continue
 		}
 
+		_, fromConfig := stateRes.Src().(*resource.TerraformConfigSource)
+
 		changelog := make([]Change, 0, len(delta))
 		for _, change := range delta {
 			if a.filter.IsFieldIgnored(stateRes, change.Path) {
@@ -108,6 +172,23 @@ func (a Analyzer) Analyze(remoteResources, resourcesFromState []*resource.Resour
 				c.Computed = resSchema.IsComputedField(c.Path)
 				c.JsonString = resSchema.IsJsonStringField(c.Path)
 			}
+			// Attributes left unset in configuration get zero values or provider defaults, the latter are reported
+			// as computed changes since driftctl does not know provider defaults
+			defaulted := false
+			if fromConfig && change.From == nil {
+				if c.Computed || isZeroValue(change.To) {
+					continue
+				}
+				c.Computed = true
+				defaulted = true
+			}
+			c.Severity = a.options.SeverityRules.Classify(stateRes.ResourceType(), c)
+			if defaulted {
+				c.Severity = SeverityInfo
+			}
+			if c.Severity < a.options.MinSeverity {
+				continue
+			}
 			if c.Computed {
 				haveComputedDiff = true
 			}
@@ -121,7 +202,13 @@ func (a Analyzer) Analyze(remoteResources, resourcesFromState []*resource.Resour
 		}
 	}
 
-	if a.hasUnmanagedSecurityGroupRules(filteredRemoteResource) {
+	for _, remoteRes := range duplicated {
+		analysis.AddDuplicated(*duplicates[remoteRes])
+	}
+
+	unmanaged := index.Unmatched()
+
+	if a.hasUnmanagedSecurityGroupRules(unmanaged) {
 		a.alerter.SendAlert("", newUnmanagedSecurityGroupRulesAlert())
 	}
 
@@ -130,7 +217,7 @@ func (a Analyzer) Analyze(remoteResources, resourcesFromState []*resource.Resour
 	}
 
 	// Add remaining unmanaged resources
-	analysis.AddUnmanaged(filteredRemoteResource...)
+	analysis.AddUnmanaged(unmanaged...)
 
 	// Sort resources by Terraform Id
 	// The purpose is to have a predictable output
@@ -141,20 +228,18 @@ func (a Analyzer) Analyze(remoteResources, resourcesFromState []*resource.Resour
 	return analysis, nil
 }
 
-func findCorrespondingRes(resources []*resource.Resource, res *resource.Resource) (int, *resource.Resource, bool) {
-	for i, r := range resources {
-		if res.Equal(r) {
-			return i, r, true
-		}
+// isZeroValue returns true for values Terraform gives to unset attributes without default, e.g. false, 0, "" or
+// empty collections
+func isZeroValue(value interface{}) bool {
+	if value == nil {
+		return true
 	}
-	return -1, nil, false
-}
-
-func removeResourceByIndex(i int, resources []*resource.Resource) []*resource.Resource {
-	if i == len(resources)-1 {
-		return resources[:len(resources)-1]
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
 	}
-	return append(resources[:i], resources[i+1:]...)
+	return v.IsZero()
 }
 
 // hasUnmanagedSecurityGroupRules returns true if we find at least one unmanaged
@@ -3,6 +3,8 @@ package analyser
 import (
 	"encoding/json"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/snyk/driftctl/pkg/filter"
@@ -18,6 +20,7 @@ import (
 	"github.com/snyk/driftctl/pkg/resource"
 	"github.com/snyk/driftctl/pkg/resource/aws"
 
+	"github.com/hashicorp/terraform/configs/configschema"
 	"github.com/r3labs/diff/v2"
 )
 
@@ -1100,6 +1103,183 @@ func TestAnalyze(t *testing.T) {
 	}
 }
 
+func TestAnalyze_ConfigurationUnsetAttributes(t *testing.T) {
+	schema := &resource.Schema{
+		Flags: resource.FlagDeepMode,
+		Attributes: map[string]resource.AttributeSchema{
+			"arn":                        {ConfigSchema: configschema.Attribute{Computed: true}},
+			"policy":                     {ConfigSchema: configschema.Attribute{Optional: true, Computed: true}},
+			"fifo_queue":                 {ConfigSchema: configschema.Attribute{Optional: true}},
+			"delay_seconds":              {ConfigSchema: configschema.Attribute{Optional: true}},
+			"tags":                       {ConfigSchema: configschema.Attribute{Optional: true}},
+			"visibility_timeout_seconds": {ConfigSchema: configschema.Attribute{Optional: true}},
+		},
+	}
+	remote := []*resource.Resource{
+		{
+			Id:   "https://sqs.us-east-1.amazonaws.com/123456789012/jobs",
+			Type: "aws_sqs_queue",
+			Sch:  schema,
+			Attrs: &resource.Attributes{
+				"name":                       "jobs",
+				"arn":                        "arn:aws:sqs:us-east-1:123456789012:jobs",
+				"policy":                     "{}",
+				"fifo_queue":                 false,
+				"delay_seconds":              float64(0),
+				"tags":                       map[string]interface{}{},
+				"visibility_timeout_seconds": float64(30),
+			},
+		},
+	}
+	state := []*resource.Resource{
+		{
+			Id:     "https://sqs.us-east-1.amazonaws.com/123456789012/jobs",
+			Type:   "aws_sqs_queue",
+			Sch:    schema,
+			Source: resource.NewTerraformConfigSource("tfconfig://.", "", "jobs"),
+			Attrs:  &resource.Attributes{"name": "jobs"},
+		},
+	}
+
+	analyzer := NewAnalyzer(alerter.NewAlerter(), AnalyzerOptions{Deep: true}, noopFilter{})
+	analysis, err := analyzer.Analyze(remote, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Computed attributes and zero values are not drifts, other values may be provider defaults
+	if assert.Len(t, analysis.Differences(), 1) {
+		changelog := analysis.Differences()[0].Changelog
+		if assert.Len(t, changelog, 1) {
+			assert.Equal(t, []string{"visibility_timeout_seconds"}, changelog[0].Path)
+			assert.Nil(t, changelog[0].From)
+			assert.Equal(t, float64(30), changelog[0].To)
+			assert.True(t, changelog[0].Computed)
+			assert.Equal(t, SeverityInfo, changelog[0].Severity)
+		}
+	}
+}
+
+func TestAnalyze_UnidentifiableResources(t *testing.T) {
+	remote := []*resource.Resource{
+		{Id: "i-0123456789", Type: "aws_instance", Attrs: &resource.Attributes{"instance_type": "t3.micro"}},
+	}
+	state := []*resource.Resource{
+		{Type: "aws_instance", Source: resource.NewTerraformConfigSource("tfconfig://.", "", "web"), Attrs: &resource.Attributes{"instance_type": "t3.micro"}},
+	}
+
+	analyzer := NewAnalyzer(alerter.NewAlerter(), AnalyzerOptions{}, noopFilter{})
+	analysis, err := analyzer.Analyze(remote, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Empty(t, analysis.Deleted())
+	assert.Equal(t, remote, analysis.Unmanaged())
+	if assert.Len(t, analysis.Alerts()["aws_instance"], 1) {
+		assert.Equal(
+			t,
+			"Unable to match aws_instance.web from tfconfig://., its identifier is only known after apply and aws_instance resources have no identifying attributes. The matching resource may be reported as unmanaged",
+			analysis.Alerts()["aws_instance"][0].Message(),
+		)
+	}
+}
+
+func TestAnalyze_Duplicated(t *testing.T) {
+	remote := []*resource.Resource{
+		{Id: "bucket", Type: "aws_s3_bucket"},
+		{Id: "user", Type: "aws_iam_user"},
+	}
+	state := []*resource.Resource{
+		{Id: "bucket", Type: "aws_s3_bucket", Source: resource.NewTerraformStateSource("tfstate://first.tfstate", "", "bucket")},
+		{Id: "user", Type: "aws_iam_user", Source: resource.NewTerraformStateSource("tfstate://first.tfstate", "", "user")},
+		{Id: "bucket", Type: "aws_s3_bucket", Source: resource.NewTerraformStateSource("tfstate://second.tfstate", "module.copy", "bucket")},
+		{Id: "bucket", Type: "aws_s3_bucket", Source: resource.NewTerraformStateSource("tfstate://third.tfstate", "", "bucket")},
+		{Id: "role", Type: "aws_iam_role", Source: resource.NewTerraformStateSource("tfstate://second.tfstate", "", "role")},
+	}
+
+	analyzer := NewAnalyzer(alerter.NewAlerter(), AnalyzerOptions{}, noopFilter{})
+	analysis, err := analyzer.Analyze(remote, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []Duplicate{{Res: remote[0], Claims: []*resource.Resource{state[0], state[2], state[3]}}}, analysis.Duplicated())
+	assert.Equal(t, Summary{
+		TotalResources:  3,
+		TotalDeleted:    1,
+		TotalManaged:    2,
+		TotalDuplicated: 1,
+	}, analysis.Summary())
+	assert.False(t, analysis.IsSync())
+}
+
+func TestAnalyze_AttributeIgnoreRules(t *testing.T) {
+	driftignore := filepath.Join(t.TempDir(), ".driftignore")
+	content := "@attr tags.managed-by == karpenter\n@attr aws_iam_role path ^= /aws-reserved/\n"
+	if err := os.WriteFile(driftignore, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	karpenter := &resource.Attributes{"tags": map[string]interface{}{"managed-by": "karpenter"}}
+
+	remote := []*resource.Resource{
+		{Id: "bucket", Type: "aws_s3_bucket", Attrs: &resource.Attributes{}},
+		{Id: "karpenter-bucket", Type: "aws_s3_bucket", Attrs: karpenter},
+		{Id: "sso", Type: "aws_iam_role", Attrs: &resource.Attributes{}},
+		{Id: "node", Type: "aws_instance", Attrs: karpenter},
+	}
+	state := []*resource.Resource{
+		{Id: "bucket", Type: "aws_s3_bucket", Attrs: &resource.Attributes{}},
+		// Only attributes of the IaC resource match
+		{Id: "sso", Type: "aws_iam_role", Attrs: &resource.Attributes{"path": "/aws-reserved/sso.amazonaws.com/"}},
+		// Only attributes of the remote resource match
+		{Id: "node", Type: "aws_instance", Attrs: &resource.Attributes{}},
+	}
+
+	analyzer := NewAnalyzer(alerter.NewAlerter(), AnalyzerOptions{}, filter.NewDriftIgnore(driftignore))
+	analysis, err := analyzer.Analyze(remote, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []*resource.Resource{state[0]}, analysis.Managed())
+	assert.Empty(t, analysis.Unmanaged())
+	assert.Empty(t, analysis.Deleted())
+	assert.True(t, analysis.IsSync())
+}
+
+func TestAnalyze_PatternIgnoreRulesOnOneSide(t *testing.T) {
+	driftignore := filepath.Join(t.TempDir(), ".driftignore")
+	// Resources declared in configuration have no identifier until they are created, patterns on identifiers only
+	// match one side of the pair
+	content := "aws_sqs_queue.\naws_sns_topic.arn:aws:sns:us-east-1:123456789012:legacy\n"
+	if err := os.WriteFile(driftignore, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	remote := []*resource.Resource{
+		{Id: "https://sqs.us-east-1.amazonaws.com/123456789012/jobs", Type: "aws_sqs_queue", Attrs: &resource.Attributes{"name": "jobs"}},
+		{Id: "arn:aws:sns:us-east-1:123456789012:legacy", Type: "aws_sns_topic", Attrs: &resource.Attributes{"name": "legacy"}},
+	}
+	state := []*resource.Resource{
+		// Only the IaC resource is ignored, its remote counterpart is not unmanaged
+		{Type: "aws_sqs_queue", Source: resource.NewTerraformConfigSource("tfconfig://.", "", "jobs"), Attrs: &resource.Attributes{"name": "jobs"}},
+		// Only the remote resource is ignored, the IaC one is not missing
+		{Type: "aws_sns_topic", Source: resource.NewTerraformConfigSource("tfconfig://.", "", "legacy"), Attrs: &resource.Attributes{"name": "legacy"}},
+	}
+
+	analyzer := NewAnalyzer(alerter.NewAlerter(), AnalyzerOptions{}, filter.NewDriftIgnore(driftignore))
+	analysis, err := analyzer.Analyze(remote, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Empty(t, analysis.Managed())
+	assert.Empty(t, analysis.Unmanaged())
+	assert.Empty(t, analysis.Deleted())
+	assert.True(t, analysis.IsSync())
+}
+
 func addSchemaToRes(res *resource.Resource, repo resource.SchemaRepositoryInterface) {
 	schema, _ := repo.GetSchema(res.ResourceType())
 	res.Sch = schema
@@ -1165,8 +1345,7 @@ func TestAnalysis_MarshalJSON(t *testing.T) {
 			&alerter.FakeAlert{Msg: "This is an alert"},
 		},
 	})
-	analysis.ProviderName = "AWS"
-	analysis.ProviderVersion = "2.18.5"
+	analysis.Providers = []Provider{{Name: "aws", Version: "2.18.5"}}
 
 	got, err := json.MarshalIndent(analysis, "", "\t")
 	if err != nil {
@@ -1249,8 +1428,7 @@ func TestAnalysis_UnmarshalJSON(t *testing.T) {
 				},
 			},
 		},
-		ProviderName:    "AWS",
-		ProviderVersion: "2.18.5",
+		Providers: []Provider{{Name: "AWS", Version: "2.18.5"}},
 	}
 
 	got := Analysis{}
@@ -1272,3 +1450,48 @@ func TestAnalysis_UnmarshalJSON(t *testing.T) {
 	assert.Len(t, got.alerts, 1)
 	assert.Equal(t, got.alerts["aws_iam_access_key"][0].Message(), "This is an alert")
 }
+
+func TestAnalysis_ProviderSummaries(t *testing.T) {
+	analysis := Analysis{}
+	analysis.AddManaged(
+		&resource.Resource{Id: "bucket", Type: "aws_s3_bucket"},
+		&resource.Resource{Id: "repo", Type: "github_repository"},
+	)
+	analysis.AddUnmanaged(
+		&resource.Resource{Id: "role", Type: "aws_iam_role"},
+		&resource.Resource{Id: "user", Type: "aws_iam_user"},
+	)
+	analysis.AddDeleted(
+		&resource.Resource{Id: "team", Type: "github_team"},
+	)
+	analysis.AddDifference(Difference{Res: &resource.Resource{Id: "repo", Type: "github_repository"}})
+
+	assert.Nil(t, analysis.ProviderSummaries())
+
+	analysis.Providers = []Provider{
+		{Name: "aws", Version: "3.19.0"},
+		{Name: "github", Version: "4.4.0"},
+	}
+	assert.Equal(t, []ProviderSummary{
+		{
+			Provider: Provider{Name: "aws", Version: "3.19.0"},
+			Coverage: 33,
+			Summary: Summary{
+				TotalResources: 3,
+				TotalManaged:   1,
+				TotalUnmanaged: 2,
+			},
+		},
+		{
+			Provider: Provider{Name: "github", Version: "4.4.0"},
+			Coverage: 50,
+			Summary: Summary{
+				TotalResources: 2,
+				TotalDrifted:   1,
+				TotalManaged:   1,
+				TotalDeleted:   1,
+			},
+		},
+	}, analysis.ProviderSummaries())
+	assert.Equal(t, 40, analysis.Coverage())
+}
@@ -0,0 +1,96 @@
+package analyser
+
+import (
+	"github.com/snyk/driftctl/pkg/resource"
+)
+
+type remoteResourceKey struct {
+	Type string
+	Id   string
+}
+
+// remoteResourceIndex finds remote resources corresponding to state ones without scanning every remote resource.
+// Resources are indexed by type and id, resources whose schema defines a DiscriminantFunc are kept in a per
+// type fallback bucket so that the discriminant function is still the one deciding whether they match.
+type remoteResourceIndex struct {
+	resources []*resource.Resource
+	// IaC resource each remote resource is matched with, nil when not matched yet
+	matchedBy []*resource.Resource
+	byKey     map[remoteResourceKey][]int
+	// Resources of types defining a DiscriminantFunc
+	discriminated map[string][]int
+	// Every resource, used for declared resources without id which are matched on their identifying attributes
+	byType map[string][]int
+}
+
+func newRemoteResourceIndex(resources []*resource.Resource) *remoteResourceIndex {
+	index := &remoteResourceIndex{
+		resources:     resources,
+		matchedBy:     make([]*resource.Resource, len(resources)),
+		byKey:         make(map[remoteResourceKey][]int, len(resources)),
+		discriminated: make(map[string][]int),
+		byType:        make(map[string][]int),
+	}
+	for i, res := range resources {
+		index.byType[res.ResourceType()] = append(index.byType[res.ResourceType()], i)
+		if res.Schema() != nil && res.Schema().DiscriminantFunc != nil {
+			index.discriminated[res.ResourceType()] = append(index.discriminated[res.ResourceType()], i)
+			continue
+		}
+		key := remoteResourceKey{res.ResourceType(), res.ResourceId()}
+		index.byKey[key] = append(index.byKey[key], i)
+	}
+	return index
+}
+
+// Match returns the first remote resource not matched yet that is equal to the given one, and marks it as matched
+func (r *remoteResourceIndex) Match(res *resource.Resource) (*resource.Resource, bool) {
+	i := r.lookup(res, false)
+	if i == -1 {
+		return nil, false
+	}
+	r.matchedBy[i] = res
+	return r.resources[i], true
+}
+
+// Matched returns the first remote resource already matched that is equal to the given one,
+// with the resource it was matched with
+func (r *remoteResourceIndex) Matched(res *resource.Resource) (remote, matchedBy *resource.Resource, found bool) {
+	i := r.lookup(res, true)
+	if i == -1 {
+		return nil, nil, false
+	}
+	return r.resources[i], r.matchedBy[i], true
+}
+
+func (r *remoteResourceIndex) lookup(res *resource.Resource, matched bool) int {
+	if res.ResourceId() == "" {
+		return r.find(res, r.byType[res.ResourceType()], matched)
+	}
+	i := r.find(res, r.byKey[remoteResourceKey{res.ResourceType(), res.ResourceId()}], matched)
+	// Keep the lowest index to match the same resource than a sequential scan would
+	if j := r.find(res, r.discriminated[res.ResourceType()], matched); j != -1 && (i == -1 || j < i) {
+		i = j
+	}
+	return i
+}
+
+func (r *remoteResourceIndex) find(res *resource.Resource, candidates []int, matched bool) int {
+	for _, i := range candidates {
+		if (r.matchedBy[i] != nil) == matched && res.Equal(r.resources[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Unmatched returns remote resources not matched yet, in their original order
+func (r *remoteResourceIndex) Unmatched() []*resource.Resource {
+	unmatched := make([]*resource.Resource, 0, len(r.resources))
+	for i, res := range r.resources {
+		if r.matchedBy[i] == nil {
+			unmatched = append(unmatched, res)
+		}
+	}
+	return unmatched
+}
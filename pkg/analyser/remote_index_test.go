@@ -0,0 +1,188 @@
+package analyser
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/snyk/driftctl/pkg/alerter"
+	"github.com/snyk/driftctl/pkg/resource"
+)
+
+func TestRemoteResourceIndex_Match(t *testing.T) {
+	discriminated := &resource.Schema{
+		DiscriminantFunc: func(self, target *resource.Resource) bool {
+			return (*self.Attrs)["dimension"] == (*target.Attrs)["dimension"]
+		},
+	}
+
+	cases := []struct {
+		name              string
+		remote            []*resource.Resource
+		state             []*resource.Resource
+		expectedMatches   []int // index of the matched remote resource, -1 when not found
+		expectedUnmatched []int
+	}{
+		{
+			name: "match by type and id",
+			remote: []*resource.Resource{
+				{Id: "foo", Type: "aws_s3_bucket"},
+				{Id: "foo", Type: "aws_iam_user"},
+				{Id: "bar", Type: "aws_s3_bucket"},
+			},
+			state: []*resource.Resource{
+				{Id: "foo", Type: "aws_iam_user"},
+				{Id: "baz", Type: "aws_s3_bucket"},
+				{Id: "bar", Type: "aws_s3_bucket"},
+			},
+			expectedMatches:   []int{1, -1, 2},
+			expectedUnmatched: []int{0},
+		},
+		{
+			name: "resources are matched only once",
+			remote: []*resource.Resource{
+				{Id: "foo", Type: "aws_s3_bucket"},
+			},
+			state: []*resource.Resource{
+				{Id: "foo", Type: "aws_s3_bucket"},
+				{Id: "foo", Type: "aws_s3_bucket"},
+			},
+			expectedMatches:   []int{0, -1},
+			expectedUnmatched: []int{},
+		},
+		{
+			name: "match resources of the same id in different accounts",
+			remote: []*resource.Resource{
+				{Id: "foo", Type: "aws_s3_bucket", Account: "123"},
+				{Id: "foo", Type: "aws_s3_bucket", Account: "456"},
+			},
+			state: []*resource.Resource{
+				{Id: "foo", Type: "aws_s3_bucket", Account: "456"},
+			},
+			expectedMatches:   []int{1},
+			expectedUnmatched: []int{0},
+		},
+		{
+			name: "match resources with a discriminant function",
+			remote: []*resource.Resource{
+				{Id: "foo", Type: "aws_appautoscaling_target", Sch: discriminated, Attrs: &resource.Attributes{"dimension": "read"}},
+				{Id: "foo", Type: "aws_appautoscaling_target", Sch: discriminated, Attrs: &resource.Attributes{"dimension": "write"}},
+			},
+			state: []*resource.Resource{
+				{Id: "foo", Type: "aws_appautoscaling_target", Sch: discriminated, Attrs: &resource.Attributes{"dimension": "write"}},
+			},
+			expectedMatches:   []int{1},
+			expectedUnmatched: []int{0},
+		},
+		{
+			name: "match declared resources without id on their identifying attributes",
+			remote: []*resource.Resource{
+				{Id: "foo", Type: "aws_sqs_queue", Attrs: &resource.Attributes{"name": "foo"}},
+				{Id: "bar", Type: "aws_sqs_queue", Attrs: &resource.Attributes{"name": "bar"}},
+			},
+			state: []*resource.Resource{
+				{Type: "aws_sqs_queue", Source: resource.NewTerraformConfigSource("tfconfig://.", "", "bar"), Attrs: &resource.Attributes{"name": "bar"}},
+			},
+			expectedMatches:   []int{1},
+			expectedUnmatched: []int{0},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			index := newRemoteResourceIndex(c.remote)
+
+			for i, res := range c.state {
+				match, found := index.Match(res)
+				if c.expectedMatches[i] == -1 {
+					assert.False(t, found)
+					continue
+				}
+				assert.True(t, found)
+				assert.Same(t, c.remote[c.expectedMatches[i]], match)
+			}
+
+			expectedUnmatched := make([]*resource.Resource, 0, len(c.expectedUnmatched))
+			for _, i := range c.expectedUnmatched {
+				expectedUnmatched = append(expectedUnmatched, c.remote[i])
+			}
+			assert.Equal(t, expectedUnmatched, index.Unmatched())
+		})
+	}
+}
+
+func TestRemoteResourceIndex_Matched(t *testing.T) {
+	remote := []*resource.Resource{
+		{Id: "foo", Type: "aws_s3_bucket"},
+		{Id: "bar", Type: "aws_s3_bucket"},
+	}
+	managedBy := &resource.Resource{Id: "foo", Type: "aws_s3_bucket"}
+	index := newRemoteResourceIndex(remote)
+
+	_, _, found := index.Matched(managedBy)
+	assert.False(t, found)
+
+	_, found = index.Match(managedBy)
+	assert.True(t, found)
+
+	match, by, found := index.Matched(&resource.Resource{Id: "foo", Type: "aws_s3_bucket"})
+	assert.True(t, found)
+	assert.Same(t, remote[0], match)
+	assert.Same(t, managedBy, by)
+
+	_, _, found = index.Matched(&resource.Resource{Id: "bar", Type: "aws_s3_bucket"})
+	assert.False(t, found)
+}
+
+// noopFilter ignores nothing, it avoids measuring mock calls in benchmarks
+type noopFilter struct{}
+
+func (noopFilter) IsTypeIgnored(resource.ResourceType) bool         { return false }
+func (noopFilter) IsResourceIgnored(*resource.Resource) bool        { return false }
+func (noopFilter) IsFieldIgnored(*resource.Resource, []string) bool { return false }
+
+// benchmarkAnalyze analyzes an estate of the given size where 80% of the resources are managed,
+// half of the remaining ones being unmanaged and the other half missing
+func benchmarkAnalyze(b *testing.B, size int) {
+	types := []string{"aws_s3_bucket", "aws_iam_user", "aws_instance", "aws_route53_record", "aws_security_group"}
+	remote := make([]*resource.Resource, 0, size)
+	state := make([]*resource.Resource, 0, size)
+	for i := 0; i < size; i++ {
+		res := &resource.Resource{
+			Id:   fmt.Sprintf("resource-%d", i),
+			Type: types[i%len(types)],
+		}
+		switch i % 10 {
+		case 0:
+			remote = append(remote, res)
+		case 1:
+			state = append(state, res)
+		default:
+			remote = append(remote, res)
+			state = append(state, &resource.Resource{Id: res.Id, Type: res.Type})
+		}
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		// Alerts can only be retrieved once from an alerter
+		b.StopTimer()
+		analyzer := NewAnalyzer(alerter.NewAlerter(), AnalyzerOptions{}, noopFilter{})
+		b.StartTimer()
+		if _, err := analyzer.Analyze(remote, state); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAnalyze_10k(b *testing.B) {
+	benchmarkAnalyze(b, 10000)
+}
+
+func BenchmarkAnalyze_100k(b *testing.B) {
+	benchmarkAnalyze(b, 100000)
+}
+
+func BenchmarkAnalyze_1M(b *testing.B) {
+	benchmarkAnalyze(b, 1000000)
+}
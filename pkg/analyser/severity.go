@@ -0,0 +1,192 @@
+package analyser
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// Severity ranks changes, the zero value is kept for changes of analysis written before severities were computed
+type Severity int
+
+const (
+	SeverityNone Severity = iota
+	SeverityInfo
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+var severityNames = []string{"", "info", "low", "medium", "high", "critical"}
+
+// SeverityNames lists accepted severities, from the lowest to the highest
+var SeverityNames = severityNames[1:]
+
+// DefaultSeverity is given to changes matched by no rule, computed ones are given SeverityInfo
+const DefaultSeverity = SeverityLow
+
+func (s Severity) String() string {
+	if s < SeverityNone || int(s) >= len(severityNames) {
+		return ""
+	}
+	return severityNames[s]
+}
+
+func ParseSeverity(name string) (Severity, error) {
+	for i, n := range SeverityNames {
+		if n == strings.ToLower(name) {
+			return Severity(i + 1), nil
+		}
+	}
+	return SeverityNone, errors.Errorf("unknown severity '%s', must be one of %s", name, strings.Join(SeverityNames, ", "))
+}
+
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+func (s *Severity) UnmarshalJSON(bytes []byte) error {
+	var name string
+	if err := json.Unmarshal(bytes, &name); err != nil {
+		return err
+	}
+	if name == "" {
+		*s = SeverityNone
+		return nil
+	}
+	severity, err := ParseSeverity(name)
+	if err != nil {
+		return err
+	}
+	*s = severity
+	return nil
+}
+
+// SeverityRule gives a severity to changes of matching resource types and attributes
+type SeverityRule struct {
+	// Type is a glob matched against resource types, every type matches when empty
+	Type string `json:"type"`
+	// Path is a glob matched against dotted attribute paths segment by segment, changes of nested attributes match too
+	Path     string   `json:"path"`
+	Severity Severity `json:"severity"`
+}
+
+func (r SeverityRule) matches(ty string, changePath []string) bool {
+	if r.Type != "" {
+		if ok, _ := path.Match(r.Type, ty); !ok {
+			return false
+		}
+	}
+	if r.Path == "" {
+		return true
+	}
+	segments := strings.Split(r.Path, ".")
+	if len(segments) > len(changePath) {
+		return false
+	}
+	for i, segment := range segments {
+		if ok, _ := path.Match(segment, changePath[i]); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// BuiltinSeverityRules flag changes on attributes granting access to resources
+var BuiltinSeverityRules = []SeverityRule{
+	{Type: "aws_security_group", Path: "ingress.*.cidr_blocks", Severity: SeverityCritical},
+	{Type: "aws_security_group", Path: "ingress.*.ipv6_cidr_blocks", Severity: SeverityCritical},
+	{Type: "aws_security_group", Path: "ingress", Severity: SeverityHigh},
+	{Type: "aws_security_group", Path: "egress", Severity: SeverityMedium},
+	{Type: "aws_security_group_rule", Path: "cidr_blocks", Severity: SeverityCritical},
+	{Type: "aws_security_group_rule", Path: "ipv6_cidr_blocks", Severity: SeverityCritical},
+	{Type: "aws_s3_bucket", Path: "acl", Severity: SeverityHigh},
+	{Type: "aws_s3_bucket", Path: "grant", Severity: SeverityHigh},
+	{Type: "aws_s3_bucket", Path: "policy", Severity: SeverityHigh},
+	{Type: "aws_s3_bucket_policy", Path: "policy", Severity: SeverityHigh},
+	{Type: "aws_s3_bucket_public_access_block", Severity: SeverityHigh},
+	{Type: "aws_iam_policy", Path: "policy", Severity: SeverityHigh},
+	{Type: "aws_iam_*_policy", Path: "policy", Severity: SeverityHigh},
+	{Type: "aws_iam_role", Path: "assume_role_policy", Severity: SeverityHigh},
+	{Type: "aws_kms_key", Path: "policy", Severity: SeverityHigh},
+	{Type: "google_compute_firewall", Path: "source_ranges", Severity: SeverityCritical},
+}
+
+// SeverityRules classifies changes, user rules take precedence over builtin ones
+type SeverityRules struct {
+	rules []SeverityRule
+}
+
+func NewSeverityRules(rules ...SeverityRule) *SeverityRules {
+	return &SeverityRules{rules}
+}
+
+type severityRulesFile struct {
+	Rules []SeverityRule `json:"rules"`
+}
+
+// ReadSeverityRules reads user rules from a YAML or JSON file
+func ReadSeverityRules(filePath string) (*SeverityRules, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	file := severityRulesFile{}
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse severity rules %s", filePath)
+	}
+	for i, rule := range file.Rules {
+		if rule.Severity == SeverityNone {
+			return nil, errors.Errorf("severity rule %d of %s has no severity", i+1, filePath)
+		}
+		if _, err := path.Match(rule.Type, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid type of severity rule %d of %s", i+1, filePath)
+		}
+		if _, err := path.Match(rule.Path, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid path of severity rule %d of %s", i+1, filePath)
+		}
+	}
+	return NewSeverityRules(file.Rules...), nil
+}
+
+// Classify returns the highest severity of matching user rules, fallback on builtin rules
+func (r *SeverityRules) Classify(ty string, change Change) Severity {
+	if r != nil {
+		if severity := classify(r.rules, ty, change.Path); severity != SeverityNone {
+			return severity
+		}
+	}
+	if severity := classify(BuiltinSeverityRules, ty, change.Path); severity != SeverityNone {
+		return severity
+	}
+	if change.Computed {
+		return SeverityInfo
+	}
+	return DefaultSeverity
+}
+
+func classify(rules []SeverityRule, ty string, changePath []string) Severity {
+	severity := SeverityNone
+	for _, rule := range rules {
+		if rule.Severity > severity && rule.matches(ty, changePath) {
+			severity = rule.Severity
+		}
+	}
+	return severity
+}
+
+// Severity is the highest severity of the changes of the difference
+func (d Difference) Severity() Severity {
+	severity := SeverityNone
+	for _, change := range d.Changelog {
+		if change.Severity > severity {
+			severity = change.Severity
+		}
+	}
+	return severity
+}
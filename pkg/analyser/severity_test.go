@@ -0,0 +1,216 @@
+package analyser
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/r3labs/diff/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/snyk/driftctl/pkg/alerter"
+	"github.com/snyk/driftctl/pkg/resource"
+)
+
+func TestSeverityRules_Classify(t *testing.T) {
+	userRules := NewSeverityRules(
+		SeverityRule{Type: "aws_instance", Path: "tags.*", Severity: SeverityMedium},
+		SeverityRule{Type: "aws_s3_bucket", Path: "acl", Severity: SeverityLow},
+		SeverityRule{Path: "tags.Owner", Severity: SeverityHigh},
+	)
+
+	cases := []struct {
+		name     string
+		rules    *SeverityRules
+		ty       string
+		change   Change
+		expected Severity
+	}{
+		{
+			name:     "default severity",
+			ty:       "aws_instance",
+			change:   Change{Change: diff.Change{Path: []string{"instance_type"}}},
+			expected: DefaultSeverity,
+		},
+		{
+			name:     "computed change",
+			ty:       "aws_instance",
+			change:   Change{Change: diff.Change{Path: []string{"arn"}}, Computed: true},
+			expected: SeverityInfo,
+		},
+		{
+			name:     "builtin rule on a nested attribute",
+			ty:       "aws_security_group",
+			change:   Change{Change: diff.Change{Path: []string{"ingress", "0", "cidr_blocks", "1"}}},
+			expected: SeverityCritical,
+		},
+		{
+			name:     "builtin rule on a parent attribute",
+			ty:       "aws_security_group",
+			change:   Change{Change: diff.Change{Path: []string{"ingress", "0", "from_port"}}},
+			expected: SeverityHigh,
+		},
+		{
+			name:     "builtin rule with a type glob",
+			ty:       "aws_iam_role_policy",
+			change:   Change{Change: diff.Change{Path: []string{"policy"}}},
+			expected: SeverityHigh,
+		},
+		{
+			name:     "user rule",
+			rules:    userRules,
+			ty:       "aws_instance",
+			change:   Change{Change: diff.Change{Path: []string{"tags", "Env"}}},
+			expected: SeverityMedium,
+		},
+		{
+			name:     "highest matching user rule",
+			rules:    userRules,
+			ty:       "aws_instance",
+			change:   Change{Change: diff.Change{Path: []string{"tags", "Owner"}}},
+			expected: SeverityHigh,
+		},
+		{
+			name:     "user rules take precedence over builtin ones",
+			rules:    userRules,
+			ty:       "aws_s3_bucket",
+			change:   Change{Change: diff.Change{Path: []string{"acl"}}},
+			expected: SeverityLow,
+		},
+		{
+			name:     "fallback on builtin rules",
+			rules:    userRules,
+			ty:       "aws_s3_bucket",
+			change:   Change{Change: diff.Change{Path: []string{"policy"}}},
+			expected: SeverityHigh,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, c.rules.Classify(c.ty, c.change))
+		})
+	}
+}
+
+func TestReadSeverityRules(t *testing.T) {
+	cases := []struct {
+		name     string
+		content  string
+		expected *SeverityRules
+		err      string
+	}{
+		{
+			name: "valid rules",
+			content: strings.Join([]string{
+				"rules:",
+				"  - type: aws_instance",
+				"    path: tags.*",
+				"    severity: medium",
+				"  - path: tags.Owner",
+				"    severity: critical",
+			}, "\n"),
+			expected: NewSeverityRules(
+				SeverityRule{Type: "aws_instance", Path: "tags.*", Severity: SeverityMedium},
+				SeverityRule{Path: "tags.Owner", Severity: SeverityCritical},
+			),
+		},
+		{
+			name:    "unknown severity",
+			content: "rules:\n  - type: aws_instance\n    severity: urgent\n",
+			err:     "unknown severity 'urgent', must be one of info, low, medium, high, critical",
+		},
+		{
+			name:    "missing severity",
+			content: "rules:\n  - type: aws_instance\n",
+			err:     "severity rule 1 of",
+		},
+		{
+			name:    "invalid type",
+			content: "rules:\n  - type: aws_[\n    severity: low\n",
+			err:     "invalid type of severity rule 1 of",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "severity.yml")
+			if err := os.WriteFile(path, []byte(c.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			rules, err := ReadSeverityRules(path)
+			if c.err != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), c.err)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, rules)
+		})
+	}
+}
+
+func TestSeverity_JSON(t *testing.T) {
+	content, err := json.Marshal(Change{Change: diff.Change{Type: diff.UPDATE, Path: []string{"acl"}}, Severity: SeverityHigh})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, string(content), `"severity":"high"`)
+
+	got := Change{}
+	if err := json.Unmarshal(content, &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, SeverityHigh, got.Severity)
+
+	content, err = json.Marshal(Change{Change: diff.Change{Type: diff.UPDATE, Path: []string{"acl"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotContains(t, string(content), "severity")
+}
+
+func TestAnalyze_Severity(t *testing.T) {
+	remote := []*resource.Resource{
+		{Id: "bucket", Type: "aws_s3_bucket", Attrs: &resource.Attributes{"acl": "public-read", "force_destroy": true}},
+	}
+	state := []*resource.Resource{
+		{Id: "bucket", Type: "aws_s3_bucket", Attrs: &resource.Attributes{"acl": "private", "force_destroy": false}},
+	}
+
+	cases := []struct {
+		name        string
+		minSeverity Severity
+		expected    Changelog
+	}{
+		{
+			name: "every change is classified",
+			expected: Changelog{
+				{Change: diff.Change{Type: diff.UPDATE, Path: []string{"acl"}, From: "private", To: "public-read"}, Severity: SeverityHigh},
+				{Change: diff.Change{Type: diff.UPDATE, Path: []string{"force_destroy"}, From: false, To: true}, Severity: DefaultSeverity},
+			},
+		},
+		{
+			name:        "changes below the minimum severity are not reported",
+			minSeverity: SeverityHigh,
+			expected: Changelog{
+				{Change: diff.Change{Type: diff.UPDATE, Path: []string{"acl"}, From: "private", To: "public-read"}, Severity: SeverityHigh},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			analyzer := NewAnalyzer(alerter.NewAlerter(), AnalyzerOptions{Deep: true, MinSeverity: c.minSeverity}, noopFilter{})
+			analysis, err := analyzer.Analyze(remote, state)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if assert.Len(t, analysis.Differences(), 1) {
+				changelog := analysis.Differences()[0].Changelog
+				assert.ElementsMatch(t, c.expected, changelog)
+				assert.Equal(t, SeverityHigh, analysis.Differences()[0].Severity())
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/pkg/cmd/scan/output"
+	"github.com/spf13/cobra"
+)
+
+func NewDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff PREVIOUS CURRENT",
+		Short: "Compare two scan results",
+		Long: "This command will compare two JSON scan results and report new unmanaged resources, resources " +
+			"that became managed, new missing resources, introduced and fixed changes as well as the coverage evolution" +
+			"\n\nOnly console, json and html outputs support comparison" +
+			"\n\nExample: driftctl diff yesterday.json today.json",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			outputFlag, _ := cmd.Flags().GetStringSlice("output")
+			outputs, err := parseOutputFlags(outputFlag)
+			if err != nil {
+				return err
+			}
+
+			// Make sure every output supports comparison before writing anything
+			diffOutputs := make([]output.DiffOutput, 0, len(outputs))
+			for _, o := range outputs {
+				diffOutput, err := output.GetDiffOutput(o)
+				if err != nil {
+					return err
+				}
+				diffOutputs = append(diffOutputs, diffOutput)
+			}
+
+			previous, err := readAnalysis(args[0])
+			if err != nil {
+				return err
+			}
+			current, err := readAnalysis(args[1])
+			if err != nil {
+				return err
+			}
+
+			diff := analyser.NewAnalysisDiff(previous, current)
+			for _, o := range diffOutputs {
+				if err := o.WriteDiff(diff); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.StringSliceP(
+		"output",
+		"o",
+		[]string{output.Example(output.ConsoleOutputType)},
+		"Output format, by default it will write to the console\n"+
+			"Accepted formats are: "+strings.Join(output.SupportedDiffOutputsExample(), ",")+"\n",
+	)
+
+	return cmd
+}
+
+func readAnalysis(path string) (*analyser.Analysis, error) {
+	file := os.Stdin
+	if path != "-" {
+		var err error
+		file, err = os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+	}
+
+	input, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := &analyser.Analysis{}
+	if err := json.Unmarshal(input, analysis); err != nil {
+		return nil, errors.Wrapf(err, "unable to read scan result from %s", path)
+	}
+
+	return analysis, nil
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/snyk/driftctl/pkg/cmd/scan/output"
+	"github.com/snyk/driftctl/test"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffCmd(t *testing.T) {
+	resultPath := path.Join(t.TempDir(), "diff.json")
+
+	rootCmd := &cobra.Command{Use: "root"}
+	rootCmd.AddCommand(NewDiffCmd())
+
+	_, err := test.Execute(rootCmd, "diff", "./testdata/diff_previous.json", "./testdata/diff_current.json", "-o", "json://"+resultPath)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	content, err := os.ReadFile(resultPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := map[string]interface{}{}
+	if err := json.Unmarshal(content, &result); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, float64(25), result["previous_coverage"])
+	assert.Equal(t, float64(50), result["coverage"])
+	assert.Equal(t, float64(25), result["coverage_delta"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": "test_user", "type": "aws_iam_user"}}, result["unmanaged"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"id":   "driftctl",
+			"type": "aws_iam_user",
+			"source": map[string]interface{}{
+				"source":        "tfstate://terraform.tfstate",
+				"namespace":     "",
+				"internal_name": "user",
+			},
+		},
+	}, result["managed"])
+	assert.Equal(t, []interface{}{}, result["missing"])
+	assert.Len(t, result["introduced_differences"], 1)
+	assert.Len(t, result["fixed_differences"], 1)
+}
+
+func TestDiffCmd_Invalid(t *testing.T) {
+	cases := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{
+			name: "missing scan result",
+			args: []string{"./testdata/diff_previous.json"},
+			err:  "accepts 2 arg(s), received 1",
+		},
+		{
+			name: "scan result does not exist",
+			args: []string{"./testdata/diff_previous.json", "doesnotexist"},
+			err:  "open doesnotexist: no such file or directory",
+		},
+		{
+			name: "invalid scan result",
+			args: []string{"./testdata/diff_previous.json", "./testdata/input_stdin_invalid.json"},
+			err:  "unable to read scan result from ./testdata/input_stdin_invalid.json: invalid character 'i' looking for beginning of value",
+		},
+		{
+			name: "output not supporting comparison",
+			args: []string{"./testdata/diff_previous.json", "./testdata/diff_current.json", "-o", "plan://plan.json"},
+			err:  "plan output cannot be used to compare scans, accepted formats are: console://,html://PATH/TO/FILE.html,json+v2://PATH/TO/FILE.json,json://PATH/TO/FILE.json",
+		},
+		{
+			name: "unsupported output",
+			args: []string{"./testdata/diff_previous.json", "./testdata/diff_current.json", "-o", "foobar://"},
+			err:  "Unsupported output 'foobar': \nValid formats are: " + strings.Join(output.SupportedOutputsExample(), ","),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "root"}
+			rootCmd.AddCommand(NewDiffCmd())
+
+			_, err := test.Execute(rootCmd, append([]string{"diff"}, c.args...)...)
+			assert.EqualError(t, err, c.err)
+		})
+	}
+}
@@ -70,6 +70,11 @@ func NewDriftctlCmd(build build.BuildInterface) *DriftctlCmd {
 
 	cmd.AddCommand(NewScanCmd(&pkg.ScanOptions{}))
 	cmd.AddCommand(NewGenDriftIgnoreCmd())
+	cmd.AddCommand(NewDriftignoreCmd())
+	cmd.AddCommand(NewGenImportCmd())
+	cmd.AddCommand(NewDiffCmd())
+	cmd.AddCommand(NewHistoryCmd())
+	cmd.AddCommand(NewServeCmd(&pkg.ScanOptions{}))
 
 	return cmd
 }
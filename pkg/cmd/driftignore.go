@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/snyk/driftctl/pkg/filter"
+)
+
+func NewDriftignoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "driftignore",
+		Short: "Maintain driftignore rules",
+		Long: "Rules of a driftignore file accept metadata in a trailing comment, e.g.\n" +
+			"  aws_s3_bucket.legacy # expires=2026-12-31 owner=team-storage reason=migration in progress\n" +
+			"Expired rules are not applied anymore and raise an alert during scans.\n" +
+			"Rules starting with @attr ignore resources on their attributes, with an optional type glob, e.g.\n" +
+			"  @attr tags.managed-by == \"karpenter\"\n" +
+			"  @attr aws_iam_role path ^= /aws-reserved/\n" +
+			"Accepted operators are ==, !=, ^= (starts with), $= (ends with), *= (contains) and =~ (regular expression).\n" +
+			"Remote resources are only read with their attributes in deep mode, without --deep @attr rules do not " +
+			"apply to unmanaged resources and raise an alert during scans.",
+		Args: cobra.NoArgs,
+	}
+
+	cmd.AddCommand(newDriftignoreAuditCmd())
+
+	return cmd
+}
+
+func newDriftignoreAuditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "List expired, unowned and unused driftignore rules",
+		Long:  "List expired rules, rules without an owner and rules that did not match anything in a scan recorded in history",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("driftignore")
+			rules := filter.NewDriftIgnore(path).Rules()
+
+			scanID, _ := cmd.Flags().GetString("scan")
+			var matches map[string]int64
+			analysis, err := getHistoryAnalysis(historyStore(cmd), scanID)
+			if err != nil {
+				fmt.Fprintf(cmd.OutOrStdout(), "Unable to read scan %s from history, unused rules are not reported: %s\n", scanID, err)
+			} else if analysis.IgnoreRules() != nil {
+				matches = make(map[string]int64, len(analysis.IgnoreRules()))
+				for _, rule := range analysis.IgnoreRules() {
+					matches[rule.Pattern] += rule.Matches
+				}
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "Scan %s did not record driftignore rules, unused rules are not reported\n", scanID)
+			}
+
+			auditDriftignoreRules(cmd.OutOrStdout(), path, rules, matches, time.Now())
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.String(
+		"driftignore",
+		".driftignore",
+		"Path to the driftignore file",
+	)
+	fl.String(
+		"scan",
+		latestHistoryID,
+		"ID of the scan recorded in history to report unused rules from, see history list",
+	)
+	addConfigDirFlag(fl)
+
+	return cmd
+}
+
+// auditDriftignoreRules writes expired rules, rules without owner, and rules that did not match anything when
+// matches by pattern are known, rules added since the scan are not reported as unused
+func auditDriftignoreRules(w io.Writer, path string, rules []filter.IgnoreRule, matches map[string]int64, now time.Time) {
+	expired := make([]filter.IgnoreRule, 0)
+	unowned := make([]filter.IgnoreRule, 0)
+	unused := make([]filter.IgnoreRule, 0)
+	for _, rule := range rules {
+		if rule.IsExpired(now) {
+			expired = append(expired, rule)
+			continue
+		}
+		if rule.Owner == "" {
+			unowned = append(unowned, rule)
+		}
+		if count, found := matches[rule.Pattern]; found && count == 0 {
+			unused = append(unused, rule)
+		}
+	}
+
+	if len(expired)+len(unowned)+len(unused) == 0 {
+		fmt.Fprintf(w, "%d rule(s) of %s are owned, not expired and in use\n", len(rules), path)
+		return
+	}
+
+	writeRules := func(title string, rules []filter.IgnoreRule, details func(rule filter.IgnoreRule) string) {
+		if len(rules) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "%s (%d):\n", title, len(rules))
+		for _, rule := range rules {
+			fmt.Fprintf(w, "  - line %d: %s%s\n", rule.Line, rule.Pattern, details(rule))
+		}
+	}
+	writeRules("Expired rules", expired, func(rule filter.IgnoreRule) string {
+		str := fmt.Sprintf(" (expired on %s", rule.Expires.Format("2006-01-02"))
+		if rule.Owner != "" {
+			str += fmt.Sprintf(", owned by %s", rule.Owner)
+		}
+		return str + ")"
+	})
+	writeRules("Rules without owner", unowned, func(rule filter.IgnoreRule) string {
+		if rule.Reason != "" {
+			return fmt.Sprintf(" (%s)", rule.Reason)
+		}
+		return ""
+	})
+	writeRules("Rules that did not match anything", unused, func(rule filter.IgnoreRule) string {
+		if rule.Owner != "" {
+			return fmt.Sprintf(" (owned by %s)", rule.Owner)
+		}
+		return ""
+	})
+}
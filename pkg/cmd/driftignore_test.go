@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/pkg/filter"
+	"github.com/snyk/driftctl/pkg/history"
+	"github.com/snyk/driftctl/test"
+)
+
+func TestDriftignoreCmd_Audit(t *testing.T) {
+	driftignore := path.Join(t.TempDir(), ".driftignore")
+	content := "aws_s3_bucket.legacy # expires=2000-01-01 owner=team-storage\n" +
+		"aws_s3_bucket.kept # expires=2999-12-31 owner=team-storage reason=audit\n" +
+		"aws_iam_user.* # reason=created by the CI\n" +
+		"aws_instance.old # owner=team-compute\n" +
+		"aws_instance.new # owner=team-compute\n"
+	if err := os.WriteFile(driftignore, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	configDir := t.TempDir()
+	a := analyser.NewAnalysis(analyser.AnalyzerOptions{})
+	a.Date = time.Date(2021, 10, 13, 10, 0, 0, 0, time.UTC)
+	a.SetIgnoreRules([]filter.IgnoreRule{
+		{Pattern: "aws_s3_bucket.legacy", Line: 1},
+		{Pattern: "aws_s3_bucket.kept", Line: 2, Matches: 1},
+		{Pattern: "aws_iam_user.*", Line: 3, Matches: 4},
+		{Pattern: "aws_instance.old", Line: 4},
+	})
+	if _, err := history.NewStore(configDir).Save(a); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name      string
+		configDir string
+		expected  string
+	}{
+		{
+			name:      "audit rules against the latest scan",
+			configDir: configDir,
+			expected: "Expired rules (1):\n" +
+				"  - line 1: aws_s3_bucket.legacy (expired on 2000-01-01, owned by team-storage)\n" +
+				"Rules without owner (1):\n" +
+				"  - line 3: aws_iam_user.* (created by the CI)\n" +
+				"Rules that did not match anything (1):\n" +
+				"  - line 4: aws_instance.old (owned by team-compute)\n",
+		},
+		{
+			name:      "audit rules without history",
+			configDir: t.TempDir(),
+			expected: "Unable to read scan latest from history, unused rules are not reported: No scan recorded in history yet\n" +
+				"Expired rules (1):\n" +
+				"  - line 1: aws_s3_bucket.legacy (expired on 2000-01-01, owned by team-storage)\n" +
+				"Rules without owner (1):\n" +
+				"  - line 3: aws_iam_user.* (created by the CI)\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "root"}
+			rootCmd.AddCommand(NewDriftignoreCmd())
+
+			output, err := test.Execute(rootCmd, "driftignore", "audit", "--driftignore", driftignore, "--config-dir", c.configDir)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, output)
+		})
+	}
+}
+
+func TestAuditDriftignoreRules_InUse(t *testing.T) {
+	rules := []filter.IgnoreRule{{Pattern: "aws_iam_user.*", Line: 1, Owner: "team-iam", Matches: 2}}
+	output := &strings.Builder{}
+	auditDriftignoreRules(output, ".driftignore", rules, map[string]int64{"aws_iam_user.*": 2}, time.Now())
+	assert.Equal(t, "1 rule(s) of .driftignore are owned, not expired and in use\n", output.String())
+}
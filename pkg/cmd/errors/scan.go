@@ -1,7 +1,23 @@
 package errors
 
+import "fmt"
+
 type InfrastructureNotInSync struct{}
 
 func (i InfrastructureNotInSync) Error() string {
 	return "Infrastructure is not in sync"
 }
+
+// FailedConditions is returned when a scan meets conditions given with --fail-on
+type FailedConditions struct {
+	ExitCode int
+	count    int
+}
+
+func NewFailedConditions(exitCode, count int) FailedConditions {
+	return FailedConditions{exitCode, count}
+}
+
+func (f FailedConditions) Error() string {
+	return fmt.Sprintf("%d fail condition(s) met", f.count)
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform/providers"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/pkg/iac/terraform/imports"
+	"github.com/snyk/driftctl/pkg/resource"
+	"github.com/snyk/driftctl/pkg/terraform"
+)
+
+// Reads the schema of a provider, see terraform.ReadProviderSchema
+type providerSchemaReader func(name, version, configDir string) (map[string]providers.Schema, error)
+
+func NewGenImportCmd() *cobra.Command {
+	return newGenImportCmd(terraform.ReadProviderSchema)
+}
+
+func newGenImportCmd(readSchema providerSchemaReader) *cobra.Command {
+	opts := imports.Options{}
+
+	cmd := &cobra.Command{
+		Use:   "gen-import",
+		Short: "Generate Terraform import blocks for unmanaged resources based on your scan result",
+		Long: "This command will generate Terraform 1.5+ import blocks for resources not covered by IaC, " +
+			"optionally along with their resource block filled with attributes read in deep mode" +
+			"\n\nThis command does not run a scan, it reads the JSON output of a previous one" +
+			"\n\nExample: driftctl scan --deep -o json://stdout | driftctl gen-import --with-resources --group-by type -o imports/",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inputPath, _ := cmd.Flags().GetString("input")
+			outputPath, _ := cmd.Flags().GetString("output")
+			groupBy, _ := cmd.Flags().GetString("group-by")
+
+			if err := parseGroupByFlag(groupBy, &opts); err != nil {
+				return err
+			}
+			if outputPath == "-" && (opts.GroupByType || opts.GroupByTag != "") {
+				return errors.New("An output directory is required to group resources in several files")
+			}
+
+			analysis, err := readAnalysis(inputPath)
+			if err != nil {
+				return err
+			}
+
+			unmanaged := analysis.Unmanaged()
+			if opts.WithResources {
+				for _, res := range unmanaged {
+					if res.Attributes() == nil {
+						fmt.Fprintln(os.Stderr, "Some resources have no attributes, make sure the scan was run in deep mode to fill resource blocks")
+						break
+					}
+				}
+
+				// Scan results do not contain schemas, they are required to skip computed attributes
+				configDir, _ := cmd.Flags().GetString("config-dir")
+				repo, err := loadSchemaRepository(analysis.Providers, configDir, readSchema)
+				if err != nil {
+					return err
+				}
+				for _, res := range unmanaged {
+					res.Sch, _ = repo.GetSchema(res.ResourceType())
+				}
+			}
+
+			files := imports.Generate(unmanaged, opts)
+			if outputPath == "-" {
+				for _, file := range files {
+					fmt.Fprint(os.Stdout, string(file.Content))
+				}
+				return nil
+			}
+
+			if err := os.MkdirAll(outputPath, 0755); err != nil {
+				return errors.Errorf("error creating output directory: %s", err)
+			}
+			for _, file := range files {
+				path := filepath.Join(outputPath, file.Name)
+				// Never overwrite existing configuration
+				f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+				if err != nil {
+					return errors.Errorf("error opening output file: %s", err)
+				}
+				_, err = f.Write(file.Content)
+				f.Close()
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(os.Stderr, "Import blocks written to %s\n", path)
+			}
+
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+
+	fl.BoolVar(&opts.WithResources, "with-resources", false, "Also generate resource blocks filled with attributes read in deep mode")
+	fl.String("group-by", "", "Write resources in several files grouped by \"type\" or by tag value with \"tag:KEY\", untagged resources are written to _untagged.tf")
+	fl.StringP("input", "i", "-", "Input where the JSON should be parsed from. Defaults to stdin.")
+	fl.StringP("output", "o", "-", "Output directory to write Terraform files to. Defaults to stdout.")
+	addConfigDirFlag(fl)
+
+	return cmd
+}
+
+// loadSchemaRepository reads the schema of providers the scan was run with
+func loadSchemaRepository(scanProviders []analyser.Provider, configDir string, readSchema providerSchemaReader) (*resource.SchemaRepository, error) {
+	repo := resource.NewSchemaRepository()
+	if len(scanProviders) == 0 {
+		fmt.Fprintln(os.Stderr, "Scan result does not tell which providers were used, attributes computed by providers may be written in resource blocks")
+		return repo, nil
+	}
+	for _, p := range scanProviders {
+		schema, err := readSchema(p.Name, p.Version, configDir)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read schema of provider %s %s", p.Name, p.Version)
+		}
+		if err := repo.Init(p.Name, p.Version, schema); err != nil {
+			return nil, err
+		}
+	}
+	return repo, nil
+}
+
+func parseGroupByFlag(groupBy string, opts *imports.Options) error {
+	switch {
+	case groupBy == "":
+	case groupBy == "type":
+		opts.GroupByType = true
+	case strings.HasPrefix(groupBy, "tag:") && len(groupBy) > len("tag:"):
+		opts.GroupByTag = strings.TrimPrefix(groupBy, "tag:")
+	default:
+		return errors.Errorf("Unable to parse group by flag '%s', accepted values are: type, tag:KEY", groupBy)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/hashicorp/terraform/providers"
+	"github.com/snyk/driftctl/test"
+	"github.com/snyk/driftctl/test/schemas"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+// readTestProviderSchema reads schemas of test fixtures instead of installing providers
+func readTestProviderSchema(name, version, _ string) (map[string]providers.Schema, error) {
+	return schemas.ReadTestSchema(name, version)
+}
+
+func TestGenImportCmd(t *testing.T) {
+	outputDir := t.TempDir()
+
+	rootCmd := &cobra.Command{Use: "root"}
+	rootCmd.AddCommand(newGenImportCmd(readTestProviderSchema))
+
+	_, err := test.Execute(rootCmd, "gen-import", "-i", "./testdata/gen_import_input.json", "-o", outputDir, "--with-resources")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	expected, err := os.ReadFile("./testdata/gen_import_output.tf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := os.ReadFile(path.Join(outputDir, "imports.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(expected), string(result))
+
+	// Existing configuration is never overwritten
+	rootCmd = &cobra.Command{Use: "root"}
+	rootCmd.AddCommand(NewGenImportCmd())
+	_, err = test.Execute(rootCmd, "gen-import", "-i", "./testdata/gen_import_input.json", "-o", outputDir)
+	assert.EqualError(t, err, "error opening output file: open "+path.Join(outputDir, "imports.tf")+": file exists")
+}
+
+func TestGenImportCmd_GroupBy(t *testing.T) {
+	cases := []struct {
+		name    string
+		groupBy string
+		files   []string
+	}{
+		{
+			name:    "group by type",
+			groupBy: "type",
+			files:   []string{"github_membership.tf", "github_repository.tf", "github_team.tf"},
+		},
+		{
+			name:    "group by tag",
+			groupBy: "tag:Env",
+			files:   []string{"_untagged.tf"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			outputDir := t.TempDir()
+
+			rootCmd := &cobra.Command{Use: "root"}
+			rootCmd.AddCommand(NewGenImportCmd())
+
+			_, err := test.Execute(rootCmd, "gen-import", "-i", "./testdata/gen_import_input.json", "-o", outputDir, "--group-by", c.groupBy)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			entries, err := os.ReadDir(outputDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			files := make([]string, 0, len(entries))
+			for _, entry := range entries {
+				files = append(files, entry.Name())
+			}
+			assert.Equal(t, c.files, files)
+		})
+	}
+}
+
+func TestGenImportCmd_Invalid(t *testing.T) {
+	cases := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{
+			name: "invalid group by",
+			args: []string{"--group-by", "region", "-o", "imports"},
+			err:  "Unable to parse group by flag 'region', accepted values are: type, tag:KEY",
+		},
+		{
+			name: "missing tag key",
+			args: []string{"--group-by", "tag:", "-o", "imports"},
+			err:  "Unable to parse group by flag 'tag:', accepted values are: type, tag:KEY",
+		},
+		{
+			name: "group by on stdout",
+			args: []string{"--group-by", "type"},
+			err:  "An output directory is required to group resources in several files",
+		},
+		{
+			name: "unknown provider schema",
+			args: []string{"-i", "./testdata/gen_import_input.json", "--with-resources"},
+			err:  "unable to read schema of provider github 4.4.0: provider not found",
+		},
+		{
+			name: "invalid input",
+			args: []string{"-i", "./testdata/input_stdin_invalid.json"},
+			err:  "unable to read scan result from ./testdata/input_stdin_invalid.json: invalid character 'i' looking for beginning of value",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "root"}
+			rootCmd.AddCommand(newGenImportCmd(func(string, string, string) (map[string]providers.Schema, error) {
+				return nil, errors.New("provider not found")
+			}))
+
+			_, err := test.Execute(rootCmd, append([]string{"gen-import"}, c.args...)...)
+			assert.EqualError(t, err, c.err)
+		})
+	}
+}
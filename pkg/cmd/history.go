@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/pkg/cmd/scan/output"
+	"github.com/snyk/driftctl/pkg/history"
+)
+
+// Scan ID referring to the most recent recorded scan
+const latestHistoryID = "latest"
+
+func NewHistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Browse scan results recorded in history",
+		Long:  "Every scan result is recorded in the config directory unless --disable-history is used, this command allows to browse them",
+		Args:  cobra.NoArgs,
+	}
+
+	addConfigDirFlag(cmd.PersistentFlags())
+
+	cmd.AddCommand(newHistoryListCmd())
+	cmd.AddCommand(newHistoryShowCmd())
+	cmd.AddCommand(newHistoryTrendCmd())
+
+	return cmd
+}
+
+func newHistoryListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List recorded scans",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := historyStore(cmd).List()
+			if err != nil {
+				return err
+			}
+			if len(entries) == 0 {
+				fmt.Println("No scan recorded in history yet")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tDATE\tCOVERAGE\tMANAGED\tUNMANAGED\tMISSING\tCHANGED")
+			for i := len(entries) - 1; i >= 0; i-- {
+				entry := entries[i]
+				fmt.Fprintf(
+					w,
+					"%s\t%s\t%d%%\t%d\t%d\t%d\t%d\n",
+					entry.ID,
+					entry.Date.Format("2006-01-02 15:04:05"),
+					entry.Coverage,
+					entry.Summary.TotalManaged,
+					entry.Summary.TotalUnmanaged,
+					entry.Summary.TotalDeleted,
+					entry.Summary.TotalDrifted,
+				)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func newHistoryShowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show ID",
+		Short: "Show a recorded scan result",
+		Long:  "Show a recorded scan result with any scan output, use latest as ID to show the most recent one",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			outputFlag, _ := cmd.Flags().GetStringSlice("output")
+			outputs, err := parseOutputFlags(outputFlag)
+			if err != nil {
+				return err
+			}
+
+			analysis, err := getHistoryAnalysis(historyStore(cmd), args[0])
+			if err != nil {
+				return err
+			}
+			for _, o := range outputs {
+				if err := output.GetOutput(o).Write(analysis); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	addHistoryOutputFlag(cmd)
+
+	return cmd
+}
+
+func newHistoryTrendCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trend",
+		Short: "Report the evolution of coverage and drifts over recorded scans",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			outputFlag, _ := cmd.Flags().GetStringSlice("output")
+			outputs, err := parseOutputFlags(outputFlag)
+			if err != nil {
+				return err
+			}
+			trendOutputs := make([]output.TrendOutput, 0, len(outputs))
+			for _, o := range outputs {
+				trendOutput, err := output.GetTrendOutput(o)
+				if err != nil {
+					return err
+				}
+				trendOutputs = append(trendOutputs, trendOutput)
+			}
+
+			entries, err := historyStore(cmd).List()
+			if err != nil {
+				return err
+			}
+			if last, _ := cmd.Flags().GetInt("last"); last > 0 && len(entries) > last {
+				entries = entries[len(entries)-last:]
+			}
+
+			for _, o := range trendOutputs {
+				if err := o.WriteTrend(entries); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	addHistoryOutputFlag(cmd)
+	cmd.Flags().Int("last", 0, "Only report the given number of most recent scans")
+
+	return cmd
+}
+
+func addHistoryOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringSliceP(
+		"output",
+		"o",
+		[]string{output.Example(output.ConsoleOutputType)},
+		"Output format, by default it will write to the console\n"+
+			"Accepted formats are: "+strings.Join(output.SupportedOutputsExample(), ",")+"\n",
+	)
+}
+
+// getHistoryAnalysis reads a recorded scan, latestHistoryID refers to the most recent one
+func getHistoryAnalysis(store *history.Store, id string) (*analyser.Analysis, error) {
+	if id == latestHistoryID {
+		entries, err := store.List()
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			return nil, errors.New("No scan recorded in history yet")
+		}
+		id = entries[len(entries)-1].ID
+	}
+
+	analysis, _, err := store.Get(id)
+	return analysis, err
+}
+
+func historyStore(cmd *cobra.Command) *history.Store {
+	configDir, _ := cmd.Flags().GetString("config-dir")
+	return history.NewStore(configDir)
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/pkg/history"
+	"github.com/snyk/driftctl/pkg/resource"
+	"github.com/snyk/driftctl/test"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func fakeHistory(t *testing.T) string {
+	configDir := t.TempDir()
+	store := history.NewStore(configDir)
+
+	for i, managed := range []int{1, 3} {
+		a := analyser.NewAnalysis(analyser.AnalyzerOptions{})
+		for j := 0; j < managed; j++ {
+			a.AddManaged(&resource.Resource{Id: string(rune('a' + j)), Type: "aws_s3_bucket"})
+		}
+		a.AddUnmanaged(&resource.Resource{Id: "user", Type: "aws_iam_user"})
+		a.Date = time.Date(2021, 10, 13+i, 10, 0, 0, 0, time.UTC)
+		if _, err := store.Save(a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return configDir
+}
+
+func TestHistoryCmd_Show(t *testing.T) {
+	configDir := fakeHistory(t)
+
+	cases := []struct {
+		name     string
+		id       string
+		coverage int
+		err      string
+	}{
+		{
+			name:     "show a scan",
+			id:       "20211013-100000",
+			coverage: 50,
+		},
+		{
+			name:     "show the latest scan",
+			id:       "latest",
+			coverage: 75,
+		},
+		{
+			name: "unknown scan",
+			id:   "20211013-110000",
+			err:  "scan 20211013-110000 not found in history",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			resultPath := path.Join(t.TempDir(), "result.json")
+
+			rootCmd := &cobra.Command{Use: "root"}
+			rootCmd.AddCommand(NewHistoryCmd())
+
+			_, err := test.Execute(rootCmd, "history", "show", c.id, "--config-dir", configDir, "-o", "json://"+resultPath)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			content, err := os.ReadFile(resultPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			analysis := &analyser.Analysis{}
+			assert.NoError(t, json.Unmarshal(content, analysis))
+			assert.Equal(t, c.coverage, analysis.Coverage())
+		})
+	}
+}
+
+func TestHistoryCmd_Trend(t *testing.T) {
+	configDir := fakeHistory(t)
+
+	cases := []struct {
+		name      string
+		args      []string
+		coverages []int
+		err       string
+	}{
+		{
+			name:      "every scan",
+			args:      []string{},
+			coverages: []int{50, 75},
+		},
+		{
+			name:      "last scans",
+			args:      []string{"--last", "1"},
+			coverages: []int{75},
+		},
+		{
+			name: "output not supporting trend",
+			args: []string{"-o", "plan://plan.json"},
+			err:  "plan output cannot be used to render a trend report",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			resultPath := path.Join(t.TempDir(), "trend.json")
+
+			rootCmd := &cobra.Command{Use: "root"}
+			rootCmd.AddCommand(NewHistoryCmd())
+
+			args := append([]string{"history", "trend", "--config-dir", configDir, "-o", "json://" + resultPath}, c.args...)
+			_, err := test.Execute(rootCmd, args...)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			content, err := os.ReadFile(resultPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			entries := []history.Entry{}
+			assert.NoError(t, json.Unmarshal(content, &entries))
+			coverages := make([]int, 0, len(entries))
+			for _, e := range entries {
+				coverages = append(coverages, e.Coverage)
+			}
+			assert.Equal(t, c.coverages, coverages)
+		})
+	}
+}
+
+func TestHistoryCmd_ShowEmpty(t *testing.T) {
+	rootCmd := &cobra.Command{Use: "root"}
+	rootCmd.AddCommand(NewHistoryCmd())
+
+	_, err := test.Execute(rootCmd, "history", "show", "latest", "--config-dir", t.TempDir())
+	assert.EqualError(t, err, "No scan recorded in history yet")
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
@@ -15,11 +16,14 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/snyk/driftctl/build"
 	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/pkg/history"
 	"github.com/snyk/driftctl/pkg/memstore"
+	"github.com/snyk/driftctl/pkg/policy"
 	"github.com/snyk/driftctl/pkg/remote/common"
 	"github.com/snyk/driftctl/pkg/telemetry"
 	"github.com/snyk/driftctl/pkg/terraform/lock"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/snyk/driftctl/pkg"
 	"github.com/snyk/driftctl/pkg/alerter"
@@ -31,6 +35,7 @@ import (
 	"github.com/snyk/driftctl/pkg/iac/terraform/state/backend"
 	globaloutput "github.com/snyk/driftctl/pkg/output"
 	"github.com/snyk/driftctl/pkg/remote"
+	"github.com/snyk/driftctl/pkg/remote/aws"
 	"github.com/snyk/driftctl/pkg/resource"
 	"github.com/snyk/driftctl/pkg/terraform"
 )
@@ -53,13 +58,30 @@ func NewScanCmd(opts *pkg.ScanOptions) *cobra.Command {
 
 			opts.From = iacSource
 
-			to, _ := cmd.Flags().GetString("to")
-			if !remote.IsSupported(to) {
-				return errors.Errorf(
-					"unsupported cloud provider '%s'\nValid values are: %s",
-					to,
-					strings.Join(remote.GetSupportedRemotes(), ","),
-				)
+			toFlag, _ := cmd.Flags().GetStringSlice("to")
+			to, err := parseToFlag(toFlag)
+			if err != nil {
+				return err
+			}
+			opts.To = to
+
+			awsRegions, _ := cmd.Flags().GetStringSlice("aws-regions")
+			if err := validateAWSRegions(awsRegions, to); err != nil {
+				return err
+			}
+			opts.AWSOptions.Regions = awsRegions
+
+			opts.AWSOptions.Accounts, _ = cmd.Flags().GetStringSlice("aws-accounts")
+			opts.AWSOptions.DiscoverAccounts, _ = cmd.Flags().GetBool("aws-organization")
+			opts.AWSOptions.AssumeRoleARN, _ = cmd.Flags().GetString("aws-assume-role")
+			opts.AWSOptions.AssumeRoleExternalID, _ = cmd.Flags().GetString("aws-external-id")
+			if err := validateAWSAccounts(opts.AWSOptions, to); err != nil {
+				return err
+			}
+
+			stateAccounts, _ := cmd.Flags().GetStringArray("aws-state-account")
+			if err := parseStateAccountFlag(stateAccounts, opts.From, opts.AWSOptions); err != nil {
+				return err
 			}
 
 			outputFlag, _ := cmd.Flags().GetStringSlice("output")
@@ -70,6 +92,16 @@ func NewScanCmd(opts *pkg.ScanOptions) *cobra.Command {
 			}
 			opts.Output = out
 
+			webhookOptions, err := parseWebhookFlags(cmd)
+			if err != nil {
+				return err
+			}
+			for i := range opts.Output {
+				if output.IsWebhook(opts.Output[i].Key) {
+					opts.Output[i].WebhookOptions = webhookOptions
+				}
+			}
+
 			filterFlag, _ := cmd.Flags().GetStringArray("filter")
 
 			if len(filterFlag) > 1 {
@@ -88,27 +120,64 @@ func NewScanCmd(opts *pkg.ScanOptions) *cobra.Command {
 			if err := validateTfProviderVersionString(providerVersion); err != nil {
 				return err
 			}
-			opts.ProviderVersion = providerVersion
+			if providerVersion != "" && len(to) > 1 {
+				return errors.New("--tf-provider-version cannot be used when scanning several cloud providers, use --tf-lockfile instead")
+			}
 
-			if opts.ProviderVersion == "" {
+			opts.ProviderVersions = make(map[string]string, len(to))
+			if providerVersion != "" {
+				opts.ProviderVersions[to[0]] = providerVersion
+			} else {
 				lockfilePath, _ := cmd.Flags().GetString("tf-lockfile")
 
-				// Attempt to read the provider version from a terraform lock file
+				// Attempt to read the providers versions from a terraform lock file
 				lockFile, err := lock.ReadLocksFromFile(lockfilePath)
 				if err != nil {
 					logrus.WithField("error", err.Error()).Debug("Error while parsing terraform lock file")
 				}
-				if provider := lockFile.GetProviderByAddress(common.RemoteParameter(to).GetProviderAddress()); provider != nil {
-					opts.ProviderVersion = provider.Version
-					logrus.WithFields(logrus.Fields{"version": opts.ProviderVersion, "provider": to}).Debug("Found provider version in terraform lock file")
+				for _, r := range to {
+					if provider := lockFile.GetProviderByAddress(common.RemoteParameter(r).GetProviderAddress()); provider != nil {
+						opts.ProviderVersions[r] = provider.Version
+						logrus.WithFields(logrus.Fields{"version": provider.Version, "provider": r}).Debug("Found provider version in terraform lock file")
+					}
 				}
 			}
 
 			opts.Quiet, _ = cmd.Flags().GetBool("quiet")
 			opts.DisableTelemetry, _ = cmd.Flags().GetBool("disable-telemetry")
+			opts.DisableHistory, _ = cmd.Flags().GetBool("disable-history")
+			opts.HistoryRetention, _ = cmd.Flags().GetInt("history-retention")
+			if opts.HistoryRetention < 0 {
+				return errors.New("History retention must be a positive number of scans")
+			}
 
 			opts.ConfigDir, _ = cmd.Flags().GetString("config-dir")
 
+			failOnFlag, _ := cmd.Flags().GetStringArray("fail-on")
+			failOn, err := parseFailOnFlag(failOnFlag)
+			if err != nil {
+				return err
+			}
+			opts.FailOn = failOn
+
+			minSeverityFlag, _ := cmd.Flags().GetString("min-severity")
+			if minSeverityFlag != "" {
+				minSeverity, err := analyser.ParseSeverity(minSeverityFlag)
+				if err != nil {
+					return errors.Wrap(err, "unable to parse --min-severity")
+				}
+				opts.MinSeverity = minSeverity
+			}
+
+			severityRulesFlag, _ := cmd.Flags().GetString("severity-rules")
+			if severityRulesFlag != "" {
+				rules, err := analyser.ReadSeverityRules(severityRulesFlag)
+				if err != nil {
+					return errors.Wrap(err, "unable to read severity rules")
+				}
+				opts.SeverityRules = rules
+			}
+
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -138,7 +207,8 @@ func NewScanCmd(opts *pkg.ScanOptions) *cobra.Command {
 		"o",
 		[]string{output.Example(output.ConsoleOutputType)},
 		"Output format, by default it will write to the console\n"+
-			"Accepted formats are: "+strings.Join(output.SupportedOutputsExample(), ",")+"\n",
+			"Accepted formats are: "+strings.Join(output.SupportedOutputsExample(), ",")+"\n"+
+			"Console output accepts options to group resources or only print the summary: "+output.ConsoleOptionsExample+"\n",
 	)
 	fl.StringSliceP(
 		"from",
@@ -148,14 +218,46 @@ func NewScanCmd(opts *pkg.ScanOptions) *cobra.Command {
 			"Accepted schemes are: "+strings.Join(supplier.GetSupportedSchemes(), ",")+"\n",
 	)
 	supportedRemotes := remote.GetSupportedRemotes()
-	fl.StringVarP(
-		&opts.To,
+	fl.StringSliceP(
 		"to",
 		"t",
-		supportedRemotes[0],
-		"Cloud provider source\n"+
+		[]string{supportedRemotes[0]},
+		"Cloud provider sources, several providers can be scanned at once\n"+
 			"Accepted values are: "+strings.Join(supportedRemotes, ",")+"\n",
 	)
+	fl.StringSlice(
+		"aws-regions",
+		[]string{},
+		"AWS regions to scan, by default only the region of your AWS session is scanned\n"+
+			"Use \""+aws.AllRegions+"\" to scan every region enabled for your account\n",
+	)
+	fl.StringSlice(
+		"aws-accounts",
+		[]string{},
+		"AWS account IDs to scan by assuming the role given by --aws-assume-role in each of them\n",
+	)
+	fl.Bool(
+		"aws-organization",
+		false,
+		"Scan every active account of the AWS organization of your AWS session by assuming the role given by --aws-assume-role\n",
+	)
+	fl.String(
+		"aws-assume-role",
+		"arn:aws:iam::"+aws.AccountIDPlaceholder+":role/OrganizationAccountAccessRole",
+		"ARN of the role to assume in scanned AWS accounts, "+aws.AccountIDPlaceholder+" is replaced by the account ID\n",
+	)
+	fl.String(
+		"aws-external-id",
+		"",
+		"External ID to use when assuming the role in scanned AWS accounts\n",
+	)
+	fl.StringArray(
+		"aws-state-account",
+		[]string{},
+		"Bind an IaC source to an AWS account so that it is only matched against resources of this account\n"+
+			"Example: --aws-state-account tfstate+s3://my-bucket/prod.tfstate=123456789012\n"+
+			"The IaC source must be given exactly as in --from\n",
+	)
 	fl.StringToStringVarP(&opts.BackendOptions.Headers,
 		"headers",
 		"H",
@@ -202,15 +304,71 @@ func NewScanCmd(opts *pkg.ScanOptions) *cobra.Command {
 		"Terraform lock file to get the provider's version from. Will be ignored if the file doesn't exist.\n",
 	)
 
-	configDir, err := homedir.Dir()
-	if err != nil {
-		configDir = os.TempDir()
-	}
+	fl.Bool(
+		"disable-history",
+		false,
+		"Do not record the scan result in the history stored in the config directory",
+	)
+	fl.Int(
+		"history-retention",
+		history.DefaultRetention,
+		"Number of scans kept in the history, older ones are deleted when a scan is recorded. 0 keeps every scan",
+	)
+	fl.StringArray(
+		"fail-on",
+		[]string{},
+		"Only fail the scan when one of those conditions is met instead of failing on any drift, each condition exits with its own code\n"+
+			fmt.Sprintf("  - coverage<PERCENT: coverage is below the given percentage (exit code %d)\n", policy.ExitCodeCoverage)+
+			fmt.Sprintf("  - missing[:TYPE,...]: any resource, or any resource of the given types, is missing (exit code %d)\n", policy.ExitCodeMissing)+
+			fmt.Sprintf("  - drift[:PATH,...]: any attribute, or any attribute under the given paths, has drifted (exit code %d)\n", policy.ExitCodeDrift)+
+			fmt.Sprintf("  - unmanaged-growth:BASELINE: there are more unmanaged resources than in the given JSON scan result (exit code %d)\n", policy.ExitCodeUnmanagedGrowth)+
+			fmt.Sprintf("  - duplicated[:TYPE,...]: any resource, or any resource of the given types, is managed by more than one IaC resource (exit code %d)\n", policy.ExitCodeDuplicated)+
+			fmt.Sprintf("  - severity:LEVEL: any change is of the given severity or higher (exit code %d)\n", policy.ExitCodeSeverity)+
+			"Examples: --fail-on coverage<80 --fail-on drift:tags.Env,acl\n",
+	)
 	fl.String(
-		"config-dir",
-		configDir,
-		"Directory path that driftctl uses for configuration.\n",
+		"min-severity",
+		"",
+		fmt.Sprintf("Only report changes of the given severity or higher, one of %s\n", strings.Join(analyser.SeverityNames, ", ")),
+	)
+	fl.String(
+		"severity-rules",
+		"",
+		"YAML or JSON file of rules giving a severity to changes, on top of the builtin ones\n"+
+			"Example of rule: {type: aws_instance, path: tags.*, severity: medium}\n",
+	)
+	fl.StringToString(
+		"webhook-headers",
+		map[string]string{},
+		"HTTP headers sent with webhook, slack and teams outputs\n",
+	)
+	fl.String(
+		"webhook-secret",
+		"",
+		"Secret used to sign the payload sent by webhook, slack and teams outputs\n"+
+			"The HMAC-SHA256 signature is sent in the "+output.WebhookSignatureHeader+" header\n",
 	)
+	fl.Int(
+		"webhook-retries",
+		output.DefaultWebhookRetries,
+		"Number of retries when a webhook, slack or teams output fails to send the scan result\n",
+	)
+	fl.Duration(
+		"webhook-timeout",
+		output.DefaultWebhookTimeout,
+		"Timeout of each request made by webhook, slack and teams outputs\n",
+	)
+	fl.String(
+		"webhook-template",
+		"",
+		"Go template rendering the payload sent by webhook, slack and teams outputs instead of the default one\n",
+	)
+	fl.Int(
+		"webhook-top",
+		output.DefaultWebhookTop,
+		"Maximum number of drifted resources listed by webhook, slack and teams outputs\n",
+	)
+	addConfigDirFlag(fl)
 
 	return cmd
 }
@@ -218,10 +376,8 @@ func NewScanCmd(opts *pkg.ScanOptions) *cobra.Command {
 func scanRun(opts *pkg.ScanOptions) error {
 	store := memstore.New()
 
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-
-	alerter := alerter.NewAlerter()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	// For now, we only use the global printer to print progress and information about the current scan, so unless one
 	// of the configured output should silence global output we simply use console by default.
@@ -229,6 +385,92 @@ func scanRun(opts *pkg.ScanOptions) error {
 		globaloutput.ChangePrinter(globaloutput.NewConsolePrinter())
 	}
 
+	analysis, err := runScan(ctx, opts, store)
+	if err != nil {
+		return err
+	}
+
+	if len(opts.FailOn) > 0 {
+		analysis.SetFailedConditions(policy.Evaluate(opts.FailOn, analysis))
+	}
+
+	validOutput := false
+	for _, o := range opts.Output {
+		if err = output.GetOutput(o).Write(analysis); err != nil {
+			logrus.Errorf("Error writing to output %s: %v", o.String(), err.Error())
+			continue
+		}
+		validOutput = true
+	}
+
+	// Fallback to console output if all output failed
+	if !validOutput {
+		logrus.Debug("All outputs failed, fallback to console output")
+		if err = output.NewConsole().Write(analysis); err != nil {
+			return err
+		}
+	}
+
+	globaloutput.Printf(color.WhiteString("Scan duration: %s\n", analysis.Duration.Round(time.Second)))
+	for _, p := range analysis.Providers {
+		globaloutput.Printf(color.WhiteString("Provider version used to scan %s: %s. Use --tf-provider-version to use another version.\n"), p.Name, p.Version)
+	}
+
+	recordHistory(opts, analysis)
+
+	if !opts.DisableTelemetry {
+		tl := telemetry.NewTelemetry(&build.Build{})
+		tl.SendTelemetry(store.Bucket(memstore.TelemetryBucket))
+	}
+
+	if len(opts.FailOn) > 0 {
+		if failed := analysis.FailedConditions(); len(failed) > 0 {
+			return cmderrors.NewFailedConditions(failed[0].ExitCode, len(failed))
+		}
+		return nil
+	}
+
+	if !analysis.IsSync() {
+		globaloutput.Printf("\nHint: use gen-driftignore command to generate a .driftignore file based on your drifts\n")
+
+		return cmderrors.InfrastructureNotInSync{}
+	}
+
+	return nil
+}
+
+func addConfigDirFlag(fl *pflag.FlagSet) {
+	configDir, err := homedir.Dir()
+	if err != nil {
+		configDir = os.TempDir()
+	}
+	fl.String(
+		"config-dir",
+		configDir,
+		"Directory path that driftctl uses for configuration.\n",
+	)
+}
+
+// recordHistory saves the analysis in the history, failing to do so does not fail the scan
+func recordHistory(opts *pkg.ScanOptions, analysis *analyser.Analysis) {
+	if opts.DisableHistory {
+		return
+	}
+	store := history.NewStore(opts.ConfigDir)
+	store.Retention = opts.HistoryRetention
+	entry, err := store.Save(analysis)
+	if err != nil {
+		logrus.Warnf("Unable to record scan in history: %s", err)
+		return
+	}
+	logrus.WithField("id", entry.ID).Debug("Scan recorded in history")
+}
+
+// runScan runs a single scan, a new provider library is initialised for each of them
+// and cleaned up once the scan is over.
+func runScan(ctx context.Context, opts *pkg.ScanOptions, store memstore.Store) (*analyser.Analysis, error) {
+	alerter := alerter.NewAlerter()
+
 	providerLibrary := terraform.NewProviderLibrary()
 	remoteLibrary := common.NewRemoteLibrary()
 
@@ -239,11 +481,6 @@ func scanRun(opts *pkg.ScanOptions) error {
 
 	resFactory := terraform.NewTerraformResourceFactory(resourceSchemaRepository)
 
-	err := remote.Activate(opts.To, opts.ProviderVersion, alerter, providerLibrary, remoteLibrary, scanProgress, resourceSchemaRepository, resFactory, opts.ConfigDir)
-	if err != nil {
-		return err
-	}
-
 	// Teardown
 	defer func() {
 		logrus.Trace("Exiting scan cmd")
@@ -251,21 +488,46 @@ func scanRun(opts *pkg.ScanOptions) error {
 		logrus.Trace("Exited")
 	}()
 
+	providers := make([]analyser.Provider, 0, len(opts.To))
+	for _, to := range opts.To {
+		err := remote.Activate(to, opts.ProviderVersions[to], alerter, providerLibrary, remoteLibrary, scanProgress, resourceSchemaRepository, resFactory, opts.ConfigDir, opts.AWSOptions)
+		if err != nil {
+			return nil, err
+		}
+		provider := providerLibrary.Provider(common.RemoteParameter(to).GetProviderAddress().Type)
+		providers = append(providers, analyser.Provider{
+			Name:    provider.Name(),
+			Version: provider.Version(),
+		})
+	}
+
 	logrus.Debug("Checking for driftignore")
 	driftIgnore := filter.NewDriftIgnore(opts.DriftignorePath)
+	for _, rule := range driftIgnore.ExpiredRules() {
+		alerter.SendAlert("", filter.NewExpiredIgnoreRuleAlert(rule))
+	}
+	if !opts.Deep {
+		for _, rule := range driftIgnore.AttributeRules() {
+			alerter.SendAlert("", filter.NewAttributeRuleWithoutDeepAlert(rule))
+		}
+	}
 
 	scanner := remote.NewScanner(remoteLibrary, alerter, remote.ScannerOptions{Deep: opts.Deep}, driftIgnore)
 
 	iacSupplier, err := supplier.GetIACSupplier(opts.From, providerLibrary, opts.BackendOptions, iacProgress, alerter, resFactory, driftIgnore)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	ctl := pkg.NewDriftCTL(
 		scanner,
 		iacSupplier,
 		alerter,
-		analyser.NewAnalyzer(alerter, analyser.AnalyzerOptions{Deep: opts.Deep}, driftIgnore),
+		analyser.NewAnalyzer(alerter, analyser.AnalyzerOptions{
+			Deep:          opts.Deep,
+			MinSeverity:   opts.MinSeverity,
+			SeverityRules: opts.SeverityRules,
+		}, driftIgnore),
 		resFactory,
 		opts,
 		scanProgress,
@@ -274,53 +536,32 @@ func scanRun(opts *pkg.ScanOptions) error {
 		store,
 	)
 
+	done := make(chan struct{})
+	defer close(done)
 	go func() {
-		<-c
-		logrus.Warn("Detected interrupt, cleanup ...")
-		ctl.Stop()
+		select {
+		case <-ctx.Done():
+			logrus.Warn("Detected interrupt, cleanup ...")
+			ctl.Stop()
+		case <-done:
+		}
 	}()
 
 	analysis, err := ctl.Run()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	analysis.ProviderVersion = resourceSchemaRepository.ProviderVersion.String()
-	analysis.ProviderName = resourceSchemaRepository.ProviderName
-	store.Bucket(memstore.TelemetryBucket).Set("provider_name", analysis.ProviderName)
-
-	validOutput := false
-	for _, o := range opts.Output {
-		if err = output.GetOutput(o).Write(analysis); err != nil {
-			logrus.Errorf("Error writing to output %s: %v", o.String(), err.Error())
-			continue
-		}
-		validOutput = true
-	}
-
-	// Fallback to console output if all output failed
-	if !validOutput {
-		logrus.Debug("All outputs failed, fallback to console output")
-		if err = output.NewConsole().Write(analysis); err != nil {
-			return err
-		}
-	}
-
-	globaloutput.Printf(color.WhiteString("Scan duration: %s\n", analysis.Duration.Round(time.Second)))
-	globaloutput.Printf(color.WhiteString("Provider version used to scan: %s. Use --tf-provider-version to use another version.\n"), resourceSchemaRepository.ProviderVersion.String())
-
-	if !opts.DisableTelemetry {
-		tl := telemetry.NewTelemetry(&build.Build{})
-		tl.SendTelemetry(store.Bucket(memstore.TelemetryBucket))
-	}
-
-	if !analysis.IsSync() {
-		globaloutput.Printf("\nHint: use gen-driftignore command to generate a .driftignore file based on your drifts\n")
-
-		return cmderrors.InfrastructureNotInSync{}
+	analysis.Providers = providers
+	// Recorded in history for driftignore audit
+	analysis.SetIgnoreRules(driftIgnore.Rules())
+	providerNames := make([]string, 0, len(providers))
+	for _, p := range providers {
+		providerNames = append(providerNames, p.Name)
 	}
+	store.Bucket(memstore.TelemetryBucket).Set("provider_name", strings.Join(providerNames, ","))
 
-	return nil
+	return analysis, nil
 }
 
 func parseFromFlag(from []string) ([]config.SupplierConfig, error) {
@@ -373,6 +614,17 @@ func parseFromFlag(from []string) ([]config.SupplierConfig, error) {
 
 		backendString := ""
 		if len(supplierBackend) == 2 {
+			if !supplier.IsBackendSupported(supplierKey) {
+				return nil, errors.Wrapf(
+					cmderrors.NewUsageError(fmt.Sprintf(
+						"\nAccepted schemes are: %s",
+						strings.Join(supplier.GetSupportedSchemes(), ","),
+					),
+					),
+					"Unable to parse from scheme '%s'",
+					scheme,
+				)
+			}
 			backendString = supplierBackend[1]
 			if !backend.IsSupported(backendString) {
 				return nil, errors.Wrapf(
@@ -444,13 +696,33 @@ func parseOutputFlag(out string) (*output.OutputConfig, error) {
 	opts := schemeOpts[1:]
 
 	switch o.Key {
-	case output.JSONOutputType:
+	case output.ConsoleOutputType:
+		// Anything but options is ignored, e.g. console:///dev/stdout
+		if len(opts) != 1 || !strings.HasPrefix(opts[0], "?") {
+			break
+		}
+		consoleOptions, err := output.ParseConsoleOptions(opts[0])
+		if err != nil {
+			return nil, errors.Wrapf(
+				cmderrors.NewUsageError(
+					fmt.Sprintf(
+						"\nMust be of kind: %s",
+						output.ConsoleOptionsExample,
+					),
+				),
+				"Invalid console output '%s', %s",
+				out,
+				err,
+			)
+		}
+		o.ConsoleOptions = consoleOptions
+	case output.JSONOutputType, output.JSONV2OutputType:
 		if len(opts) != 1 || opts[0] == "" {
 			return nil, errors.Wrapf(
 				cmderrors.NewUsageError(
 					fmt.Sprintf(
 						"\nMust be of kind: %s",
-						output.Example(output.JSONOutputType),
+						output.Example(o.Key),
 					),
 				),
 				"Invalid json output '%s'",
@@ -486,11 +758,240 @@ func parseOutputFlag(out string) (*output.OutputConfig, error) {
 			)
 		}
 		o.Path = opts[0]
+	case output.SARIFOutputType:
+		if len(opts) != 1 || opts[0] == "" {
+			return nil, errors.Wrapf(
+				cmderrors.NewUsageError(
+					fmt.Sprintf(
+						"\nMust be of kind: %s",
+						output.Example(output.SARIFOutputType),
+					),
+				),
+				"Invalid sarif output '%s'",
+				out,
+			)
+		}
+		o.Path = opts[0]
+	case output.JUnitOutputType:
+		if len(opts) != 1 || opts[0] == "" {
+			return nil, errors.Wrapf(
+				cmderrors.NewUsageError(
+					fmt.Sprintf(
+						"\nMust be of kind: %s",
+						output.Example(output.JUnitOutputType),
+					),
+				),
+				"Invalid junit output '%s'",
+				out,
+			)
+		}
+		o.Path = opts[0]
+	case output.MarkdownOutputType:
+		if len(opts) != 1 || opts[0] == "" {
+			return nil, errors.Wrapf(
+				cmderrors.NewUsageError(
+					fmt.Sprintf(
+						"\nMust be of kind: %s",
+						output.Example(output.MarkdownOutputType),
+					),
+				),
+				"Invalid markdown output '%s'",
+				out,
+			)
+		}
+		o.Path = opts[0]
+	case output.OpenMetricsOutputType:
+		if len(opts) != 1 || opts[0] == "" {
+			return nil, errors.Wrapf(
+				cmderrors.NewUsageError(
+					fmt.Sprintf(
+						"\nMust be of kind: %s",
+						output.Example(output.OpenMetricsOutputType),
+					),
+				),
+				"Invalid openmetrics output '%s'",
+				out,
+			)
+		}
+		o.Path = opts[0]
+	case output.TemplateOutputType:
+		// The output path is what follows the last colon, the template path may contain some
+		sep := -1
+		if len(opts) == 1 {
+			sep = strings.LastIndex(opts[0], ":")
+		}
+		if sep <= 0 || sep == len(opts[0])-1 {
+			return nil, errors.Wrapf(
+				cmderrors.NewUsageError(
+					fmt.Sprintf(
+						"\nMust be of kind: %s",
+						output.Example(output.TemplateOutputType),
+					),
+				),
+				"Invalid template output '%s'",
+				out,
+			)
+		}
+		o.TemplatePath = opts[0][:sep]
+		o.Path = opts[0][sep+1:]
+		if _, err := os.Stat(o.TemplatePath); err != nil {
+			return nil, errors.Wrapf(err, "Invalid template output '%s'", out)
+		}
+	case output.WebhookOutputType, output.SlackOutputType, output.TeamsOutputType:
+		// The URL may come with its own scheme, e.g. webhook://http://localhost:8080
+		url := strings.Join(opts, "://")
+		if url == "" || strings.HasSuffix(url, "://") {
+			return nil, errors.Wrapf(
+				cmderrors.NewUsageError(
+					fmt.Sprintf(
+						"\nMust be of kind: %s",
+						output.Example(o.Key),
+					),
+				),
+				"Invalid %s output '%s'",
+				o.Key,
+				out,
+			)
+		}
+		o.Path = url
 	}
 
 	return o, nil
 }
 
+func parseWebhookFlags(cmd *cobra.Command) (*output.WebhookOptions, error) {
+	options := output.DefaultWebhookOptions()
+	options.Headers, _ = cmd.Flags().GetStringToString("webhook-headers")
+	options.Secret, _ = cmd.Flags().GetString("webhook-secret")
+	options.Retries, _ = cmd.Flags().GetInt("webhook-retries")
+	options.Timeout, _ = cmd.Flags().GetDuration("webhook-timeout")
+	options.TemplatePath, _ = cmd.Flags().GetString("webhook-template")
+	options.Top, _ = cmd.Flags().GetInt("webhook-top")
+
+	if options.Retries < 0 {
+		return nil, errors.Errorf("--webhook-retries cannot be negative")
+	}
+	if options.Top < 0 {
+		return nil, errors.Errorf("--webhook-top cannot be negative")
+	}
+	if options.TemplatePath != "" {
+		if _, err := os.Stat(options.TemplatePath); err != nil {
+			return nil, errors.Wrap(err, "Invalid --webhook-template")
+		}
+	}
+	return options, nil
+}
+
+func parseFailOnFlag(failOn []string) ([]policy.Condition, error) {
+	conditions := make([]policy.Condition, 0, len(failOn))
+	for _, flag := range failOn {
+		condition, err := policy.Parse(flag)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+// parseToFlag validates cloud providers and removes duplicates
+func parseToFlag(to []string) ([]string, error) {
+	remotes := make([]string, 0, len(to))
+	for _, r := range to {
+		if !remote.IsSupported(r) {
+			return nil, errors.Errorf(
+				"unsupported cloud provider '%s'\nValid values are: %s",
+				r,
+				strings.Join(remote.GetSupportedRemotes(), ","),
+			)
+		}
+		if !isScanning(remotes, r) {
+			remotes = append(remotes, r)
+		}
+	}
+	return remotes, nil
+}
+
+func isScanning(to []string, remote string) bool {
+	for _, r := range to {
+		if r == remote {
+			return true
+		}
+	}
+	return false
+}
+
+func validateAWSRegions(regions []string, to []string) error {
+	if len(regions) == 0 {
+		return nil
+	}
+	if !isScanning(to, common.RemoteAWSTerraform) {
+		return errors.Errorf("--aws-regions can only be used when scanning %s", common.RemoteAWSTerraform)
+	}
+	for _, region := range regions {
+		if region == "" {
+			return errors.New("AWS region cannot be empty")
+		}
+		if region == aws.AllRegions && len(regions) > 1 {
+			return errors.Errorf("\"%s\" cannot be combined with other AWS regions", aws.AllRegions)
+		}
+	}
+	return nil
+}
+
+func validateAWSAccounts(opts aws.Options, to []string) error {
+	if !opts.IsMultiAccount() {
+		return nil
+	}
+	if !isScanning(to, common.RemoteAWSTerraform) {
+		return errors.Errorf("--aws-accounts and --aws-organization can only be used when scanning %s", common.RemoteAWSTerraform)
+	}
+	for _, account := range opts.Accounts {
+		if !aws.IsValidAccountID(account) {
+			return errors.Errorf("Invalid AWS account ID '%s', expected 12 digits", account)
+		}
+	}
+	if !strings.Contains(opts.AssumeRoleARN, aws.AccountIDPlaceholder) {
+		return errors.Errorf("--aws-assume-role must contain %s to assume a role in each scanned account", aws.AccountIDPlaceholder)
+	}
+	return nil
+}
+
+// parseStateAccountFlag binds IaC sources to AWS accounts from "SOURCE=ACCOUNT_ID" values
+func parseStateAccountFlag(stateAccounts []string, from []config.SupplierConfig, opts aws.Options) error {
+	if len(stateAccounts) == 0 {
+		return nil
+	}
+	if !opts.IsMultiAccount() {
+		return errors.New("--aws-state-account can only be used with --aws-accounts or --aws-organization")
+	}
+	for _, stateAccount := range stateAccounts {
+		i := strings.LastIndex(stateAccount, "=")
+		if i == -1 {
+			return errors.Wrapf(
+				cmderrors.NewUsageError("\nExpected format is SOURCE=ACCOUNT_ID"),
+				"Unable to parse state account '%s'",
+				stateAccount,
+			)
+		}
+		source, account := stateAccount[:i], stateAccount[i+1:]
+		if !aws.IsValidAccountID(account) {
+			return errors.Errorf("Invalid AWS account ID '%s', expected 12 digits", account)
+		}
+		found := false
+		for j := range from {
+			if from[j].String() == source {
+				from[j].Account = account
+				found = true
+			}
+		}
+		if !found {
+			return errors.Errorf("IaC source '%s' is not part of --from", source)
+		}
+	}
+	return nil
+}
+
 func validateTfProviderVersionString(version string) error {
 	if version == "" {
 		return nil
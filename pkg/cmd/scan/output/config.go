@@ -5,8 +5,17 @@ import "fmt"
 type OutputConfig struct {
 	Key  string
 	Path string
+	// Only used by the template output
+	TemplatePath string
+	// Only used by the console output
+	ConsoleOptions *ConsoleOptions
+	// Only used by webhook, slack and teams outputs
+	WebhookOptions *WebhookOptions
 }
 
 func (o *OutputConfig) String() string {
+	if o.TemplatePath != "" {
+		return fmt.Sprintf("%s://%s:%s", o.Key, o.TemplatePath, o.Path)
+	}
 	return fmt.Sprintf("%s://%s", o.Key, o.Path)
 }
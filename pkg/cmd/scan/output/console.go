@@ -3,178 +3,543 @@ package output
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/aws/aws-sdk-go/aws/awsutil"
 	"github.com/fatih/color"
 	"github.com/mattn/go-isatty"
+	"github.com/pkg/errors"
 	"github.com/r3labs/diff/v2"
 	"github.com/snyk/driftctl/pkg/remote/alerts"
 	"github.com/yudai/gojsondiff"
 	"github.com/yudai/gojsondiff/formatter"
 
 	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/pkg/history"
 	"github.com/snyk/driftctl/pkg/resource"
 )
 
 const ConsoleOutputType = "console"
 const ConsoleOutputExample = "console://"
 
+const (
+	ConsoleGroupByType   = "type"
+	ConsoleGroupBySource = "source"
+	ConsoleGroupByModule = "module"
+	ConsoleGroupByTag    = "tag"
+)
+
+// ConsoleOptionsExample describes options accepted in the query of the console output
+const ConsoleOptionsExample = "console://?group-by=type|source|module|tag:KEY&summary"
+
+// ConsoleOptions changes how the console output lists resources
+type ConsoleOptions struct {
+	// GroupBy is one of the ConsoleGroupBy constants, each category of resources is grouped its own way when empty
+	GroupBy string
+	// Tag is the key of the tag resources are grouped by when grouping by tag
+	Tag string
+	// Summary only prints the summary of the scan, without listing resources
+	Summary bool
+}
+
+// ParseConsoleOptions parses options given as a query, e.g. ?group-by=tag:team&summary
+func ParseConsoleOptions(query string) (*ConsoleOptions, error) {
+	values, err := url.ParseQuery(strings.TrimPrefix(query, "?"))
+	if err != nil {
+		return nil, err
+	}
+
+	options := &ConsoleOptions{}
+	for key, value := range values {
+		v := value[len(value)-1]
+		switch key {
+		case "group-by":
+			if strings.HasPrefix(v, ConsoleGroupByTag+":") {
+				options.GroupBy = ConsoleGroupByTag
+				options.Tag = strings.TrimPrefix(v, ConsoleGroupByTag+":")
+				if options.Tag == "" {
+					return nil, errors.New("tag key is missing")
+				}
+				continue
+			}
+			switch v {
+			case ConsoleGroupByType, ConsoleGroupBySource, ConsoleGroupByModule:
+				options.GroupBy = v
+			default:
+				return nil, errors.Errorf("unable to group resources by '%s'", v)
+			}
+		case "summary":
+			if v == "" {
+				options.Summary = true
+				continue
+			}
+			options.Summary, err = strconv.ParseBool(v)
+			if err != nil {
+				return nil, errors.Errorf("invalid summary value '%s'", v)
+			}
+		default:
+			return nil, errors.Errorf("unknown option '%s'", key)
+		}
+	}
+	return options, nil
+}
+
 type Console struct {
 	summary string
+	options *ConsoleOptions
 }
 
 func NewConsole() *Console {
+	return NewConsoleWithOptions(nil)
+}
+
+func NewConsoleWithOptions(options *ConsoleOptions) *Console {
+	if options == nil {
+		options = &ConsoleOptions{}
+	}
 	return &Console{
 		`Total coverage is {{ analysis.Coverage }}`,
+		options,
 	}
 }
 
 func (c *Console) Write(analysis *analyser.Analysis) error {
+	if !c.options.Summary {
+		c.writeResources(analysis)
+	}
+
+	c.writeSummary(analysis)
+
+	if failed := analysis.FailedConditions(); len(failed) > 0 {
+		fmt.Println(color.RedString("Fail conditions met:"))
+		for _, condition := range failed {
+			fmt.Printf("  - %s (exit code %d): %s\n", color.RedString(condition.Condition), condition.ExitCode, condition.Message)
+		}
+	}
+
+	enumerationErrorMessage := ""
+	for _, a := range analysis.Alerts() {
+		for _, alert := range a {
+			fmt.Println(color.YellowString(alert.Message()))
+			if alert, ok := alert.(*alerts.RemoteAccessDeniedAlert); ok && enumerationErrorMessage == "" {
+				enumerationErrorMessage = alert.GetProviderMessage()
+			}
+		}
+	}
+
+	if enumerationErrorMessage != "" {
+		_, _ = fmt.Fprintf(os.Stderr, "\n%s\n", color.YellowString(enumerationErrorMessage))
+	}
+
+	return nil
+}
+
+func (c *Console) writeResources(analysis *analyser.Analysis) {
 	if analysis.Summary().TotalDeleted > 0 {
-		var sources []string
-		groupedBySource := make(map[string][]*resource.Resource)
+		fmt.Println("Found missing resources:")
+		deleted := analysis.Deleted()
+		printConsoleGroups(c.groupResources(deleted, ConsoleGroupBySource), "  ", func(indentBase string, i int) {
+			deletedResource := deleted[i]
+			humanStringSource := deletedResource.ResourceType()
+			if deletedResource.SourceString() != "" {
+				humanStringSource = deletedResource.SourceString()
+			}
+			humanString := fmt.Sprintf("%s- %s (%s)", indentBase, deletedResource.ResourceId(), humanStringSource)
 
-		for _, deletedResource := range analysis.Deleted() {
-			key := ""
-			if deletedResource.Source != nil {
-				key = deletedResource.Source.Source()
+			if humanAttrs := formatResourceAttributes(deletedResource); humanAttrs != "" {
+				humanString += fmt.Sprintf("\n%s    %s", indentBase, humanAttrs)
 			}
+			fmt.Println(humanString)
+		})
+	}
 
-			if _, exist := groupedBySource[key]; !exist {
-				groupedBySource[key] = []*resource.Resource{deletedResource}
-				continue
+	if analysis.Summary().TotalUnmanaged > 0 {
+		fmt.Println("Found resources not covered by IaC:")
+		unmanaged := analysis.Unmanaged()
+		groups := c.groupResources(unmanaged, ConsoleGroupByType)
+		// Resources are grouped by type by default, the type is only needed when grouped another way
+		byType := c.options.GroupBy == "" || c.options.GroupBy == ConsoleGroupByType
+		printConsoleGroups(groups, "  ", func(indentBase string, i int) {
+			res := unmanaged[i]
+			humanString := fmt.Sprintf("%s- %s", indentBase, res.ResourceId())
+			if !byType {
+				humanString += fmt.Sprintf(" (%s)", res.ResourceType())
 			}
+			if humanAttrs := formatResourceAttributes(res); humanAttrs != "" {
+				humanString += fmt.Sprintf("\n%s    %s", indentBase, humanAttrs)
+			}
+			fmt.Println(humanString)
+		})
+	}
 
-			groupedBySource[key] = append(groupedBySource[key], deletedResource)
+	if analysis.Summary().TotalDrifted > 0 {
+		fmt.Println("Found changed resources:")
+		differences := analysis.Differences()
+		resources := make([]*resource.Resource, 0, len(differences))
+		for _, difference := range differences {
+			resources = append(resources, difference.Res)
 		}
+		printConsoleGroups(c.groupResources(resources, ConsoleGroupBySource), "  ", func(indentBase string, i int) {
+			difference := differences[i]
+			humanStringSource := difference.Res.ResourceType()
+			if difference.Res.SourceString() != "" {
+				humanStringSource = difference.Res.SourceString()
+			}
+			humanString := fmt.Sprintf("%s- %s (%s):", indentBase, difference.Res.ResourceId(), humanStringSource)
+			whiteSpace := indentBase + "    "
+			if humanAttrs := formatResourceAttributes(difference.Res); humanAttrs != "" {
+				humanString += fmt.Sprintf("\n%s%s", whiteSpace, humanAttrs)
+				whiteSpace += "    "
+			}
+			fmt.Println(humanString)
+			printChangelog(whiteSpace, difference.Changelog)
+		})
+	}
 
-		for s := range groupedBySource {
-			sources = append(sources, s)
+	if analysis.Summary().TotalDuplicated > 0 {
+		fmt.Println("Found resources managed more than once:")
+		duplicated := analysis.Duplicated()
+		resources := make([]*resource.Resource, 0, len(duplicated))
+		for _, duplicate := range duplicated {
+			resources = append(resources, duplicate.Res)
 		}
-		sort.Strings(sources)
+		byType := c.options.GroupBy == "" || c.options.GroupBy == ConsoleGroupByType
+		printConsoleGroups(c.groupResources(resources, ConsoleGroupByType), "  ", func(indentBase string, i int) {
+			duplicate := duplicated[i]
+			humanString := fmt.Sprintf("%s- %s", indentBase, duplicate.Res.ResourceId())
+			if !byType {
+				humanString += fmt.Sprintf(" (%s)", duplicate.Res.ResourceType())
+			}
+			fmt.Println(humanString + ":")
+			for _, claim := range duplicate.Claims {
+				fmt.Printf("%s    - %s\n", indentBase, formatClaim(claim))
+			}
+		})
+	}
+}
 
-		fmt.Println("Found missing resources:")
+// formatClaim locates an IaC resource claiming a duplicated resource by its address and the state it was read from
+func formatClaim(res *resource.Resource) string {
+	if res.Src() == nil {
+		return fmt.Sprintf("%s.%s", res.ResourceType(), res.ResourceId())
+	}
+	return fmt.Sprintf("%s in %s", res.Address(), res.Src().Source())
+}
+
+// consoleGroup holds indexes of listed resources sharing the same header, groups of nested modules are children of
+// the group of their parent module
+type consoleGroup struct {
+	name     string
+	header   string
+	items    []int
+	children []*consoleGroup
+}
 
-		for _, source := range sources {
-			indentBase := "  "
-			if source != "" {
-				fmt.Print(color.BlueString("%sFrom %s\n", indentBase, source))
-				indentBase += indentBase
+// groupResources groups resources according to options, or by defaultGroupBy when no grouping was asked
+func (c *Console) groupResources(resources []*resource.Resource, defaultGroupBy string) []*consoleGroup {
+	groupBy := c.options.GroupBy
+	if groupBy == "" {
+		groupBy = defaultGroupBy
+	}
+
+	if groupBy == ConsoleGroupByModule {
+		return groupByModule(resources)
+	}
+
+	groups := make(map[string]*consoleGroup)
+	for i, res := range resources {
+		key := ""
+		switch groupBy {
+		case ConsoleGroupByType:
+			key = res.ResourceType()
+		case ConsoleGroupBySource:
+			if res.Src() != nil {
+				key = res.Src().Source()
 			}
-			for _, deletedResource := range groupedBySource[source] {
-				humanStringSource := deletedResource.ResourceType()
-				if deletedResource.SourceString() != "" {
-					humanStringSource = deletedResource.SourceString()
-				}
-				humanString := fmt.Sprintf("%s- %s (%s)", indentBase, deletedResource.ResourceId(), humanStringSource)
+		case ConsoleGroupByTag:
+			key = resourceTag(res, c.options.Tag)
+		}
 
-				if humanAttrs := formatResourceAttributes(deletedResource); humanAttrs != "" {
-					humanString += fmt.Sprintf("\n%s    %s", indentBase, humanAttrs)
-				}
-				fmt.Println(humanString)
+		group, exist := groups[key]
+		if !exist {
+			group = &consoleGroup{name: key}
+			switch {
+			case groupBy == ConsoleGroupByType:
+				group.header = fmt.Sprintf("%s:", key)
+			case groupBy == ConsoleGroupByTag && key == "":
+				group.header = color.BlueString("Without %s tag", c.options.Tag)
+			case groupBy == ConsoleGroupByTag:
+				group.header = color.BlueString("%s: %s", c.options.Tag, key)
+			case key != "":
+				group.header = color.BlueString("From %s", key)
 			}
+			groups[key] = group
 		}
+		group.items = append(group.items, i)
 	}
 
-	if analysis.Summary().TotalUnmanaged > 0 {
-		fmt.Println("Found resources not covered by IaC:")
-		unmanagedByType, keys := groupByType(analysis.Unmanaged())
-		for _, ty := range keys {
-			fmt.Printf("  %s:\n", ty)
-			for _, res := range unmanagedByType[ty] {
-				humanString := fmt.Sprintf("    - %s", res.ResourceId())
-				if humanAttrs := formatResourceAttributes(res); humanAttrs != "" {
-					humanString += fmt.Sprintf("\n        %s", humanAttrs)
-				}
-				fmt.Println(humanString)
+	return sortConsoleGroups(groups)
+}
+
+// groupByModule builds the tree of modules resources are declared in, resources without IaC source are listed first
+func groupByModule(resources []*resource.Resource) []*consoleGroup {
+	withoutSource := &consoleGroup{}
+	root := &consoleGroup{header: color.BlueString("Root module")}
+	modules := make(map[string]*consoleGroup)
+	for i, res := range resources {
+		if res.Src() == nil {
+			withoutSource.items = append(withoutSource.items, i)
+			continue
+		}
+		group := root
+		path := ""
+		for _, module := range splitModules(res.Src().Namespace()) {
+			path += "." + module
+			child, exist := modules[path]
+			if !exist {
+				child = &consoleGroup{name: module, header: color.BlueString(module)}
+				modules[path] = child
+				group.children = append(group.children, child)
 			}
+			group = child
 		}
+		group.items = append(group.items, i)
 	}
 
-	if analysis.Summary().TotalDrifted > 0 {
-		var sources []string
-		groupedBySource := make(map[string][]analyser.Difference)
-		for _, difference := range analysis.Differences() {
-			key := ""
-			if difference.Res.Source != nil {
-				key = difference.Res.Source.Source()
-			}
-			if _, exist := groupedBySource[key]; !exist {
-				groupedBySource[key] = []analyser.Difference{difference}
-				continue
-			}
-			groupedBySource[key] = append(groupedBySource[key], difference)
+	var groups []*consoleGroup
+	if len(withoutSource.items) > 0 {
+		groups = append(groups, withoutSource)
+	}
+	if len(root.items) > 0 || len(root.children) > 0 {
+		sortConsoleModules(root)
+		groups = append(groups, root)
+	}
+	return groups
+}
+
+func sortConsoleModules(group *consoleGroup) {
+	sort.SliceStable(group.children, func(i, j int) bool {
+		return group.children[i].name < group.children[j].name
+	})
+	for _, child := range group.children {
+		sortConsoleModules(child)
+	}
+}
+
+// splitModules splits a module address like module.a.module.b into its modules
+func splitModules(namespace string) []string {
+	if namespace == "" {
+		return nil
+	}
+	modules := strings.Split(namespace, ".module.")
+	for i := 1; i < len(modules); i++ {
+		modules[i] = "module." + modules[i]
+	}
+	return modules
+}
+
+func sortConsoleGroups(groups map[string]*consoleGroup) []*consoleGroup {
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	sorted := make([]*consoleGroup, 0, len(keys))
+	for _, key := range keys {
+		sorted = append(sorted, groups[key])
+	}
+	return sorted
+}
+
+// resourceTag returns the value of the given tag, or label for providers calling them this way
+func resourceTag(res *resource.Resource, key string) string {
+	if res.Attributes() == nil {
+		return ""
+	}
+	for _, field := range []string{"tags", "labels"} {
+		tags, ok := (*res.Attributes())[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if value, exist := tags[key]; exist && value != nil {
+			return fmt.Sprint(value)
 		}
+	}
+	return ""
+}
 
-		for s := range groupedBySource {
-			sources = append(sources, s)
+// printConsoleGroups prints headers of groups followed by their resources, indented below when there is a header
+func printConsoleGroups(groups []*consoleGroup, indentBase string, printItem func(indentBase string, i int)) {
+	for _, group := range groups {
+		indent := indentBase
+		if group.header != "" {
+			fmt.Printf("%s%s\n", indentBase, group.header)
+			indent += "  "
+		}
+		for _, i := range group.items {
+			printItem(indent, i)
 		}
-		sort.Strings(sources)
+		printConsoleGroups(group.children, indent, printItem)
+	}
+}
 
-		fmt.Println("Found changed resources:")
-		for _, source := range sources {
-			indentBase := "  "
-			if source != "" {
-				fmt.Print(color.BlueString("%sFrom %s\n", indentBase, source))
-				indentBase += indentBase
+func printChangelog(whiteSpace string, changelog analyser.Changelog) {
+	writeChangelog(os.Stdout, whiteSpace, changelog, true)
+}
+
+// writeChangelog renders a changelog the way the console does, without colors unless colored is set
+func writeChangelog(w io.Writer, whiteSpace string, changelog analyser.Changelog, colored bool) {
+	yellow, green, red := color.YellowString, color.GreenString, color.RedString
+	if !colored {
+		yellow, green, red = fmt.Sprintf, fmt.Sprintf, fmt.Sprintf
+	}
+	for _, change := range changelog {
+		path := strings.Join(change.Path, ".")
+		pref := fmt.Sprintf("%s %s:", yellow("~"), path)
+		if change.Type == diff.CREATE {
+			pref = fmt.Sprintf("%s %s:", green("+"), path)
+		} else if change.Type == diff.DELETE {
+			pref = fmt.Sprintf("%s %s:", red("-"), path)
+		}
+		if change.Type == diff.UPDATE {
+			if change.JsonString {
+				prefix := "           "
+				_, _ = fmt.Fprintf(w, "%s%s%s\n%s%s\n", whiteSpace, pref, severityString(change.Severity, colored), prefix, jsonDiff(change.From, change.To, colored && isatty.IsTerminal(os.Stdout.Fd())))
+				continue
 			}
-			for _, difference := range groupedBySource[source] {
-				humanStringSource := difference.Res.ResourceType()
-				if difference.Res.SourceString() != "" {
-					humanStringSource = difference.Res.SourceString()
-				}
-				humanString := fmt.Sprintf("%s- %s (%s):", indentBase, difference.Res.ResourceId(), humanStringSource)
-				whiteSpace := indentBase + "    "
-				if humanAttrs := formatResourceAttributes(difference.Res); humanAttrs != "" {
-					humanString += fmt.Sprintf("\n%s%s", whiteSpace, humanAttrs)
-					whiteSpace += "    "
+		}
+		_, _ = fmt.Fprintf(w, "%s%s %s => %s", whiteSpace, pref, prettify(change.From), prettify(change.To))
+		if change.Computed {
+			_, _ = fmt.Fprintf(w, " %s", yellow("(computed)"))
+		}
+		_, _ = fmt.Fprintf(w, "%s\n", severityString(change.Severity, colored))
+	}
+}
+
+// severityString renders the severity of a change, high and critical ones in red and medium ones in yellow
+func severityString(severity analyser.Severity, colored bool) string {
+	if severity == analyser.SeverityNone {
+		return ""
+	}
+	str := fmt.Sprintf(" [%s]", severity)
+	if !colored {
+		return str
+	}
+	switch {
+	case severity >= analyser.SeverityHigh:
+		return color.RedString(str)
+	case severity == analyser.SeverityMedium:
+		return color.YellowString(str)
+	}
+	return str
+}
+
+func (c *Console) WriteDiff(d *analyser.AnalysisDiff) error {
+	printResources := func(title string, resources []*resource.Resource) {
+		if len(resources) == 0 {
+			return
+		}
+		fmt.Println(title)
+		byType, keys := groupByType(resources)
+		for _, ty := range keys {
+			fmt.Printf("  %s:\n", ty)
+			for _, res := range byType[ty] {
+				humanString := fmt.Sprintf("    - %s", res.ResourceId())
+				if res.SourceString() != "" {
+					humanString += fmt.Sprintf(" (%s)", res.SourceString())
 				}
 				fmt.Println(humanString)
-				for _, change := range difference.Changelog {
-					path := strings.Join(change.Path, ".")
-					pref := fmt.Sprintf("%s %s:", color.YellowString("~"), path)
-					if change.Type == diff.CREATE {
-						pref = fmt.Sprintf("%s %s:", color.GreenString("+"), path)
-					} else if change.Type == diff.DELETE {
-						pref = fmt.Sprintf("%s %s:", color.RedString("-"), path)
-					}
-					if change.Type == diff.UPDATE {
-						if change.JsonString {
-							prefix := "           "
-							fmt.Printf("%s%s\n%s%s\n", whiteSpace, pref, prefix, jsonDiff(change.From, change.To, isatty.IsTerminal(os.Stdout.Fd())))
-							continue
-						}
-					}
-					fmt.Printf("%s%s %s => %s", whiteSpace, pref, prettify(change.From), prettify(change.To))
-					if change.Computed {
-						fmt.Printf(" %s", color.YellowString("(computed)"))
-					}
-					fmt.Printf("\n")
-				}
 			}
 		}
 	}
-
-	c.writeSummary(analysis)
-
-	enumerationErrorMessage := ""
-	for _, a := range analysis.Alerts() {
-		for _, alert := range a {
-			fmt.Println(color.YellowString(alert.Message()))
-			if alert, ok := alert.(*alerts.RemoteAccessDeniedAlert); ok && enumerationErrorMessage == "" {
-				enumerationErrorMessage = alert.GetProviderMessage()
+	printDifferences := func(title string, differences []analyser.Difference) {
+		if len(differences) == 0 {
+			return
+		}
+		fmt.Println(title)
+		for _, difference := range differences {
+			humanStringSource := difference.Res.ResourceType()
+			if difference.Res.SourceString() != "" {
+				humanStringSource = difference.Res.SourceString()
 			}
+			fmt.Printf("  - %s (%s):\n", difference.Res.ResourceId(), humanStringSource)
+			printChangelog("      ", difference.Changelog)
 		}
 	}
 
-	if enumerationErrorMessage != "" {
-		_, _ = fmt.Fprintf(os.Stderr, "\n%s\n", color.YellowString(enumerationErrorMessage))
+	printResources("Found new resources not covered by IaC:", d.Unmanaged)
+	printResources("Found resources now managed by IaC:", d.Managed)
+	printResources("Found new missing resources:", d.Deleted)
+	printDifferences("Found new changes:", d.IntroducedDifferences)
+	printDifferences("Found fixed changes:", d.FixedDifferences)
+
+	boldWriter := color.New(color.Bold)
+	if d.IsEmpty() {
+		fmt.Printf("Nothing changed between the two scans, coverage is %s\n", boldWriter.Sprintf("%d%%", d.Current.Coverage()))
+		return nil
 	}
 
+	delta := boldWriter.Sprintf("%+d%%", d.CoverageDelta())
+	if d.CoverageDelta() > 0 {
+		delta = color.New(color.Bold, color.FgGreen).Sprintf("%+d%%", d.CoverageDelta())
+	} else if d.CoverageDelta() < 0 {
+		delta = color.New(color.Bold, color.FgRed).Sprintf("%+d%%", d.CoverageDelta())
+	}
+	fmt.Printf(
+		"Coverage went from %s to %s (%s)\n",
+		boldWriter.Sprintf("%d%%", d.Previous.Coverage()),
+		boldWriter.Sprintf("%d%%", d.Current.Coverage()),
+		delta,
+	)
+
+	return nil
+}
+
+func (c *Console) WriteTrend(entries []history.Entry) error {
+	if len(entries) == 0 {
+		fmt.Println("No scan recorded in history yet")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DATE\tCOVERAGE\tMANAGED\tUNMANAGED\tMISSING\tCHANGED")
+	for i, entry := range entries {
+		coverage := fmt.Sprintf("%d%%", entry.Coverage)
+		if i > 0 {
+			coverage += fmt.Sprintf(" (%+d%%)", entry.Coverage-entries[i-1].Coverage)
+		}
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%d\t%d\t%d\t%d\n",
+			entry.Date.Format("2006-01-02 15:04:05"),
+			coverage,
+			entry.Summary.TotalManaged,
+			entry.Summary.TotalUnmanaged,
+			entry.Summary.TotalDeleted,
+			entry.Summary.TotalDrifted,
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	boldWriter := color.New(color.Bold)
+	first, last := entries[0], entries[len(entries)-1]
+	fmt.Printf(
+		"Coverage went from %s to %s over %d scan(s)\n",
+		boldWriter.Sprintf("%d%%", first.Coverage),
+		boldWriter.Sprintf("%d%%", last.Coverage),
+		len(entries),
+	)
+
 	return nil
 }
 
@@ -196,6 +561,17 @@ func (c Console) writeSummary(analysis *analyser.Analysis) {
 			analysis.Coverage(),
 		),
 	)
+	if providers := analysis.ProviderSummaries(); len(providers) > 1 {
+		for _, provider := range providers {
+			fmt.Printf(
+				"     - %s%% coverage for %s (%d/%d resource(s) managed)\n",
+				boldWriter.Sprintf("%d", provider.Coverage),
+				provider.Name,
+				provider.Summary.TotalManaged,
+				provider.Summary.TotalResources,
+			)
+		}
+	}
 	if !analysis.IsSync() {
 		managed := successWriter.Sprintf("0")
 		if analysis.Summary().TotalManaged > 0 {
@@ -222,6 +598,10 @@ func (c Console) writeSummary(analysis *analyser.Analysis) {
 			deleted = errorWriter.Sprintf("%d", analysis.Summary().TotalDeleted)
 		}
 		fmt.Printf(" - %s resource(s) found in a Terraform state but missing on the cloud provider\n", deleted)
+
+		if analysis.Summary().TotalDuplicated > 0 {
+			fmt.Printf(" - %s resource(s) managed by more than one Terraform resource\n", errorWriter.Sprintf("%d", analysis.Summary().TotalDuplicated))
+		}
 	}
 	if analysis.IsSync() {
 		fmt.Println(color.GreenString("Congrats! Your infrastructure is fully in sync."))
@@ -7,7 +7,9 @@ import (
 	"os"
 	"path"
 	"testing"
+	"time"
 
+	"github.com/snyk/driftctl/pkg/history"
 	"github.com/snyk/driftctl/pkg/resource"
 	"github.com/snyk/driftctl/pkg/resource/aws"
 	"github.com/snyk/driftctl/test/goldenfile"
@@ -162,3 +164,243 @@ func TestConsole_Write(t *testing.T) {
 		})
 	}
 }
+
+func TestConsole_Write_Options(t *testing.T) {
+	tests := []struct {
+		name       string
+		goldenfile string
+		options    *ConsoleOptions
+		analysis   *analyser.Analysis
+	}{
+		{
+			name:       "test console output grouped by type",
+			goldenfile: "output_group_by_type.txt",
+			options:    &ConsoleOptions{GroupBy: ConsoleGroupByType},
+		},
+		{
+			name:       "test console output grouped by source",
+			goldenfile: "output_group_by_source.txt",
+			options:    &ConsoleOptions{GroupBy: ConsoleGroupBySource},
+		},
+		{
+			name:       "test console output grouped by module",
+			goldenfile: "output_group_by_module.txt",
+			options:    &ConsoleOptions{GroupBy: ConsoleGroupByModule},
+		},
+		{
+			name:       "test console output grouped by tag",
+			goldenfile: "output_group_by_tag.txt",
+			options:    &ConsoleOptions{GroupBy: ConsoleGroupByTag, Tag: "team"},
+		},
+		{
+			name:       "test console summary output",
+			goldenfile: "output_summary.txt",
+			options:    &ConsoleOptions{Summary: true},
+		},
+		{
+			name:       "test console output with duplicated resources",
+			goldenfile: "output_duplicated.txt",
+			options:    &ConsoleOptions{},
+			analysis:   fakeAnalysisWithDuplicated(),
+		},
+		{
+			name:       "test console output with severities",
+			goldenfile: "output_severities.txt",
+			options:    &ConsoleOptions{},
+			analysis:   fakeAnalysisWithSeverities(),
+		},
+		{
+			name:       "test console output with multiple providers",
+			goldenfile: "output_multiple_providers.txt",
+			options:    &ConsoleOptions{},
+			analysis: func() *analyser.Analysis {
+				a := &analyser.Analysis{}
+				a.AddManaged(
+					&resource.Resource{Id: "test-id-1", Type: "aws_test_resource"},
+					&resource.Resource{Id: "test-id-2", Type: "aws_test_resource"},
+					&resource.Resource{Id: "test-id-3", Type: "github_test_resource"},
+				)
+				a.AddUnmanaged(
+					&resource.Resource{Id: "test-id-4", Type: "github_test_resource"},
+				)
+				a.Providers = []analyser.Provider{
+					{Name: "aws", Version: "3.19.0"},
+					{Name: "github", Version: "4.4.0"},
+				}
+				return a
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConsoleWithOptions(tt.options)
+
+			stdout := os.Stdout // keep backup of the real stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			analysis := tt.analysis
+			if analysis == nil {
+				analysis = fakeAnalysisForConsoleGrouping()
+			}
+			if err := c.Write(analysis); err != nil {
+				t.Errorf("Write() error = %v", err)
+			}
+
+			outC := make(chan []byte)
+			// copy the output in a separate goroutine so printing can't block indefinitely
+			go func() {
+				var buf bytes.Buffer
+				_, _ = io.Copy(&buf, r)
+				outC <- buf.Bytes()
+			}()
+
+			// back to normal state
+			assert.Nil(t, w.Close())
+			os.Stdout = stdout // restoring the real stdout
+			out := <-outC
+
+			expectedFilePath := path.Join("./testdata", tt.goldenfile)
+			if *goldenfile.Update == tt.goldenfile {
+				if err := ioutil.WriteFile(expectedFilePath, out, 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			expected, err := ioutil.ReadFile(expectedFilePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, string(expected), string(out))
+		})
+	}
+}
+
+func TestParseConsoleOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    *ConsoleOptions
+		wantErr string
+	}{
+		{
+			name:  "test without options",
+			query: "?",
+			want:  &ConsoleOptions{},
+		},
+		{
+			name:  "test group by module",
+			query: "?group-by=module",
+			want:  &ConsoleOptions{GroupBy: ConsoleGroupByModule},
+		},
+		{
+			name:  "test group by tag and summary",
+			query: "?group-by=tag:team&summary",
+			want:  &ConsoleOptions{GroupBy: ConsoleGroupByTag, Tag: "team", Summary: true},
+		},
+		{
+			name:  "test summary disabled",
+			query: "?summary=false",
+			want:  &ConsoleOptions{},
+		},
+		{
+			name:    "test group by tag without key",
+			query:   "?group-by=tag:",
+			wantErr: "tag key is missing",
+		},
+		{
+			name:    "test invalid summary",
+			query:   "?summary=maybe",
+			wantErr: "invalid summary value 'maybe'",
+		},
+		{
+			name:    "test unknown option",
+			query:   "?sort=type",
+			wantErr: "unknown option 'sort'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseConsoleOptions(tt.query)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConsole_WriteTrend(t *testing.T) {
+	tests := []struct {
+		name       string
+		goldenfile string
+		entries    []history.Entry
+	}{
+		{
+			name:       "test console trend output",
+			goldenfile: "output_trend.txt",
+			entries: []history.Entry{
+				{
+					ID:       "20211013-100000",
+					Date:     time.Date(2021, 10, 13, 10, 0, 0, 0, time.UTC),
+					Coverage: 40,
+					Summary:  analyser.Summary{TotalResources: 10, TotalManaged: 4, TotalUnmanaged: 5, TotalDeleted: 1, TotalDrifted: 2},
+				},
+				{
+					ID:       "20211014-100000",
+					Date:     time.Date(2021, 10, 14, 10, 0, 0, 0, time.UTC),
+					Coverage: 60,
+					Summary:  analyser.Summary{TotalResources: 10, TotalManaged: 6, TotalUnmanaged: 4, TotalDeleted: 0, TotalDrifted: 1},
+				},
+			},
+		},
+		{
+			name:       "test console trend output without entries",
+			goldenfile: "output_trend_empty.txt",
+			entries:    []history.Entry{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConsole()
+
+			stdout := os.Stdout // keep backup of the real stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			if err := c.WriteTrend(tt.entries); err != nil {
+				t.Errorf("WriteTrend() error = %v", err)
+			}
+
+			outC := make(chan []byte)
+			// copy the output in a separate goroutine so printing can't block indefinitely
+			go func() {
+				var buf bytes.Buffer
+				_, _ = io.Copy(&buf, r)
+				outC <- buf.Bytes()
+			}()
+
+			// back to normal state
+			assert.Nil(t, w.Close())
+			os.Stdout = stdout // restoring the real stdout
+			out := <-outC
+
+			expectedFilePath := path.Join("./testdata", tt.goldenfile)
+			if *goldenfile.Update == tt.goldenfile {
+				if err := ioutil.WriteFile(expectedFilePath, out, 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			expected, err := ioutil.ReadFile(expectedFilePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, string(expected), string(out))
+		})
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"embed"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"html/template"
+	"io"
 	"math"
 	"os"
 	"reflect"
@@ -16,8 +18,8 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws/awsutil"
 	"github.com/r3labs/diff/v2"
-	"github.com/snyk/driftctl/pkg/alerter"
 	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/pkg/history"
 	"github.com/snyk/driftctl/pkg/resource"
 )
 
@@ -33,22 +35,44 @@ type HTML struct {
 }
 
 type HTMLTemplateParams struct {
-	IsSync          bool
-	ScanDate        string
-	Coverage        int
-	Summary         analyser.Summary
-	Unmanaged       []*resource.Resource
-	Differences     []analyser.Difference
-	Deleted         []*resource.Resource
-	Alerts          alerter.Alerts
-	Stylesheet      template.CSS
-	ScanDuration    string
-	ProviderName    string
-	ProviderVersion string
-	LogoSvg         template.HTML
-	FaviconBase64   string
+	TemplateParams
+	ScanDate      string
+	ScanDuration  string
+	Stylesheet    template.CSS
+	LogoSvg       template.HTML
+	FaviconBase64 string
 }
 
+type HTMLDiffTemplateParams struct {
+	IsEmpty               bool
+	PreviousCoverage      int
+	Coverage              int
+	CoverageDelta         int
+	Unmanaged             []*resource.Resource
+	Managed               []*resource.Resource
+	Deleted               []*resource.Resource
+	IntroducedDifferences []analyser.Difference
+	FixedDifferences      []analyser.Difference
+	Stylesheet            template.CSS
+	LogoSvg               template.HTML
+	FaviconBase64         string
+}
+
+type HTMLTrendTemplateParams struct {
+	Entries        []history.Entry
+	FirstCoverage  int
+	LastCoverage   int
+	CoveragePoints string
+	Stylesheet     template.CSS
+	LogoSvg        template.HTML
+	FaviconBase64  string
+}
+
+const (
+	trendChartWidth  = 800
+	trendChartHeight = 200
+)
+
 func NewHTML(path string) *HTML {
 	return &HTML{path}
 }
@@ -64,6 +88,11 @@ func (c *HTML) Write(analysis *analyser.Analysis) error {
 		file = f
 	}
 
+	return RenderHTML(file, analysis)
+}
+
+// RenderHTML writes the HTML report of the given analysis
+func RenderHTML(w io.Writer, analysis *analyser.Analysis) error {
 	tmplFile, err := assets.ReadFile("assets/index.tmpl")
 	if err != nil {
 		return err
@@ -93,6 +122,9 @@ func (c *HTML) Write(analysis *analyser.Analysis) error {
 			for _, d := range analysis.Differences() {
 				resources = append(resources, d.Res)
 			}
+			for _, d := range analysis.Duplicated() {
+				resources = append(resources, d.Res)
+			}
 
 			return distinctResourceTypes(resources)
 		},
@@ -103,6 +135,30 @@ func (c *HTML) Write(analysis *analyser.Analysis) error {
 
 			return distinctIaCSources(resources)
 		},
+		"getModules": func() []string {
+			resources := make([]*resource.Resource, 0)
+			resources = append(resources, analysis.Deleted()...)
+			for _, d := range analysis.Differences() {
+				resources = append(resources, d.Res)
+			}
+
+			return distinctModules(resources)
+		},
+		"module":         htmlResourceModule,
+		"attributesJSON": htmlAttributes,
+		"changePath":     htmlChangePath,
+		"changeBefore": func(change analyser.Change) string {
+			if change.Type == diff.CREATE {
+				return ""
+			}
+			return htmlChangeValue(change.From, change.JsonString)
+		},
+		"changeAfter": func(change analyser.Change) string {
+			if change.Type == diff.DELETE {
+				return ""
+			}
+			return htmlChangeValue(change.To, change.JsonString)
+		},
 		"rate": func(count int) float64 {
 			if analysis.Summary().TotalResources == 0 {
 				return 0
@@ -110,42 +166,7 @@ func (c *HTML) Write(analysis *analyser.Analysis) error {
 			rate := 100 * float64(count) / float64(analysis.Summary().TotalResources)
 			return math.Floor(rate*100) / 100
 		},
-		"jsonDiff": func(ch analyser.Changelog) template.HTML {
-			var buf bytes.Buffer
-
-			whiteSpace := "&emsp;"
-			for _, change := range ch {
-				for i, v := range change.Path {
-					if _, err := strconv.Atoi(v); err == nil {
-						change.Path[i] = fmt.Sprintf("[%s]", v)
-					}
-				}
-				path := strings.Join(change.Path, ".")
-
-				switch change.Type {
-				case diff.CREATE:
-					pref := fmt.Sprintf("%s %s:", "+", path)
-					_, _ = fmt.Fprintf(&buf, "%s%s <span class=\"code-box-line-create\">%s</span>", whiteSpace, pref, prettify(change.To))
-				case diff.DELETE:
-					pref := fmt.Sprintf("%s %s:", "-", path)
-					_, _ = fmt.Fprintf(&buf, "%s%s <span class=\"code-box-line-delete\">%s</span>", whiteSpace, pref, prettify(change.From))
-				case diff.UPDATE:
-					prefix := fmt.Sprintf("%s %s:", "~", path)
-					if change.JsonString {
-						_, _ = fmt.Fprintf(&buf, "%s%s<br>%s%s<br>", whiteSpace, prefix, whiteSpace, jsonDiffHTML(change.From, change.To))
-						continue
-					}
-					_, _ = fmt.Fprintf(&buf, "%s%s <span class=\"code-box-line-delete\">%s</span> => <span class=\"code-box-line-create\">%s</span>", whiteSpace, prefix, htmlPrettify(change.From), htmlPrettify(change.To))
-				}
-
-				if change.Computed {
-					_, _ = fmt.Fprintf(&buf, " %s", "(computed)")
-				}
-				_, _ = fmt.Fprintf(&buf, "<br>")
-			}
-
-			return template.HTML(buf.String())
-		},
+		"jsonDiff": htmlChangelog,
 	}
 
 	tmpl, err := template.New("main").Funcs(funcMap).Parse(string(tmplFile))
@@ -154,23 +175,15 @@ func (c *HTML) Write(analysis *analyser.Analysis) error {
 	}
 
 	data := &HTMLTemplateParams{
-		IsSync:          analysis.IsSync(),
-		ScanDate:        analysis.Date.Format("Jan 02, 2006"),
-		Coverage:        analysis.Coverage(),
-		Summary:         analysis.Summary(),
-		Unmanaged:       analysis.Unmanaged(),
-		Differences:     analysis.Differences(),
-		Deleted:         analysis.Deleted(),
-		Alerts:          analysis.Alerts(),
-		Stylesheet:      template.CSS(styleFile),
-		ScanDuration:    analysis.Duration.Round(time.Second).String(),
-		ProviderName:    analysis.ProviderName,
-		ProviderVersion: analysis.ProviderVersion,
-		LogoSvg:         template.HTML(logoSvgFile),
-		FaviconBase64:   base64.StdEncoding.EncodeToString(faviconFile),
+		TemplateParams: NewTemplateParams(analysis),
+		ScanDate:       analysis.Date.Format("Jan 02, 2006"),
+		ScanDuration:   analysis.Duration.Round(time.Second).String(),
+		Stylesheet:     template.CSS(styleFile),
+		LogoSvg:        template.HTML(logoSvgFile),
+		FaviconBase64:  base64.StdEncoding.EncodeToString(faviconFile),
 	}
 
-	err = tmpl.Execute(file, data)
+	err = tmpl.Execute(w, data)
 	if err != nil {
 		return err
 	}
@@ -178,6 +191,185 @@ func (c *HTML) Write(analysis *analyser.Analysis) error {
 	return nil
 }
 
+func (c *HTML) WriteDiff(d *analyser.AnalysisDiff) error {
+	file := os.Stdout
+	if !isStdOut(c.path) {
+		f, err := os.OpenFile(c.path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		file = f
+	}
+
+	tmplFile, err := assets.ReadFile("assets/diff.tmpl")
+	if err != nil {
+		return err
+	}
+
+	styleFile, err := assets.ReadFile("assets/style.css")
+	if err != nil {
+		return err
+	}
+
+	logoSvgFile, err := assets.ReadFile("assets/driftctl_light.svg")
+	if err != nil {
+		return err
+	}
+
+	faviconFile, err := assets.ReadFile("assets/favicon.ico")
+	if err != nil {
+		return err
+	}
+
+	funcMap := template.FuncMap{
+		"jsonDiff": htmlChangelog,
+	}
+
+	tmpl, err := template.New("main").Funcs(funcMap).Parse(string(tmplFile))
+	if err != nil {
+		return err
+	}
+
+	data := &HTMLDiffTemplateParams{
+		IsEmpty:               d.IsEmpty(),
+		PreviousCoverage:      d.Previous.Coverage(),
+		Coverage:              d.Current.Coverage(),
+		CoverageDelta:         d.CoverageDelta(),
+		Unmanaged:             d.Unmanaged,
+		Managed:               d.Managed,
+		Deleted:               d.Deleted,
+		IntroducedDifferences: d.IntroducedDifferences,
+		FixedDifferences:      d.FixedDifferences,
+		Stylesheet:            template.CSS(styleFile),
+		LogoSvg:               template.HTML(logoSvgFile),
+		FaviconBase64:         base64.StdEncoding.EncodeToString(faviconFile),
+	}
+
+	return tmpl.Execute(file, data)
+}
+
+func (c *HTML) WriteTrend(entries []history.Entry) error {
+	file := os.Stdout
+	if !isStdOut(c.path) {
+		f, err := os.OpenFile(c.path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		file = f
+	}
+
+	tmplFile, err := assets.ReadFile("assets/trend.tmpl")
+	if err != nil {
+		return err
+	}
+
+	styleFile, err := assets.ReadFile("assets/style.css")
+	if err != nil {
+		return err
+	}
+
+	logoSvgFile, err := assets.ReadFile("assets/driftctl_light.svg")
+	if err != nil {
+		return err
+	}
+
+	faviconFile, err := assets.ReadFile("assets/favicon.ico")
+	if err != nil {
+		return err
+	}
+
+	funcMap := template.FuncMap{
+		"formatDate": func(t time.Time) string {
+			return t.Format("Jan 02, 2006 15:04")
+		},
+		"coverageDelta": func(i int) string {
+			if i == 0 {
+				return ""
+			}
+			return fmt.Sprintf("%+d%%", entries[i].Coverage-entries[i-1].Coverage)
+		},
+	}
+
+	tmpl, err := template.New("main").Funcs(funcMap).Parse(string(tmplFile))
+	if err != nil {
+		return err
+	}
+
+	data := &HTMLTrendTemplateParams{
+		Entries:        entries,
+		CoveragePoints: coveragePoints(entries),
+		Stylesheet:     template.CSS(styleFile),
+		LogoSvg:        template.HTML(logoSvgFile),
+		FaviconBase64:  base64.StdEncoding.EncodeToString(faviconFile),
+	}
+	if len(entries) > 0 {
+		data.FirstCoverage = entries[0].Coverage
+		data.LastCoverage = entries[len(entries)-1].Coverage
+	}
+
+	return tmpl.Execute(file, data)
+}
+
+// coveragePoints returns coordinates of the coverage of each entry in the trend chart
+func coveragePoints(entries []history.Entry) string {
+	points := make([]string, 0, len(entries))
+	for i, entry := range entries {
+		x := trendChartWidth / 2
+		if len(entries) > 1 {
+			x = i * trendChartWidth / (len(entries) - 1)
+		}
+		y := trendChartHeight - entry.Coverage*trendChartHeight/100
+		points = append(points, fmt.Sprintf("%d,%d", x, y))
+	}
+	return strings.Join(points, " ")
+}
+
+func htmlChangelog(ch analyser.Changelog) template.HTML {
+	var buf bytes.Buffer
+
+	whiteSpace := "&emsp;"
+	for _, change := range ch {
+		for i, v := range change.Path {
+			if _, err := strconv.Atoi(v); err == nil {
+				change.Path[i] = fmt.Sprintf("[%s]", v)
+			}
+		}
+		path := strings.Join(change.Path, ".")
+
+		switch change.Type {
+		case diff.CREATE:
+			pref := fmt.Sprintf("%s %s:", "+", path)
+			_, _ = fmt.Fprintf(&buf, "%s%s <span class=\"code-box-line-create\">%s</span>", whiteSpace, pref, prettify(change.To))
+		case diff.DELETE:
+			pref := fmt.Sprintf("%s %s:", "-", path)
+			_, _ = fmt.Fprintf(&buf, "%s%s <span class=\"code-box-line-delete\">%s</span>", whiteSpace, pref, prettify(change.From))
+		case diff.UPDATE:
+			prefix := fmt.Sprintf("%s %s:", "~", path)
+			if change.JsonString {
+				_, _ = fmt.Fprintf(&buf, "%s%s%s<br>%s%s<br>", whiteSpace, prefix, htmlSeverity(change.Severity), whiteSpace, jsonDiffHTML(change.From, change.To))
+				continue
+			}
+			_, _ = fmt.Fprintf(&buf, "%s%s <span class=\"code-box-line-delete\">%s</span> => <span class=\"code-box-line-create\">%s</span>", whiteSpace, prefix, htmlPrettify(change.From), htmlPrettify(change.To))
+		}
+
+		if change.Computed {
+			_, _ = fmt.Fprintf(&buf, " %s", "(computed)")
+		}
+		_, _ = fmt.Fprintf(&buf, "%s<br>", htmlSeverity(change.Severity))
+	}
+
+	return template.HTML(buf.String())
+}
+
+func htmlSeverity(severity analyser.Severity) string {
+	if severity == analyser.SeverityNone {
+		return ""
+	}
+	return fmt.Sprintf(" <span class=\"severity severity-%s\">%s</span>", severity, severity)
+}
+
 func distinctResourceTypes(resources []*resource.Resource) []string {
 	types := make([]string, 0)
 
@@ -220,6 +412,94 @@ func distinctIaCSources(resources []*resource.Resource) []string {
 	return types
 }
 
+// Module of resources declared at the root of a Terraform configuration
+const htmlRootModule = "root"
+
+func distinctModules(resources []*resource.Resource) []string {
+	modules := make([]string, 0)
+
+	for _, res := range resources {
+		module := htmlResourceModule(res)
+		if module == "" {
+			continue
+		}
+
+		found := false
+		for _, v := range modules {
+			if v == module {
+				found = true
+				break
+			}
+		}
+		if !found {
+			modules = append(modules, module)
+		}
+	}
+
+	return modules
+}
+
+// htmlResourceModule returns the module a resource is declared in, it is empty for resources without IaC source
+func htmlResourceModule(res *resource.Resource) string {
+	if res.Src() == nil {
+		return ""
+	}
+	if res.Src().Namespace() == "" {
+		return htmlRootModule
+	}
+	return res.Src().Namespace()
+}
+
+// htmlAttributes renders attributes as indented JSON, it is empty when the resource has no attribute
+func htmlAttributes(res *resource.Resource) string {
+	if res.Attributes() == nil || len(*res.Attributes()) == 0 {
+		return ""
+	}
+	content, err := htmlJSON(res.Attributes())
+	if err != nil {
+		return prettify(res.Attributes())
+	}
+	return content
+}
+
+func htmlChangePath(change analyser.Change) string {
+	path := make([]string, 0, len(change.Path))
+	for _, v := range change.Path {
+		if _, err := strconv.Atoi(v); err == nil {
+			v = fmt.Sprintf("[%s]", v)
+		}
+		path = append(path, v)
+	}
+	return strings.Join(path, ".")
+}
+
+// htmlChangeValue renders a value of a change as indented JSON, JSON strings are decoded first so that they get indented too
+func htmlChangeValue(value interface{}, jsonString bool) string {
+	if str, ok := value.(string); ok && jsonString {
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(str), &decoded); err == nil {
+			value = decoded
+		}
+	}
+	content, err := htmlJSON(value)
+	if err != nil {
+		return htmlPrettify(value)
+	}
+	return content
+}
+
+// htmlJSON indents the value as JSON, characters are escaped by the template
+func htmlJSON(value interface{}) (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
 func htmlPrettify(resource interface{}) string {
 	res := reflect.ValueOf(resource)
 	if resource == nil || res.Kind() == reflect.Ptr && res.IsNil() {
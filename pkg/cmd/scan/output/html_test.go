@@ -27,8 +27,7 @@ func TestHTML_Write(t *testing.T) {
 			analysis: func() *analyser.Analysis {
 				a := &analyser.Analysis{}
 				a.Date = time.Date(2021, 06, 10, 0, 0, 0, 0, &time.Location{})
-				a.ProviderName = "AWS"
-				a.ProviderVersion = "3.19.0"
+				a.Providers = []analyser.Provider{{Name: "aws", Version: "3.19.0"}}
 				return a
 			},
 			err: nil,
@@ -46,8 +45,7 @@ func TestHTML_Write(t *testing.T) {
 						Type: "aws_deleted_resource",
 					},
 				)
-				a.ProviderName = "AWS"
-				a.ProviderVersion = "3.19.0"
+				a.Providers = []analyser.Provider{{Name: "aws", Version: "3.19.0"}}
 				return a
 			},
 			err: nil,
@@ -113,6 +111,12 @@ func TestHTML_Write(t *testing.T) {
 					&resource.Resource{
 						Id:   "unmanaged-id-3",
 						Type: "aws_unmanaged_resource",
+						Attrs: &resource.Attributes{
+							"name": "unmanaged-3",
+							"tags": map[string]interface{}{
+								"Env": "<prod>",
+							},
+						},
 					},
 					&resource.Resource{
 						Id:   "unmanaged-id-4",
@@ -183,8 +187,7 @@ func TestHTML_Write(t *testing.T) {
 							},
 						},
 					}})
-				a.ProviderName = "AWS"
-				a.ProviderVersion = "3.19.0"
+				a.Providers = []analyser.Provider{{Name: "aws", Version: "3.19.0"}}
 				return a
 			},
 			err: nil,
@@ -226,8 +229,29 @@ func TestHTML_Write(t *testing.T) {
 							},
 						},
 					}})
-				a.ProviderName = "AWS"
-				a.ProviderVersion = "3.19.0"
+				a.Providers = []analyser.Provider{{Name: "aws", Version: "3.19.0"}}
+				return a
+			},
+			err: nil,
+		},
+		{
+			name:       "test html output with duplicated resources",
+			goldenfile: "output_duplicated.html",
+			analysis: func() *analyser.Analysis {
+				a := fakeAnalysisWithDuplicated()
+				a.Date = time.Date(2021, 06, 10, 0, 0, 0, 0, &time.Location{})
+				a.Duration = 91 * time.Second
+				return a
+			},
+			err: nil,
+		},
+		{
+			name:       "test html output with severities",
+			goldenfile: "output_severities.html",
+			analysis: func() *analyser.Analysis {
+				a := fakeAnalysisWithSeverities()
+				a.Date = time.Date(2021, 06, 10, 0, 0, 0, 0, &time.Location{})
+				a.Duration = 91 * time.Second
 				return a
 			},
 			err: nil,
@@ -383,3 +407,101 @@ func TestHTML_DistinctIaCSources(t *testing.T) {
 		})
 	}
 }
+
+func TestHTML_DistinctModules(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources []*resource.Resource
+		value     []string
+	}{
+		{
+			name:      "should return empty array",
+			resources: []*resource.Resource{},
+			value:     []string{},
+		},
+		{
+			name: "should return distinct list of modules",
+			resources: []*resource.Resource{
+				{
+					Id:   "deleted-id-1",
+					Type: "aws_deleted_resource",
+					Source: &resource.TerraformStateSource{
+						Module: "module.network",
+						Name:   "test",
+						State:  "tfstate://terraform.tfstate",
+					},
+				},
+				{
+					Id:   "unmanaged-id-1",
+					Type: "aws_unmanaged_resource",
+				},
+				{
+					Id:   "diff-id-1",
+					Type: "aws_diff_resource",
+					Source: &resource.TerraformStateSource{
+						Name:  "test",
+						State: "tfstate://terraform.tfstate",
+					},
+				},
+				{
+					Id:   "diff-id-2",
+					Type: "aws_diff_resource",
+					Source: &resource.TerraformStateSource{
+						Module: "module.network",
+						Name:   "other",
+						State:  "tfstate+s3://test/terraform.tfstate",
+					},
+				},
+			},
+			value: []string{"module.network", "root"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := distinctModules(tt.resources)
+			assert.Equal(t, tt.value, got)
+		})
+	}
+}
+
+func TestHTML_ChangeValue(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      interface{}
+		jsonString bool
+		expected   string
+	}{
+		{
+			name:     "nil value",
+			value:    nil,
+			expected: "null",
+		},
+		{
+			name:     "string value",
+			value:    "foobar",
+			expected: `"foobar"`,
+		},
+		{
+			name:     "list value",
+			value:    []string{"a", "b"},
+			expected: "[\n  \"a\",\n  \"b\"\n]",
+		},
+		{
+			name:       "json string value",
+			value:      `{"Version":"2012-10-17"}`,
+			jsonString: true,
+			expected:   "{\n  \"Version\": \"2012-10-17\"\n}",
+		},
+		{
+			name:       "invalid json string value",
+			value:      `{"Version"`,
+			jsonString: true,
+			expected:   `"{\"Version\""`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, htmlChangeValue(tt.value, tt.jsonString))
+		})
+	}
+}
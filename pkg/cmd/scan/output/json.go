@@ -5,20 +5,52 @@ import (
 	"os"
 
 	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/pkg/history"
 )
 
 const JSONOutputType = "json"
 const JSONOutputExample = "json://PATH/TO/FILE.json"
 
+// JSONV2OutputType writes the version 2 of the JSON schema, keeping attributes and addresses of every resource
+const JSONV2OutputType = "json+v2"
+const JSONV2OutputExample = "json+v2://PATH/TO/FILE.json"
+
 type JSON struct {
-	path string
+	path    string
+	version int
 }
 
 func NewJSON(path string) *JSON {
-	return &JSON{path}
+	return &JSON{path, analyser.SchemaVersion1}
+}
+
+func NewJSONV2(path string) *JSON {
+	return &JSON{path, analyser.SchemaVersion2}
+}
+
+// versionedAnalysis serializes an analysis with the given schema version
+type versionedAnalysis struct {
+	*analyser.Analysis
+	version int
+}
+
+func (v versionedAnalysis) MarshalJSON() ([]byte, error) {
+	return v.Analysis.MarshalJSONVersion(v.version)
 }
 
 func (c *JSON) Write(analysis *analyser.Analysis) error {
+	return c.write(versionedAnalysis{analysis, c.version})
+}
+
+func (c *JSON) WriteDiff(diff *analyser.AnalysisDiff) error {
+	return c.write(diff)
+}
+
+func (c *JSON) WriteTrend(entries []history.Entry) error {
+	return c.write(entries)
+}
+
+func (c *JSON) write(v interface{}) error {
 	file := os.Stdout
 	if !isStdOut(c.path) {
 		f, err := os.OpenFile(c.path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0600)
@@ -29,7 +61,7 @@ func (c *JSON) Write(analysis *analyser.Analysis) error {
 		file = f
 	}
 
-	json, err := json.MarshalIndent(analysis, "", "\t")
+	json, err := json.MarshalIndent(v, "", "\t")
 	if err != nil {
 		return err
 	}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -87,6 +88,33 @@ func TestJSON_Write(t *testing.T) {
 	}
 }
 
+func TestJSON_Write_V2(t *testing.T) {
+	analysis := fakeAnalysis(analyser.AnalyzerOptions{})
+	analysis.Date = time.Date(2021, 10, 13, 10, 0, 0, 0, time.UTC)
+	analysis.Duration = 12 * time.Second
+
+	resultPath := path.Join(t.TempDir(), "result.json")
+	if err := NewJSONV2(resultPath).Write(analysis); err != nil {
+		t.Fatal(err)
+	}
+	result, err := ioutil.ReadFile(resultPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedFilePath := path.Join("./testdata/", "output_v2.json")
+	if *goldenfile.Update == "output_v2.json" {
+		if err := ioutil.WriteFile(expectedFilePath, result, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := ioutil.ReadFile(expectedFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(expected), string(result))
+}
+
 func TestJSON_Write_stdout(t *testing.T) {
 	type args struct {
 		analysis *analyser.Analysis
@@ -0,0 +1,230 @@
+package output
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/pkg/resource"
+)
+
+const JUnitOutputType = "junit"
+const JUnitOutputExample = "junit://PATH/TO/FILE.xml"
+
+// Name of the suite holding unmanaged resources, other suites are named after resource types
+const junitUnmanagedSuiteName = "unmanaged resources"
+
+// Name of the suite holding fail conditions met by the scan
+const junitFailedConditionsSuiteName = "fail conditions"
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Content string `xml:",cdata"`
+}
+
+type JUnit struct {
+	path string
+}
+
+func NewJUnit(path string) *JUnit {
+	return &JUnit{path}
+}
+
+func (c *JUnit) Write(analysis *analyser.Analysis) error {
+	file := os.Stdout
+	if !isStdOut(c.path) {
+		f, err := os.OpenFile(c.path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		file = f
+	}
+
+	differences := make(map[string]analyser.Difference, len(analysis.Differences()))
+	for _, difference := range analysis.Differences() {
+		differences[junitKey(difference.Res)] = difference
+	}
+
+	duplicates := make(map[string]analyser.Duplicate, len(analysis.Duplicated()))
+	for _, duplicate := range analysis.Duplicated() {
+		duplicates[junitKey(duplicate.Res)] = duplicate
+	}
+
+	suites := make(map[string]*junitTestSuite)
+	addTestCase := func(res *resource.Resource, failure *junitFailure) {
+		suite, exist := suites[res.ResourceType()]
+		if !exist {
+			suite = &junitTestSuite{Name: res.ResourceType()}
+			suites[res.ResourceType()] = suite
+		}
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			Name:      junitTestCaseName(res),
+			ClassName: res.ResourceType(),
+			Failure:   failure,
+		})
+	}
+
+	for _, res := range analysis.Managed() {
+		difference, drifted := differences[junitKey(res)]
+		if !drifted {
+			duplicate, duplicated := duplicates[junitKey(res)]
+			if !duplicated {
+				addTestCase(res, nil)
+				continue
+			}
+			delete(duplicates, junitKey(res))
+			addTestCase(res, newJUnitDuplicatedFailure(duplicate))
+			continue
+		}
+		delete(differences, junitKey(res))
+		addTestCase(difference.Res, newJUnitChangedFailure(difference))
+	}
+	// Drifted resources should be managed ones, it is not enforced by the analysis though
+	for _, difference := range analysis.Differences() {
+		if _, left := differences[junitKey(difference.Res)]; left {
+			addTestCase(difference.Res, newJUnitChangedFailure(difference))
+		}
+	}
+	for _, res := range analysis.Deleted() {
+		addTestCase(res, &junitFailure{
+			Message: "Resource is missing on the cloud provider",
+			Type:    "missing",
+		})
+	}
+
+	// Duplicated resources left are the drifted ones, they get their own test case
+	for _, duplicate := range analysis.Duplicated() {
+		if _, left := duplicates[junitKey(duplicate.Res)]; left {
+			addTestCase(duplicate.Res, newJUnitDuplicatedFailure(duplicate))
+		}
+	}
+
+	types := make([]string, 0, len(suites))
+	for ty := range suites {
+		types = append(types, ty)
+	}
+	sort.Strings(types)
+
+	output := junitTestSuites{Name: "driftctl", Suites: make([]junitTestSuite, 0, len(types)+1)}
+	for _, ty := range types {
+		output.Suites = append(output.Suites, *suites[ty])
+	}
+
+	if len(analysis.Unmanaged()) > 0 {
+		unmanaged := junitTestSuite{Name: junitUnmanagedSuiteName}
+		for _, res := range analysis.Unmanaged() {
+			unmanaged.TestCases = append(unmanaged.TestCases, junitTestCase{
+				Name:      junitTestCaseName(res),
+				ClassName: res.ResourceType(),
+				Failure: &junitFailure{
+					Message: "Resource is not managed by Terraform",
+					Type:    "unmanaged",
+				},
+			})
+		}
+		output.Suites = append(output.Suites, unmanaged)
+	}
+
+	if failed := analysis.FailedConditions(); len(failed) > 0 {
+		conditions := junitTestSuite{Name: junitFailedConditionsSuiteName}
+		for _, condition := range failed {
+			conditions.TestCases = append(conditions.TestCases, junitTestCase{
+				Name:      condition.Condition,
+				ClassName: junitFailedConditionsSuiteName,
+				Failure: &junitFailure{
+					Message: fmt.Sprintf("Fail condition met, exit code %d", condition.ExitCode),
+					Type:    "condition",
+					Content: condition.Message,
+				},
+			})
+		}
+		output.Suites = append(output.Suites, conditions)
+	}
+
+	for i := range output.Suites {
+		suite := &output.Suites[i]
+		suite.Tests = len(suite.TestCases)
+		for _, testCase := range suite.TestCases {
+			if testCase.Failure != nil {
+				suite.Failures++
+			}
+		}
+		output.Tests += suite.Tests
+		output.Failures += suite.Failures
+	}
+
+	content, err := xml.MarshalIndent(output, "", "\t")
+	if err != nil {
+		return err
+	}
+	if _, err := file.WriteString(xml.Header); err != nil {
+		return err
+	}
+	if _, err := file.Write(content); err != nil {
+		return err
+	}
+	return nil
+}
+
+func newJUnitChangedFailure(difference analyser.Difference) *junitFailure {
+	var changelog bytes.Buffer
+	writeChangelog(&changelog, "", difference.Changelog, false)
+	message := fmt.Sprintf("Resource has drifted, %d change(s) found", len(difference.Changelog))
+	if severity := difference.Severity(); severity != analyser.SeverityNone {
+		message = fmt.Sprintf("%s, highest severity is %s", message, severity)
+	}
+	return &junitFailure{
+		Message: message,
+		Type:    "changed",
+		Content: changelog.String(),
+	}
+}
+
+func newJUnitDuplicatedFailure(duplicate analyser.Duplicate) *junitFailure {
+	var claims bytes.Buffer
+	for _, claim := range duplicate.Claims {
+		fmt.Fprintf(&claims, "%s\n", formatClaim(claim))
+	}
+	return &junitFailure{
+		Message: fmt.Sprintf("Resource is managed by %d Terraform resources", len(duplicate.Claims)),
+		Type:    "duplicated",
+		Content: claims.String(),
+	}
+}
+
+func junitKey(res *resource.Resource) string {
+	return fmt.Sprintf("%s.%s", res.ResourceType(), res.ResourceId())
+}
+
+func junitTestCaseName(res *resource.Resource) string {
+	if res.SourceString() != "" {
+		return fmt.Sprintf("%s (%s)", res.ResourceId(), res.SourceString())
+	}
+	return res.ResourceId()
+}
@@ -0,0 +1,79 @@
+package output
+
+import (
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/test/goldenfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJUnit_Write(t *testing.T) {
+	tests := []struct {
+		name       string
+		goldenfile string
+		analysis   *analyser.Analysis
+		wantErr    bool
+	}{
+		{
+			name:       "test junit output",
+			goldenfile: "output_junit.xml",
+			analysis:   fakeAnalysis(analyser.AnalyzerOptions{}),
+			wantErr:    false,
+		},
+		{
+			name:       "test junit output when no infra",
+			goldenfile: "output_junit_empty.xml",
+			analysis:   &analyser.Analysis{},
+			wantErr:    false,
+		},
+		{
+			name:       "test junit output with duplicated resources",
+			goldenfile: "output_junit_duplicated.xml",
+			analysis:   fakeAnalysisWithDuplicated(),
+			wantErr:    false,
+		},
+		{
+			name:       "test junit output with severities",
+			goldenfile: "output_junit_severities.xml",
+			analysis:   fakeAnalysisWithSeverities(),
+			wantErr:    false,
+		},
+		{
+			name:       "test junit output with failed conditions",
+			goldenfile: "output_junit_failed_conditions.xml",
+			analysis:   fakeAnalysisWithFailedConditions(),
+			wantErr:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			tempFile, err := ioutil.TempFile(tempDir, "result")
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := NewJUnit(tempFile.Name())
+			if err := c.Write(tt.analysis); (err != nil) != tt.wantErr {
+				t.Errorf("Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			result, err := ioutil.ReadFile(tempFile.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+			expectedFilePath := path.Join("./testdata/", tt.goldenfile)
+			if *goldenfile.Update == tt.goldenfile {
+				if err := ioutil.WriteFile(expectedFilePath, result, 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			expected, err := ioutil.ReadFile(expectedFilePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, string(expected), string(result))
+		})
+	}
+}
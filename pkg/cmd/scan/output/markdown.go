@@ -0,0 +1,184 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/r3labs/diff/v2"
+
+	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/pkg/resource"
+)
+
+const MarkdownOutputType = "markdown"
+const MarkdownOutputExample = "markdown://PATH/TO/FILE.md"
+
+const (
+	// Comments are limited to 65536 characters on GitHub, keep some room for what is added around the report
+	markdownMaxLength = 60000
+	// Maximum number of rows or changed resources listed in each section
+	markdownMaxItems = 100
+)
+
+type Markdown struct {
+	path string
+}
+
+func NewMarkdown(path string) *Markdown {
+	return &Markdown{path}
+}
+
+func (c *Markdown) Write(analysis *analyser.Analysis) error {
+	file := os.Stdout
+	if !isStdOut(c.path) {
+		f, err := os.OpenFile(c.path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		file = f
+	}
+
+	if _, err := file.WriteString(RenderMarkdown(analysis)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// RenderMarkdown renders the analysis as Markdown, lists are truncated to fit in a pull request comment
+func RenderMarkdown(analysis *analyser.Analysis) string {
+	var b strings.Builder
+
+	summary := analysis.Summary()
+	b.WriteString("## driftctl scan report\n\n")
+	if analysis.IsSync() {
+		b.WriteString(":white_check_mark: Congrats! Your infrastructure is fully in sync.\n\n")
+	} else {
+		b.WriteString(":warning: Your infrastructure is not in sync.\n\n")
+	}
+	b.WriteString("| Coverage | Resources | Managed | Unmanaged | Missing | Changed |\n")
+	b.WriteString("|---|---|---|---|---|---|\n")
+	fmt.Fprintf(
+		&b,
+		"| **%d%%** | %d | %d | %d | %d | %d |\n",
+		analysis.Coverage(),
+		summary.TotalResources,
+		summary.TotalManaged,
+		summary.TotalUnmanaged,
+		summary.TotalDeleted,
+		summary.TotalDrifted,
+	)
+	if providers := analysis.ProviderSummaries(); len(providers) > 1 {
+		b.WriteString("\n")
+		for _, provider := range providers {
+			fmt.Fprintf(&b, "- %d%% coverage for %s (%d/%d resource(s) managed)\n", provider.Coverage, provider.Name, provider.Summary.TotalManaged, provider.Summary.TotalResources)
+		}
+	}
+	fmt.Fprintf(&b, "\nScanned on %s in %s\n", analysis.Date.Format("Jan 02, 2006"), analysis.Duration.Round(time.Second))
+
+	if failed := analysis.FailedConditions(); len(failed) > 0 {
+		b.WriteString("\n**Fail conditions met:**\n\n")
+		for _, condition := range failed {
+			fmt.Fprintf(&b, "- `%s` (exit code %d): %s\n", condition.Condition, condition.ExitCode, escapeMarkdown(condition.Message))
+		}
+	}
+
+	writeMarkdownResources(&b, "Missing resources", analysis.Deleted())
+	writeMarkdownResources(&b, "Unmanaged resources", analysis.Unmanaged())
+
+	if differences := analysis.Differences(); len(differences) > 0 {
+		items := make([]string, 0, len(differences))
+		for _, difference := range differences {
+			var item strings.Builder
+			fmt.Fprintf(&item, "**`%s`** (%s)\n\n```diff\n", difference.Res.ResourceId(), escapeMarkdown(markdownResourceSource(difference.Res)))
+			for _, change := range difference.Changelog {
+				writeMarkdownChange(&item, change)
+			}
+			item.WriteString("```\n\n")
+			items = append(items, item.String())
+		}
+		writeMarkdownSection(&b, "Changed resources", len(differences), "", items)
+	}
+
+	if duplicated := analysis.Duplicated(); len(duplicated) > 0 {
+		rows := make([]string, 0, len(duplicated))
+		for _, duplicate := range duplicated {
+			claims := make([]string, 0, len(duplicate.Claims))
+			for _, claim := range duplicate.Claims {
+				claims = append(claims, fmt.Sprintf("`%s`", escapeMarkdown(formatClaim(claim))))
+			}
+			rows = append(rows, fmt.Sprintf("| `%s` | %s | %s |\n", escapeMarkdown(duplicate.Res.ResourceId()), duplicate.Res.ResourceType(), strings.Join(claims, "<br>")))
+		}
+		writeMarkdownSection(&b, "Resources managed more than once", len(duplicated), "| Resource ID | Resource type | Managed by |\n|---|---|---|\n", rows)
+	}
+
+	return b.String()
+}
+
+func writeMarkdownResources(b *strings.Builder, title string, resources []*resource.Resource) {
+	if len(resources) == 0 {
+		return
+	}
+	rows := make([]string, 0, len(resources))
+	for _, res := range resources {
+		source := ""
+		if res.SourceString() != "" {
+			source = fmt.Sprintf("`%s`", res.SourceString())
+		}
+		rows = append(rows, fmt.Sprintf("| `%s` | %s | %s |\n", escapeMarkdown(res.ResourceId()), res.ResourceType(), source))
+	}
+	writeMarkdownSection(b, title, len(resources), "| Resource ID | Resource type | Source |\n|---|---|---|\n", rows)
+}
+
+// writeMarkdownSection writes items in a collapsible section, items that would exceed the size limits are only counted
+func writeMarkdownSection(b *strings.Builder, title string, count int, header string, items []string) {
+	fmt.Fprintf(b, "\n<details>\n<summary>%s (%d)</summary>\n\n", title, count)
+	b.WriteString(header)
+	for i, item := range items {
+		if i >= markdownMaxItems || b.Len()+len(item) > markdownMaxLength {
+			fmt.Fprintf(b, "\n_and %d more_\n", len(items)-i)
+			break
+		}
+		b.WriteString(item)
+	}
+	b.WriteString("\n</details>\n")
+}
+
+// writeMarkdownChange renders a change as lines of a diff block
+func writeMarkdownChange(b *strings.Builder, change analyser.Change) {
+	path := strings.Join(change.Path, ".")
+	computed := ""
+	if change.Computed {
+		computed = " (computed)"
+	}
+	if change.Severity != analyser.SeverityNone {
+		computed += fmt.Sprintf(" [%s]", change.Severity)
+	}
+	if change.Type != diff.CREATE {
+		writeMarkdownDiffLines(b, "-", fmt.Sprintf("%s: %s%s", path, prettify(change.From), computed))
+	}
+	if change.Type != diff.DELETE {
+		writeMarkdownDiffLines(b, "+", fmt.Sprintf("%s: %s%s", path, prettify(change.To), computed))
+	}
+}
+
+// writeMarkdownDiffLines prefixes every line of text so that multi-line values stay highlighted in the diff block
+func writeMarkdownDiffLines(b *strings.Builder, prefix, text string) {
+	for _, line := range strings.Split(text, "\n") {
+		fmt.Fprintf(b, "%s %s\n", prefix, line)
+	}
+}
+
+func markdownResourceSource(res *resource.Resource) string {
+	if res.SourceString() != "" {
+		return res.SourceString()
+	}
+	return res.ResourceType()
+}
+
+// escapeMarkdown escapes characters breaking tables and inline code
+func escapeMarkdown(s string) string {
+	return strings.NewReplacer("|", "\\|", "`", "'", "\n", " ").Replace(s)
+}
@@ -0,0 +1,132 @@
+package output
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/r3labs/diff/v2"
+	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/pkg/resource"
+	"github.com/snyk/driftctl/test/goldenfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdown_Write(t *testing.T) {
+	tests := []struct {
+		name       string
+		goldenfile string
+		analysis   func() *analyser.Analysis
+		wantErr    bool
+	}{
+		{
+			name:       "test markdown output",
+			goldenfile: "output.md",
+			analysis: func() *analyser.Analysis {
+				a := fakeAnalysis(analyser.AnalyzerOptions{})
+				a.Date = time.Date(2021, 10, 13, 10, 0, 0, 0, time.UTC)
+				a.Duration = 12 * time.Second
+				a.SetFailedConditions([]analyser.FailedCondition{
+					{Condition: "coverage<80", Message: "Coverage is 33%, expected at least 80%", ExitCode: 3},
+				})
+				return a
+			},
+			wantErr: false,
+		},
+		{
+			name:       "test markdown output when infrastructure is in sync",
+			goldenfile: "output_sync.md",
+			analysis: func() *analyser.Analysis {
+				a := fakeAnalysisNoDrift()
+				a.Date = time.Date(2021, 10, 13, 10, 0, 0, 0, time.UTC)
+				return a
+			},
+			wantErr: false,
+		},
+		{
+			name:       "test markdown output with duplicated resources",
+			goldenfile: "output_duplicated.md",
+			analysis: func() *analyser.Analysis {
+				a := fakeAnalysisWithDuplicated()
+				a.Date = time.Date(2021, 10, 13, 10, 0, 0, 0, time.UTC)
+				return a
+			},
+			wantErr: false,
+		},
+		{
+			name:       "test markdown output with severities",
+			goldenfile: "output_severities.md",
+			analysis: func() *analyser.Analysis {
+				a := fakeAnalysisWithSeverities()
+				a.Date = time.Date(2021, 10, 13, 10, 0, 0, 0, time.UTC)
+				return a
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			tempFile, err := ioutil.TempFile(tempDir, "result")
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := NewMarkdown(tempFile.Name())
+			if err := c.Write(tt.analysis()); (err != nil) != tt.wantErr {
+				t.Errorf("Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			result, err := ioutil.ReadFile(tempFile.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+			expectedFilePath := path.Join("./testdata/", tt.goldenfile)
+			if *goldenfile.Update == tt.goldenfile {
+				if err := ioutil.WriteFile(expectedFilePath, result, 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			expected, err := ioutil.ReadFile(expectedFilePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, string(expected), string(result))
+		})
+	}
+}
+
+func TestRenderMarkdown_Truncated(t *testing.T) {
+	a := analyser.NewAnalysis(analyser.AnalyzerOptions{})
+	for i := 0; i < markdownMaxItems+20; i++ {
+		a.AddDeleted(&resource.Resource{Id: fmt.Sprintf("deleted-%d", i), Type: "aws_s3_bucket"})
+	}
+	for i := 0; i < 5000; i++ {
+		a.AddUnmanaged(&resource.Resource{Id: fmt.Sprintf("%s-%d", strings.Repeat("x", 50), i), Type: "aws_s3_bucket"})
+	}
+
+	result := RenderMarkdown(a)
+
+	assert.LessOrEqual(t, len(result), markdownMaxLength+100)
+	assert.Contains(t, result, "<summary>Missing resources (120)</summary>")
+	assert.Contains(t, result, "\n_and 20 more_\n")
+	assert.Contains(t, result, "<summary>Unmanaged resources (5000)</summary>")
+	assert.NotContains(t, result, fmt.Sprintf("-%d`", 4999))
+	assert.True(t, strings.HasSuffix(result, "more_\n\n</details>\n"))
+}
+
+func Test_writeMarkdownChange(t *testing.T) {
+	b := &strings.Builder{}
+	writeMarkdownChange(b, analyser.Change{Change: diff.Change{
+		Type: diff.UPDATE,
+		Path: []string{"versioning"},
+		From: map[string]interface{}{"enabled": false},
+		To:   map[string]interface{}{"enabled": true},
+	}})
+
+	for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n") {
+		assert.Regexp(t, "^[-+] ", line)
+	}
+	assert.Equal(t, "- versioning: {\n-   enabled: false\n- }\n+ versioning: {\n+   enabled: true\n+ }\n", b.String())
+}
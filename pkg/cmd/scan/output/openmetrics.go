@@ -0,0 +1,234 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/pkg/resource"
+)
+
+const OpenMetricsOutputType = "openmetrics"
+const OpenMetricsOutputExample = "openmetrics://PATH/TO/FILE.prom"
+
+// openMetricsGroup identifies series of resource gauges
+type openMetricsGroup struct {
+	Type     string
+	Provider string
+	Source   string
+}
+
+type openMetricsCounts struct {
+	resources, managed, unmanaged, missing, changed, duplicated int
+}
+
+type OpenMetrics struct {
+	path string
+}
+
+func NewOpenMetrics(path string) *OpenMetrics {
+	return &OpenMetrics{path}
+}
+
+func (c *OpenMetrics) Write(analysis *analyser.Analysis) error {
+	if isStdOut(c.path) {
+		return writeOpenMetrics(os.Stdout, analysis)
+	}
+
+	// Collectors like the node_exporter textfile one may read the file at any time,
+	// write a temporary file next to it and rename it so that they never see a partial content
+	f, err := os.CreateTemp(filepath.Dir(c.path), "."+filepath.Base(c.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := writeOpenMetrics(f, analysis); err != nil {
+		f.Close()
+		return err
+	}
+	// Temporary files are only readable by their owner, collectors usually run as another user
+	if err := f.Chmod(0644); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), c.path)
+}
+
+func writeOpenMetrics(w io.Writer, analysis *analyser.Analysis) error {
+	var b strings.Builder
+
+	groups := make(map[openMetricsGroup]*openMetricsCounts)
+	count := func(res *resource.Resource) *openMetricsCounts {
+		group := openMetricsGroup{
+			Type:     res.ResourceType(),
+			Provider: resource.ResourceType(res.ResourceType()).Provider(),
+		}
+		if res.Src() != nil {
+			group.Source = res.Src().Source()
+		}
+		counts, exist := groups[group]
+		if !exist {
+			counts = &openMetricsCounts{}
+			groups[group] = counts
+		}
+		return counts
+	}
+	for _, res := range analysis.Managed() {
+		counts := count(res)
+		counts.resources++
+		counts.managed++
+	}
+	for _, res := range analysis.Unmanaged() {
+		counts := count(res)
+		counts.resources++
+		counts.unmanaged++
+	}
+	for _, res := range analysis.Deleted() {
+		counts := count(res)
+		counts.resources++
+		counts.missing++
+	}
+	for _, difference := range analysis.Differences() {
+		count(difference.Res).changed++
+	}
+	// Counted along the managed resource, which is the first claim
+	for _, duplicate := range analysis.Duplicated() {
+		count(duplicate.Claims[0]).duplicated++
+	}
+
+	keys := make([]openMetricsGroup, 0, len(groups))
+	for group := range groups {
+		keys = append(keys, group)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Type != keys[j].Type {
+			return keys[i].Type < keys[j].Type
+		}
+		return keys[i].Source < keys[j].Source
+	})
+
+	resourceGauges := []struct {
+		name  string
+		help  string
+		value func(counts *openMetricsCounts) int
+	}{
+		{"driftctl_resources", "Number of resources found in IaC or on cloud providers", func(c *openMetricsCounts) int { return c.resources }},
+		{"driftctl_managed_resources", "Number of resources found both in IaC and on cloud providers", func(c *openMetricsCounts) int { return c.managed }},
+		{"driftctl_unmanaged_resources", "Number of resources found on cloud providers but not in IaC", func(c *openMetricsCounts) int { return c.unmanaged }},
+		{"driftctl_missing_resources", "Number of resources found in IaC but missing on cloud providers", func(c *openMetricsCounts) int { return c.missing }},
+		{"driftctl_changed_resources", "Number of managed resources whose attributes changed on cloud providers", func(c *openMetricsCounts) int { return c.changed }},
+		{"driftctl_duplicated_resources", "Number of managed resources claimed by more than one IaC resource", func(c *openMetricsCounts) int { return c.duplicated }},
+	}
+	for _, gauge := range resourceGauges {
+		writeOpenMetricsHeader(&b, gauge.name, gauge.help)
+		for _, group := range keys {
+			fmt.Fprintf(
+				&b,
+				"%s{type=\"%s\",provider=\"%s\",source=\"%s\"} %d\n",
+				gauge.name,
+				escapeOpenMetricsLabel(group.Type),
+				escapeOpenMetricsLabel(group.Provider),
+				escapeOpenMetricsLabel(group.Source),
+				gauge.value(groups[group]),
+			)
+		}
+	}
+
+	writeOpenMetricsHeader(&b, "driftctl_coverage_percent", "Percentage of resources managed by IaC")
+	fmt.Fprintf(&b, "driftctl_coverage_percent %d\n", analysis.Coverage())
+
+	writeOpenMetricsHeader(&b, "driftctl_provider_coverage_percent", "Percentage of resources of the provider managed by IaC")
+	for _, provider := range analysis.ProviderSummaries() {
+		fmt.Fprintf(&b, "driftctl_provider_coverage_percent{provider=\"%s\"} %d\n", escapeOpenMetricsLabel(provider.Name), provider.Coverage)
+	}
+
+	// Unmanaged resources have no source, coverage is only broken down by type and provider
+	types := make(map[openMetricsGroup]*openMetricsCounts)
+	for group, counts := range groups {
+		key := openMetricsGroup{Type: group.Type, Provider: group.Provider}
+		if _, exist := types[key]; !exist {
+			types[key] = &openMetricsCounts{}
+		}
+		types[key].resources += counts.resources
+		types[key].managed += counts.managed
+	}
+	typeKeys := make([]openMetricsGroup, 0, len(types))
+	for key := range types {
+		typeKeys = append(typeKeys, key)
+	}
+	sort.Slice(typeKeys, func(i, j int) bool {
+		return typeKeys[i].Type < typeKeys[j].Type
+	})
+	writeOpenMetricsHeader(&b, "driftctl_type_coverage_percent", "Percentage of resources of the type managed by IaC")
+	for _, key := range typeKeys {
+		counts := types[key]
+		if counts.resources == 0 {
+			continue
+		}
+		fmt.Fprintf(
+			&b,
+			"driftctl_type_coverage_percent{type=\"%s\",provider=\"%s\"} %d\n",
+			escapeOpenMetricsLabel(key.Type),
+			escapeOpenMetricsLabel(key.Provider),
+			counts.managed*100/counts.resources,
+		)
+	}
+
+	// Changes of analysis written before severities were computed are not counted
+	severities := make(map[analyser.Severity]int)
+	for _, difference := range analysis.Differences() {
+		for _, change := range difference.Changelog {
+			severities[change.Severity]++
+		}
+	}
+	writeOpenMetricsHeader(&b, "driftctl_changes", "Number of changed attributes of each severity")
+	for _, name := range analyser.SeverityNames {
+		severity, _ := analyser.ParseSeverity(name)
+		fmt.Fprintf(&b, "driftctl_changes{severity=\"%s\"} %d\n", name, severities[severity])
+	}
+
+	writeOpenMetricsHeader(&b, "driftctl_scan_duration_seconds", "Duration of the scan")
+	fmt.Fprintf(&b, "driftctl_scan_duration_seconds %g\n", analysis.Duration.Seconds())
+
+	// Alerts not related to a resource type are labelled with an empty type
+	alertTypes := make([]string, 0, len(analysis.Alerts()))
+	for ty := range analysis.Alerts() {
+		alertTypes = append(alertTypes, ty)
+	}
+	sort.Strings(alertTypes)
+	writeOpenMetricsHeader(&b, "driftctl_alerts", "Number of alerts raised during the scan")
+	for _, ty := range alertTypes {
+		fmt.Fprintf(&b, "driftctl_alerts{type=\"%s\"} %d\n", escapeOpenMetricsLabel(ty), len(analysis.Alerts()[ty]))
+	}
+
+	writeOpenMetricsHeader(&b, "driftctl_failed_conditions", "Fail conditions met by the scan")
+	for _, condition := range analysis.FailedConditions() {
+		fmt.Fprintf(
+			&b,
+			"driftctl_failed_conditions{condition=\"%s\",exit_code=\"%d\"} 1\n",
+			escapeOpenMetricsLabel(condition.Condition),
+			condition.ExitCode,
+		)
+	}
+
+	b.WriteString("# EOF\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeOpenMetricsHeader(b *strings.Builder, name, help string) {
+	fmt.Fprintf(b, "# TYPE %s gauge\n# HELP %s %s.\n", name, name, help)
+}
+
+func escapeOpenMetricsLabel(value string) string {
+	return strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n").Replace(value)
+}
@@ -0,0 +1,94 @@
+package output
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/test/goldenfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenMetrics_Write(t *testing.T) {
+	tests := []struct {
+		name       string
+		goldenfile string
+		analysis   func() *analyser.Analysis
+		wantErr    bool
+	}{
+		{
+			name:       "test openmetrics output",
+			goldenfile: "output.prom",
+			analysis: func() *analyser.Analysis {
+				a := fakeAnalysisWithAlerts()
+				a.Duration = 12500 * time.Millisecond
+				return a
+			},
+			wantErr: false,
+		},
+		{
+			name:       "test openmetrics output when no infra",
+			goldenfile: "output_empty.prom",
+			analysis: func() *analyser.Analysis {
+				return &analyser.Analysis{}
+			},
+			wantErr: false,
+		},
+		{
+			name:       "test openmetrics output with duplicated resources",
+			goldenfile: "output_duplicated.prom",
+			analysis:   fakeAnalysisWithDuplicated,
+			wantErr:    false,
+		},
+		{
+			name:       "test openmetrics output with severities",
+			goldenfile: "output_severities.prom",
+			analysis:   fakeAnalysisWithSeverities,
+			wantErr:    false,
+		},
+		{
+			name:       "test openmetrics output with failed conditions",
+			goldenfile: "output_failed_conditions.prom",
+			analysis:   fakeAnalysisWithFailedConditions,
+			wantErr:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempFile := filepath.Join(t.TempDir(), "driftctl.prom")
+			c := NewOpenMetrics(tempFile)
+			if err := c.Write(tt.analysis()); (err != nil) != tt.wantErr {
+				t.Errorf("Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			result, err := ioutil.ReadFile(tempFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expectedFilePath := path.Join("./testdata/", tt.goldenfile)
+			if *goldenfile.Update == tt.goldenfile {
+				if err := ioutil.WriteFile(expectedFilePath, result, 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			expected, err := ioutil.ReadFile(expectedFilePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, string(expected), string(result))
+
+			// The temporary file must have been renamed
+			files, err := ioutil.ReadDir(filepath.Dir(tempFile))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if assert.Len(t, files, 1) {
+				// Collectors running as another user must be able to read it
+				assert.Equal(t, os.FileMode(0644), files[0].Mode().Perm())
+			}
+		})
+	}
+}
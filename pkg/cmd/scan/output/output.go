@@ -2,8 +2,11 @@ package output
 
 import (
 	"sort"
+	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/pkg/history"
 	"github.com/snyk/driftctl/pkg/output"
 )
 
@@ -11,18 +14,54 @@ type Output interface {
 	Write(analysis *analyser.Analysis) error
 }
 
+// DiffOutput is implemented by outputs able to render what changed between two scans
+type DiffOutput interface {
+	WriteDiff(diff *analyser.AnalysisDiff) error
+}
+
+// TrendOutput is implemented by outputs able to render the evolution of recorded scans
+type TrendOutput interface {
+	WriteTrend(entries []history.Entry) error
+}
+
 var supportedOutputTypes = []string{
 	ConsoleOutputType,
 	JSONOutputType,
+	JSONV2OutputType,
 	HTMLOutputType,
 	PlanOutputType,
+	SARIFOutputType,
+	JUnitOutputType,
+	MarkdownOutputType,
+	TemplateOutputType,
+	WebhookOutputType,
+	SlackOutputType,
+	TeamsOutputType,
+	OpenMetricsOutputType,
+}
+
+// Outputs implementing DiffOutput
+var supportedDiffOutputTypes = []string{
+	ConsoleOutputType,
+	JSONOutputType,
+	JSONV2OutputType,
+	HTMLOutputType,
 }
 
 var supportedOutputExample = map[string]string{
-	ConsoleOutputType: ConsoleOutputExample,
-	JSONOutputType:    JSONOutputExample,
-	HTMLOutputType:    HTMLOutputExample,
-	PlanOutputType:    PlanOutputExample,
+	ConsoleOutputType:     ConsoleOutputExample,
+	JSONOutputType:        JSONOutputExample,
+	JSONV2OutputType:      JSONV2OutputExample,
+	HTMLOutputType:        HTMLOutputExample,
+	PlanOutputType:        PlanOutputExample,
+	SARIFOutputType:       SARIFOutputExample,
+	JUnitOutputType:       JUnitOutputExample,
+	MarkdownOutputType:    MarkdownOutputExample,
+	TemplateOutputType:    TemplateOutputExample,
+	WebhookOutputType:     WebhookOutputExample,
+	SlackOutputType:       SlackOutputExample,
+	TeamsOutputType:       TeamsOutputExample,
+	OpenMetricsOutputType: OpenMetricsOutputExample,
 }
 
 func SupportedOutputsExample() []string {
@@ -34,6 +73,16 @@ func SupportedOutputsExample() []string {
 	return examples
 }
 
+// SupportedDiffOutputsExample returns examples of the outputs able to compare scans
+func SupportedDiffOutputsExample() []string {
+	examples := make([]string, 0, len(supportedDiffOutputTypes))
+	for _, key := range supportedDiffOutputTypes {
+		examples = append(examples, supportedOutputExample[key])
+	}
+	sort.Strings(examples)
+	return examples
+}
+
 func Example(key string) string {
 	return supportedOutputExample[key]
 }
@@ -51,17 +100,51 @@ func GetOutput(config OutputConfig) Output {
 	switch config.Key {
 	case JSONOutputType:
 		return NewJSON(config.Path)
+	case JSONV2OutputType:
+		return NewJSONV2(config.Path)
 	case HTMLOutputType:
 		return NewHTML(config.Path)
 	case PlanOutputType:
 		return NewPlan(config.Path)
+	case SARIFOutputType:
+		return NewSARIF(config.Path)
+	case JUnitOutputType:
+		return NewJUnit(config.Path)
+	case MarkdownOutputType:
+		return NewMarkdown(config.Path)
+	case TemplateOutputType:
+		return NewTemplate(config.TemplatePath, config.Path)
+	case WebhookOutputType:
+		return NewWebhook(config.Path, config.WebhookOptions)
+	case SlackOutputType:
+		return NewSlack(config.Path, config.WebhookOptions)
+	case TeamsOutputType:
+		return NewTeams(config.Path, config.WebhookOptions)
+	case OpenMetricsOutputType:
+		return NewOpenMetrics(config.Path)
 	case ConsoleOutputType:
-		fallthrough
+		return NewConsoleWithOptions(config.ConsoleOptions)
 	default:
 		return NewConsole()
 	}
 }
 
+func GetDiffOutput(config OutputConfig) (DiffOutput, error) {
+	o, ok := GetOutput(config).(DiffOutput)
+	if !ok {
+		return nil, errors.Errorf("%s output cannot be used to compare scans, accepted formats are: %s", config.Key, strings.Join(SupportedDiffOutputsExample(), ","))
+	}
+	return o, nil
+}
+
+func GetTrendOutput(config OutputConfig) (TrendOutput, error) {
+	o, ok := GetOutput(config).(TrendOutput)
+	if !ok {
+		return nil, errors.Errorf("%s output cannot be used to render a trend report", config.Key)
+	}
+	return o, nil
+}
+
 // ShouldPrint indicate if we should use the global output or not (e.g. when outputting to stdout).
 func ShouldPrint(outputs []OutputConfig, quiet bool) bool {
 	for _, c := range outputs {
@@ -79,7 +162,7 @@ func GetPrinter(config OutputConfig, quiet bool) output.Printer {
 	}
 
 	switch config.Key {
-	case JSONOutputType:
+	case JSONOutputType, JSONV2OutputType:
 		if isStdOut(config.Path) {
 			return &output.VoidPrinter{}
 		}
@@ -89,6 +172,31 @@ func GetPrinter(config OutputConfig, quiet bool) output.Printer {
 			return &output.VoidPrinter{}
 		}
 		fallthrough
+	case SARIFOutputType:
+		if isStdOut(config.Path) {
+			return &output.VoidPrinter{}
+		}
+		fallthrough
+	case JUnitOutputType:
+		if isStdOut(config.Path) {
+			return &output.VoidPrinter{}
+		}
+		fallthrough
+	case MarkdownOutputType:
+		if isStdOut(config.Path) {
+			return &output.VoidPrinter{}
+		}
+		fallthrough
+	case TemplateOutputType:
+		if isStdOut(config.Path) {
+			return &output.VoidPrinter{}
+		}
+		fallthrough
+	case OpenMetricsOutputType:
+		if isStdOut(config.Path) {
+			return &output.VoidPrinter{}
+		}
+		fallthrough
 	case ConsoleOutputType:
 		fallthrough
 	default:
@@ -96,6 +204,11 @@ func GetPrinter(config OutputConfig, quiet bool) output.Printer {
 	}
 }
 
+// IsWebhook indicates if the output sends the analysis to an HTTP endpoint
+func IsWebhook(key string) bool {
+	return key == WebhookOutputType || key == SlackOutputType || key == TeamsOutputType
+}
+
 func isStdOut(path string) bool {
 	return path == "/dev/stdout" || path == "stdout"
 }
@@ -107,8 +107,7 @@ func fakeAnalysis(opts analyser.AnalyzerOptions) *analyser.Analysis {
 				},
 			},
 		}})
-	a.ProviderName = "AWS"
-	a.ProviderVersion = "3.19.0"
+	a.Providers = []analyser.Provider{{Name: "aws", Version: "3.19.0"}}
 	return a
 }
 
@@ -121,7 +120,6 @@ func fakeAnalysisWithAlerts() *analyser.Analysis {
 			alerts.NewRemoteAccessDeniedAlert(common.RemoteAWSTerraform, remoteerr.NewResourceListingErrorWithType(errors.New("dummy error"), "aws_sns", "aws_sns"), alerts.EnumerationPhase),
 		},
 	})
-	a.ProviderVersion = "3.19.0"
 	return a
 }
 
@@ -133,11 +131,102 @@ func fakeAnalysisNoDrift() *analyser.Analysis {
 			Type: "aws_managed_resource",
 		})
 	}
-	a.ProviderName = "AWS"
-	a.ProviderVersion = "3.19.0"
+	a.Providers = []analyser.Provider{{Name: "aws", Version: "3.19.0"}}
 	return &a
 }
 
+func fakeAnalysisWithDuplicated() *analyser.Analysis {
+	a := analyser.NewAnalysis(analyser.AnalyzerOptions{})
+	claims := []*resource.Resource{
+		{
+			Id:     "managed-id-0",
+			Type:   "aws_managed_resource",
+			Source: resource.NewTerraformStateSource("tfstate://first.tfstate", "", "managed"),
+		},
+		{
+			Id:     "managed-id-0",
+			Type:   "aws_managed_resource",
+			Source: resource.NewTerraformStateSource("tfstate://second.tfstate", "module.copy", "managed"),
+		},
+	}
+	a.AddManaged(
+		claims[0],
+		&resource.Resource{
+			Id:     "managed-id-1",
+			Type:   "aws_managed_resource",
+			Source: resource.NewTerraformStateSource("tfstate://first.tfstate", "", "other"),
+		},
+	)
+	a.AddDuplicated(analyser.Duplicate{
+		Res: &resource.Resource{
+			Id:   "managed-id-0",
+			Type: "aws_managed_resource",
+		},
+		Claims: claims,
+	})
+	a.Providers = []analyser.Provider{{Name: "aws", Version: "3.19.0"}}
+	return a
+}
+
+func fakeAnalysisWithSeverities() *analyser.Analysis {
+	a := analyser.NewAnalysis(analyser.AnalyzerOptions{Deep: true})
+	bucket := &resource.Resource{
+		Id:     "bucket",
+		Type:   "aws_s3_bucket",
+		Source: resource.NewTerraformStateSource("tfstate://terraform.tfstate", "", "bucket"),
+	}
+	group := &resource.Resource{
+		Id:     "sg-123",
+		Type:   "aws_security_group",
+		Source: resource.NewTerraformStateSource("tfstate://terraform.tfstate", "", "group"),
+	}
+	a.AddManaged(bucket, group)
+	a.AddDifference(
+		analyser.Difference{
+			Res: bucket,
+			Changelog: []analyser.Change{
+				{
+					Change:   diff.Change{Type: diff.UPDATE, Path: []string{"acl"}, From: "private", To: "public-read"},
+					Severity: analyser.SeverityHigh,
+				},
+				{
+					Change:   diff.Change{Type: diff.CREATE, Path: []string{"tags", "Env"}, To: "prod"},
+					Severity: analyser.SeverityLow,
+				},
+				{
+					Change:   diff.Change{Type: diff.UPDATE, Path: []string{"arn"}, From: "arn:aws:s3:::bucket", To: "arn:aws:s3:::other"},
+					Computed: true,
+					Severity: analyser.SeverityInfo,
+				},
+			},
+		},
+		analyser.Difference{
+			Res: group,
+			Changelog: []analyser.Change{
+				{
+					Change:   diff.Change{Type: diff.CREATE, Path: []string{"ingress", "0", "cidr_blocks", "0"}, To: "0.0.0.0/0"},
+					Severity: analyser.SeverityCritical,
+				},
+				{
+					Change:   diff.Change{Type: diff.DELETE, Path: []string{"egress", "0", "cidr_blocks", "0"}, From: "10.0.0.0/8"},
+					Severity: analyser.SeverityMedium,
+				},
+			},
+		},
+	)
+	a.Providers = []analyser.Provider{{Name: "aws", Version: "3.19.0"}}
+	return a
+}
+
+func fakeAnalysisWithFailedConditions() *analyser.Analysis {
+	a := fakeAnalysisWithSeverities()
+	a.SetFailedConditions([]analyser.FailedCondition{
+		{Condition: "severity:high", Message: "2 change(s) of severity high or higher: aws_s3_bucket.bucket: acl [high], aws_security_group.sg-123: ingress.0.cidr_blocks.0 [critical]", ExitCode: 8},
+		{Condition: "drift:acl", Message: "1 change(s) detected: aws_s3_bucket.bucket: acl", ExitCode: 5},
+	})
+	return a
+}
+
 func fakeAnalysisWithJsonFields() *analyser.Analysis {
 	a := analyser.NewAnalysis(analyser.AnalyzerOptions{Deep: true})
 	a.AddManaged(
@@ -194,8 +283,7 @@ func fakeAnalysisWithJsonFields() *analyser.Analysis {
 				},
 			},
 		}})
-	a.ProviderName = "AWS"
-	a.ProviderVersion = "3.19.0"
+	a.Providers = []analyser.Provider{{Name: "aws", Version: "3.19.0"}}
 	return a
 }
 
@@ -227,8 +315,7 @@ func fakeAnalysisWithoutAttrs() *analyser.Analysis {
 			Attrs: &resource.Attributes{},
 		},
 	)
-	a.ProviderName = "AWS"
-	a.ProviderVersion = "3.19.0"
+	a.Providers = []analyser.Provider{{Name: "aws", Version: "3.19.0"}}
 	return a
 }
 
@@ -296,8 +383,7 @@ func fakeAnalysisWithStringerResources() *analyser.Analysis {
 			},
 		},
 	}})
-	a.ProviderName = "AWS"
-	a.ProviderVersion = "3.19.0"
+	a.Providers = []analyser.Provider{{Name: "aws", Version: "3.19.0"}}
 	return a
 }
 
@@ -378,8 +464,7 @@ func fakeAnalysisWithComputedFields() *analyser.Analysis {
 			analyser.NewComputedDiffAlert(),
 		},
 	})
-	a.ProviderName = "AWS"
-	a.ProviderVersion = "3.19.0"
+	a.Providers = []analyser.Provider{{Name: "aws", Version: "3.19.0"}}
 	return a
 }
 
@@ -392,8 +477,7 @@ func fakeAnalysisWithAWSEnumerationError() *analyser.Analysis {
 			alerts.NewRemoteAccessDeniedAlert(common.RemoteAWSTerraform, remoteerr.NewResourceListingErrorWithType(errors.New("dummy error"), "aws_sns", "aws_sns"), alerts.EnumerationPhase),
 		},
 	})
-	a.ProviderName = "AWS"
-	a.ProviderVersion = "3.19.0"
+	a.Providers = []analyser.Provider{{Name: "aws", Version: "3.19.0"}}
 	return &a
 }
 
@@ -405,8 +489,7 @@ func fakeAnalysisWithGithubEnumerationError() *analyser.Analysis {
 			alerts.NewRemoteAccessDeniedAlert(common.RemoteGithubTerraform, remoteerr.NewResourceListingErrorWithType(errors.New("dummy error"), "github_team_membership", "github_team"), alerts.EnumerationPhase),
 		},
 	})
-	a.ProviderName = "AWS"
-	a.ProviderVersion = "3.19.0"
+	a.Providers = []analyser.Provider{{Name: "aws", Version: "3.19.0"}}
 	return &a
 }
 
@@ -444,11 +527,128 @@ func fakeAnalysisForJSONPlan() *analyser.Analysis {
 			},
 		},
 	)
-	a.ProviderName = "AWS"
-	a.ProviderVersion = "3.19.0"
+	a.Providers = []analyser.Provider{{Name: "aws", Version: "3.19.0"}}
+	return &a
+}
+
+func fakeAnalysisForJSONPlanWithDrifts() *analyser.Analysis {
+	a := analyser.Analysis{}
+	a.AddUnmanaged(
+		&resource.Resource{
+			Id:   "unmanaged-id-1",
+			Type: "aws_unmanaged_resource",
+			Attrs: &resource.Attributes{
+				"name": "First unmanaged resource",
+			},
+		},
+	)
+	managed := &resource.Resource{
+		Id:   "managed-id-1",
+		Type: "aws_managed_resource",
+		Attrs: &resource.Attributes{
+			"name": "First managed resource",
+			"tags": map[string]interface{}{
+				"env":   "prod",
+				"owner": "ops",
+			},
+			"ports": []interface{}{float64(80), float64(443), float64(8080)},
+		},
+		Source: &resource.TerraformStateSource{State: "tfstate://state.tfstate", Module: "module.network", Name: "managed", Key: "[0]"},
+	}
+	a.AddManaged(
+		managed,
+		&resource.Resource{
+			Id:   "managed-id-2",
+			Type: "aws_managed_resource",
+			Attrs: &resource.Attributes{
+				"name": "Second managed resource",
+			},
+			Source: resource.NewTerraformStateSource("tfstate://state.tfstate", "", "second"),
+		},
+	)
+	a.AddDeleted(
+		&resource.Resource{
+			Id:   "deleted-id-1",
+			Type: "aws_deleted_resource",
+			Attrs: &resource.Attributes{
+				"name": "Deleted resource",
+			},
+			Source: resource.NewTerraformStateSource("tfstate://state.tfstate", "module.storage", "deleted"),
+		},
+	)
+	a.AddDifference(analyser.Difference{
+		Res: managed,
+		Changelog: []analyser.Change{
+			{Change: diff.Change{Type: diff.UPDATE, Path: []string{"name"}, From: "First managed resource", To: "Renamed resource"}},
+			{Change: diff.Change{Type: diff.UPDATE, Path: []string{"tags", "env"}, From: "prod", To: "dev"}},
+			{Change: diff.Change{Type: diff.DELETE, Path: []string{"tags", "owner"}, From: "ops", To: nil}},
+			{Change: diff.Change{Type: diff.CREATE, Path: []string{"tags", "team"}, From: nil, To: "infra"}},
+			{Change: diff.Change{Type: diff.DELETE, Path: []string{"ports", "0"}, From: float64(80), To: nil}},
+			{Change: diff.Change{Type: diff.DELETE, Path: []string{"ports", "1"}, From: float64(443), To: nil}},
+		},
+	})
+	a.Providers = []analyser.Provider{{Name: "aws", Version: "3.19.0"}}
 	return &a
 }
 
+func fakeAnalysisForConsoleGrouping() *analyser.Analysis {
+	a := fakeAnalysis(analyser.AnalyzerOptions{})
+	a.AddUnmanaged(
+		&resource.Resource{
+			Id:   "unmanaged-id-3",
+			Type: "aws_unmanaged_resource",
+			Attrs: &resource.Attributes{
+				"tags": map[string]interface{}{
+					"team": "platform",
+				},
+			},
+		},
+		&resource.Resource{
+			Id:   "unmanaged-id-4",
+			Type: "google_unmanaged_resource",
+			Attrs: &resource.Attributes{
+				"labels": map[string]interface{}{
+					"team": "data",
+				},
+			},
+		},
+	)
+	a.AddDeleted(
+		&resource.Resource{
+			Id:   "deleted-id-3",
+			Type: "aws_deleted_resource",
+			Source: &resource.TerraformStateSource{
+				State:  "tfstate://delete_state.tfstate",
+				Module: "module.network.module.subnets",
+				Name:   "subnet",
+			},
+			Attrs: &resource.Attributes{
+				"tags": map[string]interface{}{
+					"team": "platform",
+				},
+			},
+		},
+		&resource.Resource{
+			Id:   "deleted-id-4",
+			Type: "aws_deleted_resource",
+			Source: &resource.TerraformStateSource{
+				State: "tfstate://other_state.tfstate",
+				Name:  "root",
+			},
+		},
+		&resource.Resource{
+			Id:   "deleted-id-5",
+			Type: "aws_deleted_resource",
+			Source: &resource.TerraformStateSource{
+				State:  "tfstate://other_state.tfstate",
+				Module: "module.network",
+				Name:   "vpc",
+			},
+		},
+	)
+	return a
+}
+
 func fakeAnalysisWithoutDeep() *analyser.Analysis {
 	a := analyser.Analysis{}
 	a.AddUnmanaged(
@@ -460,11 +660,51 @@ func fakeAnalysisWithoutDeep() *analyser.Analysis {
 			},
 		},
 	)
-	a.ProviderName = "AWS"
-	a.ProviderVersion = "3.19.0"
+	a.Providers = []analyser.Provider{{Name: "aws", Version: "3.19.0"}}
 	return &a
 }
 
+func TestSupportedOutputsExample(t *testing.T) {
+	want := []string{
+		"console://",
+		"html://PATH/TO/FILE.html",
+		"json+v2://PATH/TO/FILE.json",
+		"json://PATH/TO/FILE.json",
+		"junit://PATH/TO/FILE.xml",
+		"markdown://PATH/TO/FILE.md",
+		"openmetrics://PATH/TO/FILE.prom",
+		"plan://PATH/TO/FILE.json",
+		"sarif://PATH/TO/FILE.sarif",
+		"slack://hooks.slack.com/services/PATH",
+		"teams://HOST/PATH",
+		"template://PATH/TO/TEMPLATE.tmpl:PATH/TO/FILE",
+		"webhook://HOST/PATH",
+	}
+	if got := SupportedOutputsExample(); !reflect.DeepEqual(got, want) {
+		t.Errorf("SupportedOutputsExample() = %v, want %v", got, want)
+	}
+}
+
+func TestGetDiffOutput(t *testing.T) {
+	for _, key := range supportedOutputTypes {
+		t.Run(key, func(t *testing.T) {
+			supported := false
+			for _, diffKey := range supportedDiffOutputTypes {
+				supported = supported || diffKey == key
+			}
+
+			o, err := GetDiffOutput(OutputConfig{Key: key, Path: "https://example.com/hook"})
+			if supported && (err != nil || o == nil) {
+				t.Errorf("GetDiffOutput() error = %v, want a diff output", err)
+			}
+			want := key + " output cannot be used to compare scans, accepted formats are: console://,html://PATH/TO/FILE.html,json+v2://PATH/TO/FILE.json,json://PATH/TO/FILE.json"
+			if !supported && (err == nil || err.Error() != want) {
+				t.Errorf("GetDiffOutput() error = %v, want %s", err, want)
+			}
+		})
+	}
+}
+
 func TestGetPrinter(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -498,6 +738,12 @@ func TestGetPrinter(t *testing.T) {
 			key:  JSONOutputType,
 			want: &output.VoidPrinter{},
 		},
+		{
+			name: "json v2 stdout output",
+			path: "stdout",
+			key:  JSONV2OutputType,
+			want: &output.VoidPrinter{},
+		},
 		{
 			name: "console stdout output",
 			path: "stdout",
@@ -529,6 +775,72 @@ func TestGetPrinter(t *testing.T) {
 			key:  PlanOutputType,
 			want: &output.VoidPrinter{},
 		},
+		{
+			name: "sarif file output",
+			path: "/path/to/file",
+			key:  SARIFOutputType,
+			want: output.NewConsolePrinter(),
+		},
+		{
+			name: "sarif stdout output",
+			path: "stdout",
+			key:  SARIFOutputType,
+			want: &output.VoidPrinter{},
+		},
+		{
+			name: "junit file output",
+			path: "/path/to/file",
+			key:  JUnitOutputType,
+			want: output.NewConsolePrinter(),
+		},
+		{
+			name: "junit stdout output",
+			path: "stdout",
+			key:  JUnitOutputType,
+			want: &output.VoidPrinter{},
+		},
+		{
+			name: "markdown file output",
+			path: "/path/to/file",
+			key:  MarkdownOutputType,
+			want: output.NewConsolePrinter(),
+		},
+		{
+			name: "markdown stdout output",
+			path: "stdout",
+			key:  MarkdownOutputType,
+			want: &output.VoidPrinter{},
+		},
+		{
+			name: "template file output",
+			path: "/path/to/file",
+			key:  TemplateOutputType,
+			want: output.NewConsolePrinter(),
+		},
+		{
+			name: "template stdout output",
+			path: "stdout",
+			key:  TemplateOutputType,
+			want: &output.VoidPrinter{},
+		},
+		{
+			name: "openmetrics file output",
+			path: "/path/to/file",
+			key:  OpenMetricsOutputType,
+			want: output.NewConsolePrinter(),
+		},
+		{
+			name: "openmetrics stdout output",
+			path: "stdout",
+			key:  OpenMetricsOutputType,
+			want: &output.VoidPrinter{},
+		},
+		{
+			name: "webhook output",
+			path: "example.com/hook",
+			key:  WebhookOutputType,
+			want: output.NewConsolePrinter(),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
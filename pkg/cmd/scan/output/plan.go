@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
+
+	"github.com/r3labs/diff/v2"
 
 	"github.com/snyk/driftctl/pkg/analyser"
 	"github.com/snyk/driftctl/pkg/resource"
@@ -17,6 +20,14 @@ type plan struct {
 	FormatVersion   string        `json:"format_version,omitempty"`
 	PlannedValues   plannedValues `json:"planned_values,omitempty"`
 	ResourceChanges []rscChange   `json:"resource_changes,omitempty"`
+	// Not part of Terraform plans, tools reading them ignore it
+	Metadata *planMetadata `json:"metadata,omitempty"`
+}
+
+type planMetadata struct {
+	FailedConditions []analyser.FailedCondition `json:"failed_conditions,omitempty"`
+	// Highest severity of changes indexed by resource address
+	Severities map[string]analyser.Severity `json:"severities,omitempty"`
 }
 
 type plannedValues struct {
@@ -68,6 +79,7 @@ func (c *Plan) Write(analysis *analyser.Analysis) error {
 	output := plan{FormatVersion: FormatVersion}
 	output.PlannedValues.RootModule = addPlannedValues(analysis)
 	output.ResourceChanges = addResourceChanges(analysis)
+	output.Metadata = addMetadata(analysis)
 	jsonPlan, err := json.MarshalIndent(output, "", "\t")
 	if err != nil {
 		return err
@@ -78,51 +90,228 @@ func (c *Plan) Write(analysis *analyser.Analysis) error {
 	return nil
 }
 
+// addPlannedValues lists resources as found on cloud providers
 func addPlannedValues(analysis *analyser.Analysis) module {
-	managedRsc := listRsc(analysis.Managed())
-	unmanagedRsc := listRsc(analysis.Unmanaged())
+	differences := planDifferences(analysis)
+	managedRsc := listRsc(analysis.Managed(), differences)
+	unmanagedRsc := listRsc(analysis.Unmanaged(), differences)
 	return module{
 		Resources: append(managedRsc, unmanagedRsc...),
 	}
 }
 
-func listRsc(resources []*resource.Resource) []rsc {
+func listRsc(resources []*resource.Resource, differences map[string]analyser.Difference) []rsc {
 	var ret []rsc
 	for _, res := range resources {
+		values := planAttributes(res)
+		if difference, drifted := differences[planKey(res)]; drifted {
+			values = applyChangelog(values, difference.Changelog)
+		}
 		r := rsc{
-			Address:         fmt.Sprintf("%s.%s", res.ResourceType(), res.ResourceId()),
+			Address:         planAddress(res),
 			Type:            res.ResourceType(),
-			Name:            res.ResourceId(),
-			AttributeValues: *res.Attributes(),
+			Name:            planName(res),
+			AttributeValues: values,
 		}
 		ret = append(ret, r)
 	}
 	return ret
 }
 
+// addResourceChanges describes what applying IaC would do to the cloud, before values are the one read from IaC and after
+// values the one found on cloud providers
 func addResourceChanges(analysis *analyser.Analysis) []rscChange {
-	managedRsc := listRscChange(analysis.Managed(), "no-op")
-	unmanagedRsc := listRscChange(analysis.Unmanaged(), "create")
-	return append(managedRsc, unmanagedRsc...)
-}
+	differences := planDifferences(analysis)
 
-func listRscChange(resources []*resource.Resource, action string) []rscChange {
 	var ret []rscChange
-	for _, res := range resources {
-		r := rscChange{
-			Address: fmt.Sprintf("%s.%s", res.ResourceType(), res.ResourceId()),
-			Type:    res.ResourceType(),
-			Name:    res.ResourceId(),
-			Change: change{
-				Actions: []string{action},
-				After:   *res.Attributes(),
-			},
-		}
-		if action == "no-op" {
-			r.Change.Before = *res.Attributes()
+	for _, res := range analysis.Managed() {
+		if difference, drifted := differences[planKey(res)]; drifted {
+			ret = append(ret, newRscChange(res, "update", planAttributes(res), applyChangelog(planAttributes(res), difference.Changelog)))
+			continue
+		}
+		ret = append(ret, newRscChange(res, "no-op", planAttributes(res), planAttributes(res)))
+	}
+	for _, res := range analysis.Unmanaged() {
+		ret = append(ret, newRscChange(res, "create", nil, planAttributes(res)))
+	}
+	// Missing resources have been deleted out of band, there is nothing left after them
+	for _, res := range analysis.Deleted() {
+		ret = append(ret, newRscChange(res, "delete", planAttributes(res), nil))
+	}
+	// Plans have no action for resources managed more than once, every other address claiming them is listed as
+	// unchanged so that none of them disappears from the plan
+	for _, duplicate := range analysis.Duplicated() {
+		for _, claim := range duplicate.Claims[1:] {
+			ret = append(ret, newRscChange(claim, "no-op", planAttributes(claim), planAttributes(claim)))
 		}
-		ret = append(ret, r)
-
 	}
 	return ret
 }
+
+// addMetadata returns what the scan found on top of resource changes, nil when there is nothing to add
+func addMetadata(analysis *analyser.Analysis) *planMetadata {
+	severities := make(map[string]analyser.Severity)
+	// Changes of analysis written before severities were computed have none
+	for _, difference := range analysis.Differences() {
+		if severity := difference.Severity(); severity != analyser.SeverityNone {
+			severities[planAddress(difference.Res)] = severity
+		}
+	}
+	if len(analysis.FailedConditions()) == 0 && len(severities) == 0 {
+		return nil
+	}
+	metadata := &planMetadata{FailedConditions: analysis.FailedConditions()}
+	if len(severities) > 0 {
+		metadata.Severities = severities
+	}
+	return metadata
+}
+
+func planDifferences(analysis *analyser.Analysis) map[string]analyser.Difference {
+	differences := make(map[string]analyser.Difference, len(analysis.Differences()))
+	for _, difference := range analysis.Differences() {
+		differences[planKey(difference.Res)] = difference
+	}
+	return differences
+}
+
+func newRscChange(res *resource.Resource, action string, before, after map[string]interface{}) rscChange {
+	return rscChange{
+		Address: planAddress(res),
+		Type:    res.ResourceType(),
+		Name:    planName(res),
+		Change: change{
+			Actions: []string{action},
+			Before:  before,
+			After:   after,
+		},
+	}
+}
+
+// planAddress returns the full Terraform address of resources coming from IaC, fallback on the resource id otherwise
+func planAddress(res *resource.Resource) string {
+	if address := res.Address(); address != "" {
+		return address
+	}
+	return planKey(res)
+}
+
+func planName(res *resource.Resource) string {
+	if res.Src() != nil && res.Src().InternalName() != "" {
+		return res.Src().InternalName()
+	}
+	return res.ResourceId()
+}
+
+func planKey(res *resource.Resource) string {
+	return fmt.Sprintf("%s.%s", res.ResourceType(), res.ResourceId())
+}
+
+func planAttributes(res *resource.Resource) map[string]interface{} {
+	if res.Attributes() == nil {
+		return nil
+	}
+	return *res.Attributes()
+}
+
+// removedPlanValue marks list elements removed by a changelog, they are dropped once every change has been applied so
+// indexes of the changelog stay valid
+type removedPlanValue struct{}
+
+// applyChangelog returns a copy of attrs with changes of the changelog applied
+func applyChangelog(attrs map[string]interface{}, changelog analyser.Changelog) map[string]interface{} {
+	var value interface{} = copyPlanValue(attrs)
+	if value == nil {
+		value = map[string]interface{}{}
+	}
+	for _, c := range changelog {
+		value = patchPlanValue(value, c.Path, c.To, c.Type == diff.DELETE)
+	}
+	after, _ := compactPlanValue(value).(map[string]interface{})
+	return after
+}
+
+func patchPlanValue(value interface{}, path []string, to interface{}, remove bool) interface{} {
+	if len(path) == 0 {
+		if remove {
+			return removedPlanValue{}
+		}
+		return to
+	}
+	if value == nil {
+		if remove {
+			return nil
+		}
+		if _, err := strconv.Atoi(path[0]); err == nil {
+			value = []interface{}{}
+		} else {
+			value = map[string]interface{}{}
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if remove && len(path) == 1 {
+			delete(v, path[0])
+			return v
+		}
+		v[path[0]] = patchPlanValue(v[path[0]], path[1:], to, remove)
+		return v
+	case []interface{}:
+		i, err := strconv.Atoi(path[0])
+		if err != nil || i < 0 || (remove && i >= len(v)) {
+			return v
+		}
+		for len(v) <= i {
+			v = append(v, nil)
+		}
+		v[i] = patchPlanValue(v[i], path[1:], to, remove)
+		return v
+	}
+	// Scalar values cannot be traversed
+	return value
+}
+
+func copyPlanValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if v == nil {
+			return nil
+		}
+		c := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			c[key] = copyPlanValue(val)
+		}
+		return c
+	case []interface{}:
+		if v == nil {
+			return nil
+		}
+		c := make([]interface{}, len(v))
+		for i, val := range v {
+			c[i] = copyPlanValue(val)
+		}
+		return c
+	}
+	return value
+}
+
+func compactPlanValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, val := range v {
+			v[key] = compactPlanValue(val)
+		}
+		return v
+	case []interface{}:
+		c := make([]interface{}, 0, len(v))
+		for _, val := range v {
+			if _, removed := val.(removedPlanValue); removed {
+				continue
+			}
+			c = append(c, compactPlanValue(val))
+		}
+		return c
+	}
+	return value
+}
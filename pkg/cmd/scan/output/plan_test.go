@@ -26,12 +26,36 @@ func TestPlan_Write(t *testing.T) {
 			analysis:   fakeAnalysisForJSONPlan(),
 			wantErr:    false,
 		},
+		{
+			name:       "test jsonplan output with missing and drifted resources",
+			goldenfile: "output_plan_drifts.json",
+			analysis:   fakeAnalysisForJSONPlanWithDrifts(),
+			wantErr:    false,
+		},
 		{
 			name:       "test jsonplan output when no infra",
 			goldenfile: "output_plan_empty.json",
 			analysis:   &analyser.Analysis{},
 			wantErr:    false,
 		},
+		{
+			name:       "test jsonplan output with duplicated resources",
+			goldenfile: "output_plan_duplicated.json",
+			analysis:   fakeAnalysisWithDuplicated(),
+			wantErr:    false,
+		},
+		{
+			name:       "test jsonplan output with severities",
+			goldenfile: "output_plan_severities.json",
+			analysis:   fakeAnalysisWithSeverities(),
+			wantErr:    false,
+		},
+		{
+			name:       "test jsonplan output with failed conditions",
+			goldenfile: "output_plan_failed_conditions.json",
+			analysis:   fakeAnalysisWithFailedConditions(),
+			wantErr:    false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
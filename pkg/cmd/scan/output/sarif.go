@@ -0,0 +1,359 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/r3labs/diff/v2"
+
+	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/pkg/resource"
+	"github.com/snyk/driftctl/pkg/version"
+)
+
+const SARIFOutputType = "sarif"
+const SARIFOutputExample = "sarif://PATH/TO/FILE.sarif"
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+const sarifVersion = "2.1.0"
+
+// Rule IDs of SARIF results, one per kind of drift
+const (
+	SARIFRuleUnmanaged  = "unmanaged-resource"
+	SARIFRuleMissing    = "missing-resource"
+	SARIFRuleChanged    = "changed-resource"
+	SARIFRuleDuplicated = "duplicated-resource"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations,omitempty"`
+	Results     []sarifResult     `json:"results"`
+}
+
+// sarifInvocation reports fail conditions met by the scan, they are not tied to a resource and are written as
+// notifications rather than results
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ExitCode                   int                 `json:"exitCode"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications"`
+}
+
+type sarifNotification struct {
+	Descriptor sarifDescriptor `json:"descriptor"`
+	Level      string          `json:"level"`
+	Message    sarifMessage    `json:"message"`
+}
+
+type sarifDescriptor struct {
+	ID string `json:"id"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	HelpURI              string             `json:"helpUri"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Properties          sarifProperties   `json:"properties"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+type sarifProperties struct {
+	ResourceID   string   `json:"resourceId"`
+	ResourceType string   `json:"resourceType"`
+	Changes      []string `json:"changes,omitempty"`
+	Severity     string   `json:"severity,omitempty"`
+}
+
+var sarifRules = []sarifRule{
+	{
+		ID:                   SARIFRuleUnmanaged,
+		Name:                 "UnmanagedResource",
+		ShortDescription:     sarifMessage{"Resource found on the cloud provider but not managed by Terraform"},
+		HelpURI:              "https://docs.driftctl.com/",
+		DefaultConfiguration: sarifConfiguration{"warning"},
+	},
+	{
+		ID:                   SARIFRuleMissing,
+		Name:                 "MissingResource",
+		ShortDescription:     sarifMessage{"Resource found in Terraform but missing on the cloud provider"},
+		HelpURI:              "https://docs.driftctl.com/",
+		DefaultConfiguration: sarifConfiguration{"error"},
+	},
+	{
+		ID:                   SARIFRuleChanged,
+		Name:                 "ChangedResource",
+		ShortDescription:     sarifMessage{"Resource managed by Terraform that drifted on the cloud provider"},
+		HelpURI:              "https://docs.driftctl.com/",
+		DefaultConfiguration: sarifConfiguration{"error"},
+	},
+	{
+		ID:                   SARIFRuleDuplicated,
+		Name:                 "DuplicatedResource",
+		ShortDescription:     sarifMessage{"Resource managed by more than one Terraform resource"},
+		HelpURI:              "https://docs.driftctl.com/",
+		DefaultConfiguration: sarifConfiguration{"error"},
+	},
+}
+
+type SARIF struct {
+	path string
+}
+
+func NewSARIF(path string) *SARIF {
+	return &SARIF{path}
+}
+
+func (c *SARIF) Write(analysis *analyser.Analysis) error {
+	file := os.Stdout
+	if !isStdOut(c.path) {
+		f, err := os.OpenFile(c.path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		file = f
+	}
+
+	results := make([]sarifResult, 0, len(analysis.Unmanaged())+len(analysis.Deleted())+len(analysis.Differences())+len(analysis.Duplicated()))
+	for _, res := range analysis.Unmanaged() {
+		results = append(results, newSARIFResult(SARIFRuleUnmanaged, res, fmt.Sprintf("%s %s is not managed by Terraform", res.ResourceType(), res.ResourceId()), nil))
+	}
+	for _, res := range analysis.Deleted() {
+		results = append(results, newSARIFResult(SARIFRuleMissing, res, fmt.Sprintf("%s %s is missing on the cloud provider", res.ResourceType(), res.ResourceId()), nil))
+	}
+	for _, difference := range analysis.Differences() {
+		changes := make([]string, 0, len(difference.Changelog))
+		for _, change := range difference.Changelog {
+			changes = append(changes, formatSARIFChange(change))
+		}
+		message := fmt.Sprintf("%s %s has drifted: %s", difference.Res.ResourceType(), difference.Res.ResourceId(), strings.Join(changes, ", "))
+		result := newSARIFResult(SARIFRuleChanged, difference.Res, message, changes)
+		if severity := difference.Severity(); severity != analyser.SeverityNone {
+			result.Level = sarifLevel(severity)
+			result.Properties.Severity = severity.String()
+		}
+		results = append(results, result)
+	}
+	for _, duplicate := range analysis.Duplicated() {
+		message := fmt.Sprintf("%s %s is managed by %d Terraform resources", duplicate.Res.ResourceType(), duplicate.Res.ResourceId(), len(duplicate.Claims))
+		result := newSARIFResult(SARIFRuleDuplicated, duplicate.Res, message, nil)
+		// Point at every resource claiming it so that each of them is annotated
+		result.Locations = make([]sarifLocation, 0, len(duplicate.Claims))
+		for _, claim := range duplicate.Claims {
+			result.Locations = append(result.Locations, sarifResourceLocation(claim))
+		}
+		results = append(results, result)
+	}
+
+	// Code scanning tools drop results without physical location, point resources lacking an IaC source at a file
+	// where they can be managed or ignored
+	fallback := &sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: sarifFallbackArtifactURI(analysis)},
+	}
+	for i := range results {
+		for j := range results[i].Locations {
+			if results[i].Locations[j].PhysicalLocation == nil {
+				results[i].Locations[j].PhysicalLocation = fallback
+			}
+		}
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:           "driftctl",
+						Version:        version.Current(),
+						InformationURI: "https://driftctl.com",
+						Rules:          sarifRules,
+					},
+				},
+				Invocations: sarifInvocations(analysis.FailedConditions()),
+				Results:     results,
+			},
+		},
+	}
+
+	content, err := json.MarshalIndent(log, "", "\t")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(content); err != nil {
+		return err
+	}
+	return nil
+}
+
+func newSARIFResult(ruleID string, res *resource.Resource, message string, changes []string) sarifResult {
+	ruleIndex := 0
+	for i, rule := range sarifRules {
+		if rule.ID == ruleID {
+			ruleIndex = i
+		}
+	}
+
+	return sarifResult{
+		RuleID:    ruleID,
+		RuleIndex: ruleIndex,
+		Level:     sarifRules[ruleIndex].DefaultConfiguration.Level,
+		Message:   sarifMessage{message},
+		Locations: []sarifLocation{sarifResourceLocation(res)},
+		// Results are not tied to lines of code, fingerprint them by resource so that they can be tracked across scans
+		PartialFingerprints: map[string]string{
+			"resource/v1": fmt.Sprintf("%s/%s/%s", ruleID, res.ResourceType(), res.ResourceId()),
+		},
+		Properties: sarifProperties{
+			ResourceID:   res.ResourceId(),
+			ResourceType: res.ResourceType(),
+			Changes:      changes,
+		},
+	}
+}
+
+// sarifInvocations returns the invocation of the scan when it met fail conditions, the exit code is the one of the
+// first condition as returned by the scan command
+func sarifInvocations(failed []analyser.FailedCondition) []sarifInvocation {
+	if len(failed) == 0 {
+		return nil
+	}
+	invocation := sarifInvocation{
+		ExecutionSuccessful:        true,
+		ExitCode:                   failed[0].ExitCode,
+		ToolExecutionNotifications: make([]sarifNotification, 0, len(failed)),
+	}
+	for _, condition := range failed {
+		invocation.ToolExecutionNotifications = append(invocation.ToolExecutionNotifications, sarifNotification{
+			Descriptor: sarifDescriptor{ID: condition.Condition},
+			Level:      "error",
+			Message:    sarifMessage{fmt.Sprintf("Fail condition %s met (exit code %d): %s", condition.Condition, condition.ExitCode, condition.Message)},
+		})
+	}
+	return []sarifInvocation{invocation}
+}
+
+// sarifResourceLocation points at the state file and module address of managed resources, unmanaged resources only
+// have a logical location on the cloud provider, see sarifFallbackArtifactURI
+func sarifResourceLocation(res *resource.Resource) sarifLocation {
+	location := sarifLocation{
+		LogicalLocations: []sarifLogicalLocation{
+			{
+				Name:               res.ResourceId(),
+				FullyQualifiedName: fmt.Sprintf("%s.%s", res.ResourceType(), res.ResourceId()),
+				Kind:               "resource",
+			},
+		},
+	}
+
+	if res.Source == nil {
+		return location
+	}
+
+	location.PhysicalLocation = &sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: sarifArtifactURI(res.Source.Source())},
+	}
+	location.LogicalLocations[0].Name = res.Source.InternalName()
+	location.LogicalLocations[0].FullyQualifiedName = res.SourceString()
+	return location
+}
+
+// sarifFallbackArtifactURI returns the first IaC source of the scan, or the .driftignore file when no resource is
+// managed
+func sarifFallbackArtifactURI(analysis *analyser.Analysis) string {
+	for _, res := range analysis.Managed() {
+		if res.Source != nil {
+			return sarifArtifactURI(res.Source.Source())
+		}
+	}
+	return ".driftignore"
+}
+
+// sarifArtifactURI strips the scheme of local IaC sources so that results are bound to files of the repository,
+// remote ones are kept as is
+func sarifArtifactURI(source string) string {
+	parts := strings.SplitN(source, "://", 2)
+	if len(parts) != 2 || strings.Contains(parts[0], "+") {
+		return source
+	}
+	return parts[1]
+}
+
+// sarifLevel maps severities of changes to SARIF levels
+func sarifLevel(severity analyser.Severity) string {
+	switch {
+	case severity >= analyser.SeverityHigh:
+		return "error"
+	case severity == analyser.SeverityMedium:
+		return "warning"
+	}
+	return "note"
+}
+
+func formatSARIFChange(change analyser.Change) string {
+	path := strings.Join(change.Path, ".")
+	switch change.Type {
+	case diff.CREATE:
+		return fmt.Sprintf("+ %s", path)
+	case diff.DELETE:
+		return fmt.Sprintf("- %s", path)
+	}
+	return fmt.Sprintf("~ %s", path)
+}
@@ -0,0 +1,110 @@
+package output
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/pkg/resource"
+	"github.com/snyk/driftctl/test/goldenfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSARIF_Write(t *testing.T) {
+	tests := []struct {
+		name       string
+		goldenfile string
+		analysis   *analyser.Analysis
+		wantErr    bool
+	}{
+		{
+			name:       "test sarif output",
+			goldenfile: "output.sarif",
+			analysis:   fakeAnalysis(analyser.AnalyzerOptions{}),
+			wantErr:    false,
+		},
+		{
+			name:       "test sarif output when no infra",
+			goldenfile: "output_empty.sarif",
+			analysis:   &analyser.Analysis{},
+			wantErr:    false,
+		},
+		{
+			name:       "test sarif output with duplicated resources",
+			goldenfile: "output_duplicated.sarif",
+			analysis:   fakeAnalysisWithDuplicated(),
+			wantErr:    false,
+		},
+		{
+			name:       "test sarif output with severities",
+			goldenfile: "output_severities.sarif",
+			analysis:   fakeAnalysisWithSeverities(),
+			wantErr:    false,
+		},
+		{
+			name:       "test sarif output with failed conditions",
+			goldenfile: "output_failed_conditions.sarif",
+			analysis:   fakeAnalysisWithFailedConditions(),
+			wantErr:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			tempFile, err := ioutil.TempFile(tempDir, "result")
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := NewSARIF(tempFile.Name())
+			if err := c.Write(tt.analysis); (err != nil) != tt.wantErr {
+				t.Errorf("Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			result, err := ioutil.ReadFile(tempFile.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+			expectedFilePath := path.Join("./testdata/", tt.goldenfile)
+			if *goldenfile.Update == tt.goldenfile {
+				if err := ioutil.WriteFile(expectedFilePath, result, 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			expected, err := ioutil.ReadFile(expectedFilePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, string(expected), string(result))
+
+			// Code scanning tools require every result to have a physical location
+			var log sarifLog
+			if err := json.Unmarshal(result, &log); err != nil {
+				t.Fatal(err)
+			}
+			for _, r := range log.Runs[0].Results {
+				for _, location := range r.Locations {
+					assert.NotNil(t, location.PhysicalLocation, "result %s has no physical location", r.Message.Text)
+				}
+			}
+		})
+	}
+}
+
+func Test_sarifFallbackArtifactURI(t *testing.T) {
+	analysis := &analyser.Analysis{}
+	analysis.AddUnmanaged(&resource.Resource{Id: "user", Type: "aws_iam_user"})
+	assert.Equal(t, ".driftignore", sarifFallbackArtifactURI(analysis))
+
+	analysis.AddManaged(
+		&resource.Resource{Id: "bucket", Type: "aws_s3_bucket"},
+		&resource.Resource{Id: "role", Type: "aws_iam_role", Source: resource.NewTerraformStateSource("tfstate://states/terraform.tfstate", "", "role")},
+	)
+	assert.Equal(t, "states/terraform.tfstate", sarifFallbackArtifactURI(analysis))
+}
+
+func Test_sarifArtifactURI(t *testing.T) {
+	assert.Equal(t, "states/terraform.tfstate", sarifArtifactURI("tfstate://states/terraform.tfstate"))
+	assert.Equal(t, "tfstate+s3://bucket/terraform.tfstate", sarifArtifactURI("tfstate+s3://bucket/terraform.tfstate"))
+	assert.Equal(t, "terraform.tfstate", sarifArtifactURI("terraform.tfstate"))
+}
@@ -0,0 +1,84 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/snyk/driftctl/pkg/analyser"
+)
+
+const SlackOutputType = "slack"
+const SlackOutputExample = "slack://hooks.slack.com/services/PATH"
+
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type   string      `json:"type"`
+	Text   *slackText  `json:"text,omitempty"`
+	Fields []slackText `json:"fields,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// NewSlack posts the analysis to a Slack incoming webhook
+func NewSlack(url string, options *WebhookOptions) *Webhook {
+	return newWebhook(url, options, formatSlackMessage)
+}
+
+func formatSlackMessage(analysis *analyser.Analysis, top int) ([]byte, error) {
+	summary := analysis.Summary()
+	title := webhookTitle(analysis)
+	message := slackMessage{
+		Text: title,
+		Blocks: []slackBlock{
+			{
+				Type: "header",
+				Text: &slackText{Type: "plain_text", Text: title},
+			},
+			{
+				Type: "section",
+				Fields: []slackText{
+					{Type: "mrkdwn", Text: fmt.Sprintf("*Coverage*\n%d%%", analysis.Coverage())},
+					{Type: "mrkdwn", Text: fmt.Sprintf("*Resources*\n%d", summary.TotalResources)},
+					{Type: "mrkdwn", Text: fmt.Sprintf("*Unmanaged*\n%d", summary.TotalUnmanaged)},
+					{Type: "mrkdwn", Text: fmt.Sprintf("*Missing*\n%d", summary.TotalDeleted)},
+					{Type: "mrkdwn", Text: fmt.Sprintf("*Changed*\n%d", summary.TotalDrifted)},
+				},
+			},
+		},
+	}
+
+	if summary.TotalDuplicated > 0 {
+		message.Blocks[1].Fields = append(message.Blocks[1].Fields, slackText{Type: "mrkdwn", Text: fmt.Sprintf("*Duplicated*\n%d", summary.TotalDuplicated)})
+	}
+
+	if lines, more := webhookDriftedResources(analysis, top); len(lines) > 0 {
+		if more > 0 {
+			lines = append(lines, fmt.Sprintf("_and %d more_", more))
+		}
+		message.Blocks = append(message.Blocks, slackBlock{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: "• " + strings.Join(lines, "\n• ")},
+		})
+	}
+
+	if failed := analysis.FailedConditions(); len(failed) > 0 {
+		conditions := make([]string, 0, len(failed))
+		for _, condition := range failed {
+			conditions = append(conditions, fmt.Sprintf("`%s`: %s", condition.Condition, condition.Message))
+		}
+		message.Blocks = append(message.Blocks, slackBlock{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: "*Fail conditions met:*\n" + strings.Join(conditions, "\n")},
+		})
+	}
+
+	return json.Marshal(message)
+}
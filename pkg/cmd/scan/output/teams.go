@@ -0,0 +1,93 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/snyk/driftctl/pkg/analyser"
+)
+
+const TeamsOutputType = "teams"
+const TeamsOutputExample = "teams://HOST/PATH"
+
+const (
+	teamsColorSync    = "2EB67D"
+	teamsColorNotSync = "E01E5A"
+)
+
+// teamsMessageCard is the legacy actionable message card format accepted by Microsoft Teams incoming webhooks
+type teamsMessageCard struct {
+	Type       string         `json:"@type"`
+	Context    string         `json:"@context"`
+	Summary    string         `json:"summary"`
+	ThemeColor string         `json:"themeColor"`
+	Title      string         `json:"title"`
+	Sections   []teamsSection `json:"sections"`
+}
+
+type teamsSection struct {
+	Facts []teamsFact `json:"facts,omitempty"`
+	Text  string      `json:"text,omitempty"`
+}
+
+type teamsFact struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// NewTeams posts the analysis to a Microsoft Teams incoming webhook
+func NewTeams(url string, options *WebhookOptions) *Webhook {
+	return newWebhook(url, options, formatTeamsMessageCard)
+}
+
+func formatTeamsMessageCard(analysis *analyser.Analysis, top int) ([]byte, error) {
+	summary := analysis.Summary()
+	title := webhookTitle(analysis)
+	card := teamsMessageCard{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		Summary:    title,
+		ThemeColor: teamsColorNotSync,
+		Title:      title,
+		Sections: []teamsSection{
+			{
+				Facts: []teamsFact{
+					{Name: "Coverage", Value: fmt.Sprintf("%d%%", analysis.Coverage())},
+					{Name: "Resources", Value: strconv.Itoa(summary.TotalResources)},
+					{Name: "Unmanaged", Value: strconv.Itoa(summary.TotalUnmanaged)},
+					{Name: "Missing", Value: strconv.Itoa(summary.TotalDeleted)},
+					{Name: "Changed", Value: strconv.Itoa(summary.TotalDrifted)},
+				},
+			},
+		},
+	}
+	if summary.TotalDuplicated > 0 {
+		card.Sections[0].Facts = append(card.Sections[0].Facts, teamsFact{Name: "Duplicated", Value: strconv.Itoa(summary.TotalDuplicated)})
+	}
+	if analysis.IsSync() {
+		card.ThemeColor = teamsColorSync
+	}
+
+	if lines, more := webhookDriftedResources(analysis, top); len(lines) > 0 {
+		if more > 0 {
+			lines = append(lines, fmt.Sprintf("_and %d more_", more))
+		}
+		card.Sections = append(card.Sections, teamsSection{
+			Text: "- " + strings.Join(lines, "\n- "),
+		})
+	}
+
+	if failed := analysis.FailedConditions(); len(failed) > 0 {
+		conditions := make([]string, 0, len(failed))
+		for _, condition := range failed {
+			conditions = append(conditions, fmt.Sprintf("`%s`: %s", condition.Condition, condition.Message))
+		}
+		card.Sections = append(card.Sections, teamsSection{
+			Text: "**Fail conditions met:**\n\n" + strings.Join(conditions, "\n\n"),
+		})
+	}
+
+	return json.Marshal(card)
+}
@@ -0,0 +1,143 @@
+package output
+
+import (
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/snyk/driftctl/pkg/alerter"
+	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/pkg/resource"
+)
+
+const TemplateOutputType = "template"
+const TemplateOutputExample = "template://PATH/TO/TEMPLATE.tmpl:PATH/TO/FILE"
+
+// Templates with those extensions are rendered with html/template, others with text/template
+var htmlTemplateExtensions = []string{".html", ".htm", ".gohtml"}
+
+// TemplateParams is the view model given to templates of the template output
+type TemplateParams struct {
+	// IsSync is true when there is no unmanaged, missing nor changed resource
+	IsSync bool
+	// Date the scan ran at
+	Date time.Time
+	// Duration of the scan
+	Duration time.Duration
+	// Coverage of the infrastructure by IaC, in percent
+	Coverage int
+	// Summary counts resources by category
+	Summary analyser.Summary
+	// Resources found both in IaC and on cloud providers
+	Managed []*resource.Resource
+	// Resources found on cloud providers but not in IaC
+	Unmanaged []*resource.Resource
+	// Resources found in IaC but missing on cloud providers
+	Deleted []*resource.Resource
+	// Differences lists changes of managed resources, only filled in deep mode
+	Differences []analyser.Difference
+	// Highest severity of changes, the one of a difference is given by its Severity method
+	Severity analyser.Severity
+	// Duplicated lists resources managed by more than one IaC resource
+	Duplicated []analyser.Duplicate
+	// Alerts raised during the scan indexed by resource type
+	Alerts alerter.Alerts
+	// Providers scanned with their own coverage and summary
+	Providers []analyser.ProviderSummary
+	// Name and version of the first scanned provider, handy when scanning a single one
+	ProviderName    string
+	ProviderVersion string
+	// Conditions given with --fail-on that the scan meets
+	FailedConditions []analyser.FailedCondition
+}
+
+func NewTemplateParams(analysis *analyser.Analysis) TemplateParams {
+	params := TemplateParams{
+		IsSync:           analysis.IsSync(),
+		Date:             analysis.Date,
+		Duration:         analysis.Duration,
+		Coverage:         analysis.Coverage(),
+		Summary:          analysis.Summary(),
+		Managed:          analysis.Managed(),
+		Unmanaged:        analysis.Unmanaged(),
+		Deleted:          analysis.Deleted(),
+		Differences:      analysis.Differences(),
+		Duplicated:       analysis.Duplicated(),
+		Alerts:           analysis.Alerts(),
+		Providers:        analysis.ProviderSummaries(),
+		FailedConditions: analysis.FailedConditions(),
+	}
+	for _, difference := range analysis.Differences() {
+		if severity := difference.Severity(); severity > params.Severity {
+			params.Severity = severity
+		}
+	}
+	if len(analysis.Providers) > 0 {
+		params.ProviderName = analysis.Providers[0].Name
+		params.ProviderVersion = analysis.Providers[0].Version
+	}
+	return params
+}
+
+// templateFuncs are available in user templates on top of the builtin ones
+var templateFuncs = map[string]interface{}{
+	"join":     strings.Join,
+	"prettify": prettify,
+}
+
+type Template struct {
+	templatePath string
+	path         string
+}
+
+func NewTemplate(templatePath, path string) *Template {
+	return &Template{templatePath, path}
+}
+
+func (c *Template) Write(analysis *analyser.Analysis) error {
+	content, err := os.ReadFile(c.templatePath)
+	if err != nil {
+		return errors.Wrapf(err, "unable to read template %s", c.templatePath)
+	}
+
+	var tmpl interface {
+		Execute(w io.Writer, data interface{}) error
+	}
+	name := filepath.Base(c.templatePath)
+	if isHTMLTemplate(c.templatePath) {
+		tmpl, err = htmltemplate.New(name).Funcs(templateFuncs).Parse(string(content))
+	} else {
+		tmpl, err = template.New(name).Funcs(templateFuncs).Parse(string(content))
+	}
+	if err != nil {
+		return errors.Wrapf(err, "unable to parse template %s", c.templatePath)
+	}
+
+	file := os.Stdout
+	if !isStdOut(c.path) {
+		f, err := os.OpenFile(c.path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		file = f
+	}
+
+	return tmpl.Execute(file, NewTemplateParams(analysis))
+}
+
+func isHTMLTemplate(path string) bool {
+	ext := filepath.Ext(strings.TrimSuffix(path, ".tmpl"))
+	for _, e := range htmlTemplateExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
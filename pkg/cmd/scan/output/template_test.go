@@ -0,0 +1,87 @@
+package output
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/test/goldenfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplate_Write(t *testing.T) {
+	tests := []struct {
+		name         string
+		templatePath string
+		goldenfile   string
+		analysis     *analyser.Analysis
+		err          string
+	}{
+		{
+			name:         "test text template output",
+			templatePath: "testdata/template.csv.tmpl",
+			goldenfile:   "output_template.csv",
+			analysis:     fakeAnalysis(analyser.AnalyzerOptions{}),
+		},
+		{
+			name:         "test html template output",
+			templatePath: "testdata/template.html.tmpl",
+			goldenfile:   "output_template.html",
+			analysis:     fakeAnalysis(analyser.AnalyzerOptions{}),
+		},
+		{
+			name:         "test text template output with severities",
+			templatePath: "testdata/template_severities.tmpl",
+			goldenfile:   "output_template_severities.txt",
+			analysis:     fakeAnalysisWithSeverities(),
+		},
+		{
+			name:         "test missing template",
+			templatePath: "testdata/missing.tmpl",
+			analysis:     fakeAnalysis(analyser.AnalyzerOptions{}),
+			err:          "unable to read template testdata/missing.tmpl: open testdata/missing.tmpl: no such file or directory",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resultPath := filepath.Join(t.TempDir(), "result")
+			c := NewTemplate(tt.templatePath, resultPath)
+			err := c.Write(tt.analysis)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			result, err := ioutil.ReadFile(resultPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expectedFilePath := path.Join("./testdata/", tt.goldenfile)
+			if *goldenfile.Update == tt.goldenfile {
+				if err := ioutil.WriteFile(expectedFilePath, result, 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			expected, err := ioutil.ReadFile(expectedFilePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, string(expected), string(result))
+		})
+	}
+}
+
+func TestTemplate_Write_InvalidTemplate(t *testing.T) {
+	templatePath := filepath.Join(t.TempDir(), "invalid.tmpl")
+	if err := os.WriteFile(templatePath, []byte("{{ .Coverage"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := NewTemplate(templatePath, filepath.Join(t.TempDir(), "result")).Write(fakeAnalysis(analyser.AnalyzerOptions{}))
+	assert.EqualError(t, err, "unable to parse template "+templatePath+": template: invalid.tmpl:1: unclosed action")
+}
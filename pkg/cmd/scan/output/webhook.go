@@ -0,0 +1,312 @@
+package output
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/snyk/driftctl/pkg/analyser"
+	pkghttp "github.com/snyk/driftctl/pkg/http"
+	"github.com/snyk/driftctl/pkg/resource"
+)
+
+const WebhookOutputType = "webhook"
+const WebhookOutputExample = "webhook://HOST/PATH"
+
+// Header holding the HMAC-SHA256 signature of the payload when a secret is configured
+const WebhookSignatureHeader = "X-Driftctl-Signature"
+
+const (
+	DefaultWebhookRetries = 3
+	DefaultWebhookTimeout = 10 * time.Second
+	DefaultWebhookTop     = 10
+)
+
+// WebhookOptions configures outputs sending the analysis to an HTTP endpoint
+type WebhookOptions struct {
+	// Headers added to the request
+	Headers map[string]string
+	// Secret used to sign the payload, nothing is signed when empty
+	Secret string
+	// Retries is the number of additional attempts made when the request fails
+	Retries int
+	// Timeout of each attempt
+	Timeout time.Duration
+	// TemplatePath is a Go template rendering the payload instead of the default one
+	TemplatePath string
+	// Top is the maximum number of resources listed in the payload
+	Top int
+}
+
+func DefaultWebhookOptions() *WebhookOptions {
+	return &WebhookOptions{
+		Headers: map[string]string{},
+		Retries: DefaultWebhookRetries,
+		Timeout: DefaultWebhookTimeout,
+		Top:     DefaultWebhookTop,
+	}
+}
+
+// webhookFormatter renders the analysis as the body of the request
+type webhookFormatter func(analysis *analyser.Analysis, top int) ([]byte, error)
+
+type webhookPayload struct {
+	Sync             bool                       `json:"sync"`
+	Coverage         int                        `json:"coverage"`
+	Summary          analyser.Summary           `json:"summary"`
+	Date             time.Time                  `json:"date"`
+	Duration         uint                       `json:"duration"` // in seconds
+	Unmanaged        []webhookResource          `json:"unmanaged,omitempty"`
+	Missing          []webhookResource          `json:"missing,omitempty"`
+	Changed          []webhookResource          `json:"changed,omitempty"`
+	Duplicated       []webhookDuplicate         `json:"duplicated,omitempty"`
+	FailedConditions []analyser.FailedCondition `json:"failed_conditions,omitempty"`
+}
+
+type webhookResource struct {
+	Id     string `json:"id"`
+	Type   string `json:"type"`
+	Source string `json:"source,omitempty"`
+	// Severity is the highest severity of the changes of changed resources
+	Severity string `json:"severity,omitempty"`
+}
+
+type webhookDuplicate struct {
+	Id     string         `json:"id"`
+	Type   string         `json:"type"`
+	Claims []webhookClaim `json:"claims"`
+}
+
+// webhookClaim locates an IaC resource managing a duplicated resource
+type webhookClaim struct {
+	Address string `json:"address"`
+	Source  string `json:"source,omitempty"`
+}
+
+type Webhook struct {
+	url        string
+	options    *WebhookOptions
+	formatter  webhookFormatter
+	client     pkghttp.HTTPClient
+	retryDelay time.Duration
+}
+
+func NewWebhook(url string, options *WebhookOptions) *Webhook {
+	return newWebhook(url, options, formatWebhookPayload)
+}
+
+func newWebhook(url string, options *WebhookOptions, formatter webhookFormatter) *Webhook {
+	if options == nil {
+		options = DefaultWebhookOptions()
+	}
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		url = "https://" + url
+	}
+	return &Webhook{
+		url:        url,
+		options:    options,
+		formatter:  formatter,
+		client:     &http.Client{Timeout: options.Timeout},
+		retryDelay: time.Second,
+	}
+}
+
+func (c *Webhook) Write(analysis *analyser.Analysis) error {
+	body, err := c.payload(analysis)
+	if err != nil {
+		return err
+	}
+
+	var signature string
+	if c.options.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(c.options.Secret))
+		mac.Write(body)
+		signature = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	for attempt := 0; ; attempt++ {
+		err = c.send(body, signature)
+		if err == nil {
+			return nil
+		}
+		if _, retryable := err.(webhookRetryableError); !retryable || attempt >= c.options.Retries {
+			return errors.Wrapf(err, "unable to send scan result to %s", c.url)
+		}
+		logrus.WithFields(logrus.Fields{
+			"url":     c.url,
+			"attempt": attempt + 1,
+		}).Debugf("Webhook request failed, retrying: %s", err)
+		time.Sleep(c.retryDelay * time.Duration(attempt+1))
+	}
+}
+
+func (c *Webhook) payload(analysis *analyser.Analysis) ([]byte, error) {
+	if c.options.TemplatePath == "" {
+		return c.formatter(analysis, c.options.Top)
+	}
+
+	content, err := os.ReadFile(c.options.TemplatePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read template %s", c.options.TemplatePath)
+	}
+	tmpl, err := template.New("webhook").Funcs(templateFuncs).Parse(string(content))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse template %s", c.options.TemplatePath)
+	}
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, NewTemplateParams(analysis)); err != nil {
+		return nil, err
+	}
+	return body.Bytes(), nil
+}
+
+// webhookRetryableError is returned for network errors and responses the endpoint may not return on a later attempt
+type webhookRetryableError struct {
+	error
+}
+
+func (c *Webhook) send(body []byte, signature string) error {
+	req, err := http.NewRequest(http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "driftctl")
+	for key, value := range c.options.Headers {
+		req.Header.Set(key, value)
+	}
+	if signature != "" {
+		req.Header.Set(WebhookSignatureHeader, signature)
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return webhookRetryableError{err}
+	}
+	defer res.Body.Close()
+	// Drain the body so that the connection can be reused by next attempts
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		err := errors.Errorf("endpoint responded with status %s", res.Status)
+		if res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500 {
+			return webhookRetryableError{err}
+		}
+		return err
+	}
+	return nil
+}
+
+func formatWebhookPayload(analysis *analyser.Analysis, top int) ([]byte, error) {
+	changed := make([]*resource.Resource, 0, len(analysis.Differences()))
+	for _, difference := range analysis.Differences() {
+		changed = append(changed, difference.Res)
+	}
+	payload := webhookPayload{
+		Sync:             analysis.IsSync(),
+		Coverage:         analysis.Coverage(),
+		Summary:          analysis.Summary(),
+		Date:             analysis.Date,
+		Duration:         uint(analysis.Duration.Seconds() + 0.5),
+		Unmanaged:        newWebhookResources(analysis.Unmanaged(), top),
+		Missing:          newWebhookResources(analysis.Deleted(), top),
+		Changed:          newWebhookResources(changed, top),
+		Duplicated:       newWebhookDuplicates(analysis.Duplicated(), top),
+		FailedConditions: analysis.FailedConditions(),
+	}
+	for i := range payload.Changed {
+		payload.Changed[i].Severity = analysis.Differences()[i].Severity().String()
+	}
+	return json.Marshal(payload)
+}
+
+func newWebhookResources(resources []*resource.Resource, top int) []webhookResource {
+	if len(resources) > top {
+		resources = resources[:top]
+	}
+	ret := make([]webhookResource, 0, len(resources))
+	for _, res := range resources {
+		ret = append(ret, webhookResource{
+			Id:     res.ResourceId(),
+			Type:   res.ResourceType(),
+			Source: res.SourceString(),
+		})
+	}
+	return ret
+}
+
+func newWebhookDuplicates(duplicated []analyser.Duplicate, top int) []webhookDuplicate {
+	if len(duplicated) > top {
+		duplicated = duplicated[:top]
+	}
+	ret := make([]webhookDuplicate, 0, len(duplicated))
+	for _, duplicate := range duplicated {
+		d := webhookDuplicate{
+			Id:     duplicate.Res.ResourceId(),
+			Type:   duplicate.Res.ResourceType(),
+			Claims: make([]webhookClaim, 0, len(duplicate.Claims)),
+		}
+		for _, claim := range duplicate.Claims {
+			c := webhookClaim{Address: fmt.Sprintf("%s.%s", claim.ResourceType(), claim.ResourceId())}
+			if claim.Src() != nil {
+				c.Address = claim.Address()
+				c.Source = claim.Src().Source()
+			}
+			d.Claims = append(d.Claims, c)
+		}
+		ret = append(ret, d)
+	}
+	return ret
+}
+
+// webhookDriftedResources lists up to top duplicated, changed, missing then unmanaged resources as human readable lines
+func webhookDriftedResources(analysis *analyser.Analysis, top int) (lines []string, more int) {
+	add := func(kind string, res *resource.Resource) {
+		if len(lines) >= top {
+			more++
+			return
+		}
+		line := fmt.Sprintf("%s `%s` (%s)", kind, res.ResourceId(), res.ResourceType())
+		if res.SourceString() != "" {
+			line = fmt.Sprintf("%s `%s` (%s)", kind, res.ResourceId(), res.SourceString())
+		}
+		lines = append(lines, line)
+	}
+	for _, duplicate := range analysis.Duplicated() {
+		add(fmt.Sprintf("Managed %d times", len(duplicate.Claims)), duplicate.Res)
+	}
+	for _, difference := range analysis.Differences() {
+		kind := "Changed"
+		if severity := difference.Severity(); severity != analyser.SeverityNone {
+			kind = fmt.Sprintf("Changed [%s]", severity)
+		}
+		add(kind, difference.Res)
+	}
+	for _, res := range analysis.Deleted() {
+		add("Missing", res)
+	}
+	for _, res := range analysis.Unmanaged() {
+		add("Unmanaged", res)
+	}
+	return lines, more
+}
+
+func webhookTitle(analysis *analyser.Analysis) string {
+	if analysis.IsSync() {
+		return "driftctl: your infrastructure is fully in sync"
+	}
+	return "driftctl: your infrastructure is not in sync"
+}
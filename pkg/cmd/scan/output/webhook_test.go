@@ -0,0 +1,188 @@
+package output
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/test/goldenfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func fakeWebhookAnalysis() *analyser.Analysis {
+	a := fakeAnalysis(analyser.AnalyzerOptions{})
+	a.Date = time.Date(2021, 10, 13, 10, 0, 0, 0, time.UTC)
+	a.Duration = 12 * time.Second
+	return a
+}
+
+func TestWebhook_Write(t *testing.T) {
+	tests := []struct {
+		name       string
+		goldenfile string
+		output     func(url string, options *WebhookOptions) *Webhook
+		analysis   *analyser.Analysis
+	}{
+		{
+			name:       "test webhook output",
+			goldenfile: "output_webhook.json",
+			output:     NewWebhook,
+			analysis:   fakeWebhookAnalysis(),
+		},
+		{
+			name:       "test webhook output when in sync",
+			goldenfile: "output_webhook_sync.json",
+			output:     NewWebhook,
+			analysis:   fakeAnalysisNoDrift(),
+		},
+		{
+			name:       "test webhook output with duplicated resources",
+			goldenfile: "output_webhook_duplicated.json",
+			output:     NewWebhook,
+			analysis:   fakeAnalysisWithDuplicated(),
+		},
+		{
+			name:       "test webhook output with severities",
+			goldenfile: "output_webhook_severities.json",
+			output:     NewWebhook,
+			analysis:   fakeAnalysisWithSeverities(),
+		},
+		{
+			name:       "test slack output with severities",
+			goldenfile: "output_slack_severities.json",
+			output:     NewSlack,
+			analysis:   fakeAnalysisWithSeverities(),
+		},
+		{
+			name:       "test slack output",
+			goldenfile: "output_slack.json",
+			output:     NewSlack,
+			analysis:   fakeWebhookAnalysis(),
+		},
+		{
+			name:       "test teams output",
+			goldenfile: "output_teams.json",
+			output:     NewTeams,
+			analysis:   fakeWebhookAnalysis(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result []byte
+			var headers http.Header
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				headers = r.Header
+				result, _ = ioutil.ReadAll(r.Body)
+			}))
+			defer server.Close()
+
+			options := DefaultWebhookOptions()
+			options.Top = 2
+			options.Secret = "secret"
+			options.Headers = map[string]string{"Authorization": "Bearer token"}
+			if err := tt.output(server.URL, options).Write(tt.analysis); err != nil {
+				t.Fatal(err)
+			}
+
+			mac := hmac.New(sha256.New, []byte("secret"))
+			mac.Write(result)
+			assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), headers.Get(WebhookSignatureHeader))
+			assert.Equal(t, "Bearer token", headers.Get("Authorization"))
+			assert.Equal(t, "application/json", headers.Get("Content-Type"))
+
+			expectedFilePath := path.Join("./testdata/", tt.goldenfile)
+			if *goldenfile.Update == tt.goldenfile {
+				if err := ioutil.WriteFile(expectedFilePath, result, 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			expected, err := ioutil.ReadFile(expectedFilePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, string(expected), string(result))
+		})
+	}
+}
+
+func TestWebhook_Write_Retries(t *testing.T) {
+	tests := []struct {
+		name          string
+		statuses      []int
+		retries       int
+		expectedCalls int
+		wantErr       bool
+	}{
+		{
+			name:          "test success after retries",
+			statuses:      []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK},
+			retries:       2,
+			expectedCalls: 3,
+		},
+		{
+			name:          "test failure when retries are exhausted",
+			statuses:      []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusOK},
+			retries:       1,
+			expectedCalls: 2,
+			wantErr:       true,
+		},
+		{
+			name:          "test client errors are not retried",
+			statuses:      []int{http.StatusForbidden, http.StatusOK},
+			retries:       3,
+			expectedCalls: 1,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statuses[calls])
+				calls++
+			}))
+			defer server.Close()
+
+			options := DefaultWebhookOptions()
+			options.Retries = tt.retries
+			c := NewWebhook(server.URL, options)
+			c.retryDelay = time.Millisecond
+			if err := c.Write(fakeAnalysisNoDrift()); (err != nil) != tt.wantErr {
+				t.Errorf("Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.expectedCalls, calls)
+		})
+	}
+}
+
+func TestWebhook_Write_Template(t *testing.T) {
+	var result []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	options := DefaultWebhookOptions()
+	options.TemplatePath = "./testdata/template.csv.tmpl"
+	if err := NewSlack(server.URL, options).Write(fakeWebhookAnalysis()); err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := ioutil.ReadFile("./testdata/output_template.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(expected), string(result))
+}
+
+func TestNewWebhook_URL(t *testing.T) {
+	assert.Equal(t, "https://example.com/hook", NewWebhook("example.com/hook", nil).url)
+	assert.Equal(t, "http://localhost:8080/hook", NewWebhook("http://localhost:8080/hook", nil).url)
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/snyk/driftctl/pkg"
@@ -10,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/snyk/driftctl/pkg/iac/config"
+	"github.com/snyk/driftctl/pkg/remote/aws"
 	"github.com/snyk/driftctl/test"
 
 	"github.com/spf13/cobra"
@@ -52,6 +54,10 @@ func TestScanCmd_Valid(t *testing.T) {
 		{args: []string{"scan", "--driftignore", ".driftignore"}},
 		{args: []string{"scan", "-o", "html://result.html", "-o", "json://result.json"}},
 		{args: []string{"scan", "--tf-lockfile", "../.terraform.lock.hcl"}},
+		{args: []string{"scan", "--aws-regions", "us-east-1,eu-west-3"}},
+		{args: []string{"scan", "--aws-accounts", "123456789012", "--aws-assume-role", "arn:aws:iam::{account_id}:role/driftctl", "--aws-external-id", "external"}},
+		{args: []string{"scan", "--aws-organization", "--from", "tfstate://prod.tfstate", "--aws-state-account", "tfstate://prod.tfstate=123456789012"}},
+		{args: []string{"scan", "-o", "slack://hooks.slack.com/services/T0/B0/XXX", "--webhook-headers", "Authorization=Bearer token", "--webhook-secret", "secret", "--webhook-retries", "5", "--webhook-timeout", "30s", "--webhook-top", "20"}},
 	}
 
 	for _, tt := range cases {
@@ -77,15 +83,18 @@ func TestScanCmd_Invalid(t *testing.T) {
 		{args: []string{"scan", "-t", "glou"}, expected: "unsupported cloud provider 'glou'\nValid values are: aws+tf,github+tf,gcp+tf,azure+tf"},
 		{args: []string{"scan", "--to"}, expected: `flag needs an argument: --to`},
 		{args: []string{"scan", "--to", "glou"}, expected: "unsupported cloud provider 'glou'\nValid values are: aws+tf,github+tf,gcp+tf,azure+tf"},
+		{args: []string{"scan", "--to", "aws+tf,glou"}, expected: "unsupported cloud provider 'glou'\nValid values are: aws+tf,github+tf,gcp+tf,azure+tf"},
+		{args: []string{"scan", "--to", "aws+tf,github+tf", "--tf-provider-version", "3.30.2"}, expected: "--tf-provider-version cannot be used when scanning several cloud providers, use --tf-lockfile instead"},
 		{args: []string{"scan", "-f"}, expected: `flag needs an argument: 'f' in -f`},
 		{args: []string{"scan", "--from"}, expected: `flag needs an argument: --from`},
 		{args: []string{"scan", "--from"}, expected: `flag needs an argument: --from`},
-		{args: []string{"scan", "--from", "tosdgjhgsdhgkjs"}, expected: "Unable to parse from flag 'tosdgjhgsdhgkjs': \nAccepted schemes are: tfstate://,tfstate+s3://,tfstate+http://,tfstate+https://,tfstate+tfcloud://,tfstate+gs://"},
-		{args: []string{"scan", "--from", "://"}, expected: "Unable to parse from flag '://': \nAccepted schemes are: tfstate://,tfstate+s3://,tfstate+http://,tfstate+https://,tfstate+tfcloud://,tfstate+gs://"},
-		{args: []string{"scan", "--from", "://test"}, expected: "Unable to parse from flag '://test': \nAccepted schemes are: tfstate://,tfstate+s3://,tfstate+http://,tfstate+https://,tfstate+tfcloud://,tfstate+gs://"},
-		{args: []string{"scan", "--from", "tosdgjhgsdhgkjs://"}, expected: "Unable to parse from flag 'tosdgjhgsdhgkjs://': \nAccepted schemes are: tfstate://,tfstate+s3://,tfstate+http://,tfstate+https://,tfstate+tfcloud://,tfstate+gs://"},
-		{args: []string{"scan", "--from", "terraform+foo+bar://test"}, expected: "Unable to parse from scheme 'terraform+foo+bar': \nAccepted schemes are: tfstate://,tfstate+s3://,tfstate+http://,tfstate+https://,tfstate+tfcloud://,tfstate+gs://"},
-		{args: []string{"scan", "--from", "unsupported://test"}, expected: "Unsupported IaC source 'unsupported': \nAccepted values are: tfstate"},
+		{args: []string{"scan", "--from", "tosdgjhgsdhgkjs"}, expected: "Unable to parse from flag 'tosdgjhgsdhgkjs': \nAccepted schemes are: tfstate://,tfstate+s3://,tfstate+http://,tfstate+https://,tfstate+tfcloud://,tfstate+gs://,tfplan://,tfconfig://"},
+		{args: []string{"scan", "--from", "://"}, expected: "Unable to parse from flag '://': \nAccepted schemes are: tfstate://,tfstate+s3://,tfstate+http://,tfstate+https://,tfstate+tfcloud://,tfstate+gs://,tfplan://,tfconfig://"},
+		{args: []string{"scan", "--from", "://test"}, expected: "Unable to parse from flag '://test': \nAccepted schemes are: tfstate://,tfstate+s3://,tfstate+http://,tfstate+https://,tfstate+tfcloud://,tfstate+gs://,tfplan://,tfconfig://"},
+		{args: []string{"scan", "--from", "tosdgjhgsdhgkjs://"}, expected: "Unable to parse from flag 'tosdgjhgsdhgkjs://': \nAccepted schemes are: tfstate://,tfstate+s3://,tfstate+http://,tfstate+https://,tfstate+tfcloud://,tfstate+gs://,tfplan://,tfconfig://"},
+		{args: []string{"scan", "--from", "terraform+foo+bar://test"}, expected: "Unable to parse from scheme 'terraform+foo+bar': \nAccepted schemes are: tfstate://,tfstate+s3://,tfstate+http://,tfstate+https://,tfstate+tfcloud://,tfstate+gs://,tfplan://,tfconfig://"},
+		{args: []string{"scan", "--from", "unsupported://test"}, expected: "Unsupported IaC source 'unsupported': \nAccepted values are: tfstate,tfplan,tfconfig"},
+		{args: []string{"scan", "--from", "tfconfig+s3://bucket/module"}, expected: "Unable to parse from scheme 'tfconfig+s3': \nAccepted schemes are: tfstate://,tfstate+s3://,tfstate+http://,tfstate+https://,tfstate+tfcloud://,tfstate+gs://,tfplan://,tfconfig://"},
 		{args: []string{"scan", "--from", "tfstate+foobar://test"}, expected: "Unsupported IaC backend 'foobar': \nAccepted values are: s3,http,https,tfcloud,gs"},
 		{args: []string{"scan", "--from", "tfstate:///tmp/test", "--from", "tfstate+toto://test"}, expected: "Unsupported IaC backend 'toto': \nAccepted values are: s3,http,https,tfcloud,gs"},
 		{args: []string{"scan", "--filter", "Type='test'"}, expected: "unable to parse filter expression: SyntaxError: Expected tRbracket, received: tUnknown"},
@@ -94,6 +103,23 @@ func TestScanCmd_Invalid(t *testing.T) {
 		{args: []string{"scan", "--tf-provider-version", "foo"}, expected: "Invalid version argument foo, expected a valid semver string (e.g. 2.13.4)"},
 		{args: []string{"scan", "--driftignore"}, expected: "flag needs an argument: --driftignore"},
 		{args: []string{"scan", "--tf-lockfile"}, expected: "flag needs an argument: --tf-lockfile"},
+		{args: []string{"scan", "--aws-regions", "all,us-east-1"}, expected: "\"all\" cannot be combined with other AWS regions"},
+		{args: []string{"scan", "--aws-regions", "us-east-1,"}, expected: "AWS region cannot be empty"},
+		{args: []string{"scan", "--to", "github+tf", "--aws-regions", "us-east-1"}, expected: "--aws-regions can only be used when scanning aws+tf"},
+		{args: []string{"scan", "--aws-accounts", "1234"}, expected: "Invalid AWS account ID '1234', expected 12 digits"},
+		{args: []string{"scan", "--to", "github+tf", "--aws-organization"}, expected: "--aws-accounts and --aws-organization can only be used when scanning aws+tf"},
+		{args: []string{"scan", "--aws-organization", "--aws-assume-role", "arn:aws:iam::123456789012:role/driftctl"}, expected: "--aws-assume-role must contain {account_id} to assume a role in each scanned account"},
+		{args: []string{"scan", "--aws-state-account", "tfstate://terraform.tfstate=123456789012"}, expected: "--aws-state-account can only be used with --aws-accounts or --aws-organization"},
+		{args: []string{"scan", "--aws-organization", "--aws-state-account", "tfstate://terraform.tfstate"}, expected: "Unable to parse state account 'tfstate://terraform.tfstate': \nExpected format is SOURCE=ACCOUNT_ID"},
+		{args: []string{"scan", "--aws-organization", "--aws-state-account", "tfstate://terraform.tfstate=1234"}, expected: "Invalid AWS account ID '1234', expected 12 digits"},
+		{args: []string{"scan", "--aws-organization", "--aws-state-account", "tfstate://other.tfstate=123456789012"}, expected: "IaC source 'tfstate://other.tfstate' is not part of --from"},
+		{args: []string{"scan", "--webhook-retries", "-1"}, expected: "--webhook-retries cannot be negative"},
+		{args: []string{"scan", "--webhook-template", "testdata/missing.tmpl"}, expected: "Invalid --webhook-template: stat testdata/missing.tmpl: no such file or directory"},
+		{args: []string{"scan", "--fail-on", "coverage<high"}, expected: "Unable to parse fail condition 'coverage<high', coverage threshold must be a percentage"},
+		{args: []string{"scan", "--fail-on", "drift", "--fail-on", "unknown"}, expected: "Unable to parse fail condition 'unknown', accepted conditions are: coverage<80, missing, missing:aws_s3_bucket,aws_iam_role, drift, drift:acl,tags.Env, unmanaged-growth:PATH/TO/BASELINE.json, duplicated, duplicated:aws_s3_bucket, severity:high"},
+		{args: []string{"scan", "--min-severity", "urgent"}, expected: "unable to parse --min-severity: unknown severity 'urgent', must be one of info, low, medium, high, critical"},
+		{args: []string{"scan", "--severity-rules", "testdata/nope.yml"}, expected: "unable to read severity rules: open testdata/nope.yml: no such file or directory"},
+		{args: []string{"scan", "--history-retention", "-1"}, expected: "History retention must be a positive number of scans"},
 	}
 
 	for _, tt := range cases {
@@ -152,6 +178,31 @@ func Test_parseFromFlag(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "test state mixed with configuration",
+			args: args{
+				from: []string{"tfstate://terraform.tfstate", "tfconfig://infra/modules/network"},
+			},
+			want: []config.SupplierConfig{
+				{
+					Key:  "tfstate",
+					Path: "terraform.tfstate",
+				},
+				{
+					Key:  "tfconfig",
+					Path: "infra/modules/network",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "test configuration from a backend",
+			args: args{
+				from: []string{"tfconfig+s3://bucket/module"},
+			},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -167,6 +218,32 @@ func Test_parseFromFlag(t *testing.T) {
 	}
 }
 
+func Test_parseStateAccountFlag(t *testing.T) {
+	from := []config.SupplierConfig{
+		{Key: "tfstate", Backend: "s3", Path: "bucket/prod.tfstate"},
+		{Key: "tfstate", Path: "staging.tfstate"},
+		{Key: "tfstate", Path: "shared.tfstate"},
+	}
+
+	err := parseStateAccountFlag(
+		[]string{"tfstate+s3://bucket/prod.tfstate=111111111111", "tfstate://staging.tfstate=222222222222"},
+		from,
+		aws.Options{Accounts: []string{"111111111111", "222222222222"}},
+	)
+	if err != nil {
+		t.Fatalf("parseStateAccountFlag() error = %v", err)
+	}
+
+	want := []config.SupplierConfig{
+		{Key: "tfstate", Backend: "s3", Path: "bucket/prod.tfstate", Account: "111111111111"},
+		{Key: "tfstate", Path: "staging.tfstate", Account: "222222222222"},
+		{Key: "tfstate", Path: "shared.tfstate"},
+	}
+	if !reflect.DeepEqual(from, want) {
+		t.Errorf("parseStateAccountFlag() got = %v, want %v", from, want)
+	}
+}
+
 func Test_parseOutputFlag(t *testing.T) {
 	type args struct {
 		out []string
@@ -183,7 +260,7 @@ func Test_parseOutputFlag(t *testing.T) {
 				out: []string{""},
 			},
 			want: []output.OutputConfig{},
-			err:  fmt.Errorf("Unable to parse output flag '': \nAccepted formats are: console://,html://PATH/TO/FILE.html,json://PATH/TO/FILE.json,plan://PATH/TO/FILE.json"),
+			err:  fmt.Errorf("Unable to parse output flag '': \nAccepted formats are: %s", strings.Join(output.SupportedOutputsExample(), ",")),
 		},
 		{
 			name: "test empty array",
@@ -199,7 +276,7 @@ func Test_parseOutputFlag(t *testing.T) {
 				out: []string{"sdgjsdgjsdg"},
 			},
 			want: []output.OutputConfig{},
-			err:  fmt.Errorf("Unable to parse output flag 'sdgjsdgjsdg': \nAccepted formats are: console://,html://PATH/TO/FILE.html,json://PATH/TO/FILE.json,plan://PATH/TO/FILE.json"),
+			err:  fmt.Errorf("Unable to parse output flag 'sdgjsdgjsdg': \nAccepted formats are: %s", strings.Join(output.SupportedOutputsExample(), ",")),
 		},
 		{
 			name: "test invalid",
@@ -207,7 +284,7 @@ func Test_parseOutputFlag(t *testing.T) {
 				out: []string{"://"},
 			},
 			want: []output.OutputConfig{},
-			err:  fmt.Errorf("Unable to parse output flag '://': \nAccepted formats are: console://,html://PATH/TO/FILE.html,json://PATH/TO/FILE.json,plan://PATH/TO/FILE.json"),
+			err:  fmt.Errorf("Unable to parse output flag '://': \nAccepted formats are: %s", strings.Join(output.SupportedOutputsExample(), ",")),
 		},
 		{
 			name: "test unsupported",
@@ -215,7 +292,7 @@ func Test_parseOutputFlag(t *testing.T) {
 				out: []string{"foobar://"},
 			},
 			want: []output.OutputConfig{},
-			err:  fmt.Errorf("Unsupported output 'foobar': \nValid formats are: console://,html://PATH/TO/FILE.html,json://PATH/TO/FILE.json,plan://PATH/TO/FILE.json"),
+			err:  fmt.Errorf("Unsupported output 'foobar': \nValid formats are: %s", strings.Join(output.SupportedOutputsExample(), ",")),
 		},
 		{
 			name: "test empty json",
@@ -271,6 +348,204 @@ func Test_parseOutputFlag(t *testing.T) {
 			},
 			err: nil,
 		},
+		{
+			name: "test empty sarif",
+			args: args{
+				out: []string{"sarif://"},
+			},
+			want: []output.OutputConfig{},
+			err:  fmt.Errorf("Invalid sarif output 'sarif://': \nMust be of kind: sarif://PATH/TO/FILE.sarif"),
+		},
+		{
+			name: "test valid sarif",
+			args: args{
+				out: []string{"sarif:///tmp/foobar.sarif"},
+			},
+			want: []output.OutputConfig{
+				{
+					Key:  "sarif",
+					Path: "/tmp/foobar.sarif",
+				},
+			},
+			err: nil,
+		},
+		{
+			name: "test empty json v2",
+			args: args{
+				out: []string{"json+v2://"},
+			},
+			want: []output.OutputConfig{},
+			err:  fmt.Errorf("Invalid json output 'json+v2://': \nMust be of kind: json+v2://PATH/TO/FILE.json"),
+		},
+		{
+			name: "test valid json v2",
+			args: args{
+				out: []string{"json+v2:///tmp/foobar.json"},
+			},
+			want: []output.OutputConfig{
+				{
+					Key:  "json+v2",
+					Path: "/tmp/foobar.json",
+				},
+			},
+			err: nil,
+		},
+		{
+			name: "test empty junit",
+			args: args{
+				out: []string{"junit://"},
+			},
+			want: []output.OutputConfig{},
+			err:  fmt.Errorf("Invalid junit output 'junit://': \nMust be of kind: junit://PATH/TO/FILE.xml"),
+		},
+		{
+			name: "test valid junit",
+			args: args{
+				out: []string{"junit:///tmp/foobar.xml"},
+			},
+			want: []output.OutputConfig{
+				{
+					Key:  "junit",
+					Path: "/tmp/foobar.xml",
+				},
+			},
+			err: nil,
+		},
+		{
+			name: "test empty markdown",
+			args: args{
+				out: []string{"markdown://"},
+			},
+			want: []output.OutputConfig{},
+			err:  fmt.Errorf("Invalid markdown output 'markdown://': \nMust be of kind: markdown://PATH/TO/FILE.md"),
+		},
+		{
+			name: "test valid markdown",
+			args: args{
+				out: []string{"markdown:///tmp/foobar.md"},
+			},
+			want: []output.OutputConfig{
+				{
+					Key:  "markdown",
+					Path: "/tmp/foobar.md",
+				},
+			},
+			err: nil,
+		},
+		{
+			name: "test template without output path",
+			args: args{
+				out: []string{"template://scan/output/testdata/template.csv.tmpl"},
+			},
+			want: []output.OutputConfig{},
+			err:  fmt.Errorf("Invalid template output 'template://scan/output/testdata/template.csv.tmpl': \nMust be of kind: template://PATH/TO/TEMPLATE.tmpl:PATH/TO/FILE"),
+		},
+		{
+			name: "test template with missing template file",
+			args: args{
+				out: []string{"template://testdata/missing.tmpl:/tmp/foobar.csv"},
+			},
+			want: []output.OutputConfig{},
+			err:  fmt.Errorf("Invalid template output 'template://testdata/missing.tmpl:/tmp/foobar.csv': stat testdata/missing.tmpl: no such file or directory"),
+		},
+		{
+			name: "test valid template",
+			args: args{
+				out: []string{"template://scan/output/testdata/template.csv.tmpl:/tmp/foobar.csv"},
+			},
+			want: []output.OutputConfig{
+				{
+					Key:          "template",
+					Path:         "/tmp/foobar.csv",
+					TemplatePath: "scan/output/testdata/template.csv.tmpl",
+				},
+			},
+			err: nil,
+		},
+		{
+			name: "test console with options",
+			args: args{
+				out: []string{"console://?group-by=tag:team&summary"},
+			},
+			want: []output.OutputConfig{
+				{
+					Key: "console",
+					ConsoleOptions: &output.ConsoleOptions{
+						GroupBy: output.ConsoleGroupByTag,
+						Tag:     "team",
+						Summary: true,
+					},
+				},
+			},
+			err: nil,
+		},
+		{
+			name: "test console with invalid options",
+			args: args{
+				out: []string{"console://?group-by=owner"},
+			},
+			want: []output.OutputConfig{},
+			err:  fmt.Errorf("Invalid console output 'console://?group-by=owner', unable to group resources by 'owner': \nMust be of kind: console://?group-by=type|source|module|tag:KEY&summary"),
+		},
+		{
+			name: "test invalid openmetrics",
+			args: args{
+				out: []string{"openmetrics://"},
+			},
+			want: []output.OutputConfig{},
+			err:  fmt.Errorf("Invalid openmetrics output 'openmetrics://': \nMust be of kind: openmetrics://PATH/TO/FILE.prom"),
+		},
+		{
+			name: "test valid openmetrics",
+			args: args{
+				out: []string{"openmetrics:///var/lib/node_exporter/driftctl.prom"},
+			},
+			want: []output.OutputConfig{
+				{
+					Key:  "openmetrics",
+					Path: "/var/lib/node_exporter/driftctl.prom",
+				},
+			},
+			err: nil,
+		},
+		{
+			name: "test webhook without url",
+			args: args{
+				out: []string{"webhook://"},
+			},
+			want: []output.OutputConfig{},
+			err:  fmt.Errorf("Invalid webhook output 'webhook://': \nMust be of kind: webhook://HOST/PATH"),
+		},
+		{
+			name: "test webhook with a url scheme",
+			args: args{
+				out: []string{"webhook://http://localhost:8080/hook"},
+			},
+			want: []output.OutputConfig{
+				{
+					Key:  "webhook",
+					Path: "http://localhost:8080/hook",
+				},
+			},
+			err: nil,
+		},
+		{
+			name: "test valid slack and teams",
+			args: args{
+				out: []string{"slack://hooks.slack.com/services/T0/B0/XXX", "teams://example.webhook.office.com/webhookb2/XXX"},
+			},
+			want: []output.OutputConfig{
+				{
+					Key:  "slack",
+					Path: "hooks.slack.com/services/T0/B0/XXX",
+				},
+				{
+					Key:  "teams",
+					Path: "example.webhook.office.com/webhookb2/XXX",
+				},
+			},
+			err: nil,
+		},
 		{
 			name: "test multiple output values",
 			args: args{
@@ -297,7 +572,7 @@ func Test_parseOutputFlag(t *testing.T) {
 					Key: "console",
 				},
 			},
-			err: fmt.Errorf("Unsupported output 'invalid': \nValid formats are: console://,html://PATH/TO/FILE.html,json://PATH/TO/FILE.json,plan://PATH/TO/FILE.json"),
+			err: fmt.Errorf("Unsupported output 'invalid': \nValid formats are: %s", strings.Join(output.SupportedOutputsExample(), ",")),
 		},
 		{
 			name: "test multiple valid output values",
@@ -344,28 +619,36 @@ func Test_Options(t *testing.T) {
 			name: "lockfile should be ignored by tf-provider-version flag",
 			args: []string{"scan", "--to", "aws+tf", "--tf-lockfile", "testdata/terraform_valid.lock.hcl", "--tf-provider-version", "3.41.0"},
 			assertOptions: func(t *testing.T, opts *pkg.ScanOptions) {
-				assert.Equal(t, "3.41.0", opts.ProviderVersion)
+				assert.Equal(t, map[string]string{"aws+tf": "3.41.0"}, opts.ProviderVersions)
 			},
 		},
 		{
 			name: "should get provider version from lockfile",
 			args: []string{"scan", "--to", "aws+tf", "--tf-lockfile", "testdata/terraform_valid.lock.hcl"},
 			assertOptions: func(t *testing.T, opts *pkg.ScanOptions) {
-				assert.Equal(t, "3.47.0", opts.ProviderVersion)
+				assert.Equal(t, map[string]string{"aws+tf": "3.47.0"}, opts.ProviderVersions)
+			},
+		},
+		{
+			name: "should get providers versions from lockfile",
+			args: []string{"scan", "--to", "aws+tf,gcp+tf", "--tf-lockfile", "testdata/terraform_valid.lock.hcl"},
+			assertOptions: func(t *testing.T, opts *pkg.ScanOptions) {
+				assert.Equal(t, []string{"aws+tf", "gcp+tf"}, opts.To)
+				assert.Equal(t, map[string]string{"aws+tf": "3.47.0"}, opts.ProviderVersions)
 			},
 		},
 		{
 			name: "should not find provider version in lockfile",
 			args: []string{"scan", "--to", "gcp+tf", "--tf-lockfile", "testdata/terraform_valid.lock.hcl"},
 			assertOptions: func(t *testing.T, opts *pkg.ScanOptions) {
-				assert.Equal(t, "", opts.ProviderVersion)
+				assert.Empty(t, opts.ProviderVersions)
 			},
 		},
 		{
 			name: "should fail to read lockfile with silent error",
 			args: []string{"scan", "--to", "gcp+tf", "--tf-lockfile", "testdata/terraform_invalid.lock.hcl"},
 			assertOptions: func(t *testing.T, opts *pkg.ScanOptions) {
-				assert.Equal(t, "", opts.ProviderVersion)
+				assert.Empty(t, opts.ProviderVersions)
 			},
 		},
 	}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/snyk/driftctl/pkg"
+	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/pkg/memstore"
+	"github.com/snyk/driftctl/pkg/serve"
+)
+
+func NewServeCmd(opts *pkg.ScanOptions) *cobra.Command {
+	serveOpts := serve.Options{}
+
+	// Serve accepts every scan flag, they are used for each scan
+	scanCmd := NewScanCmd(opts)
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run scans on a schedule and serve their results over HTTP",
+		Long: "This command will run scans in background and keep their results in memory, " +
+			"they can be retrieved and new scans can be triggered through a REST API:\n" +
+			"  POST /api/scans              trigger a scan\n" +
+			"  GET  /api/scans              list scans\n" +
+			"  GET  /api/scans/{id}         get the result of a scan as JSON, use latest as ID for the most recent one\n" +
+			"  GET  /api/scans/{id}/html    get the HTML report of a scan\n" +
+			"  GET  /api/coverage           get the coverage of the most recent scan\n" +
+			"  GET  /                       HTML report of the most recent scan" +
+			"\n\nThe API has no authentication unless a token is given, requests must then send an Authorization: Bearer TOKEN header." +
+			"\n\nExample: DCTL_TOKEN=secret driftctl serve --interval 1h --keep-results 24 --from tfstate+s3://my-bucket/terraform.tfstate",
+		Args:    cobra.NoArgs,
+		PreRunE: scanCmd.PreRunE,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			listen, _ := cmd.Flags().GetString("listen")
+			if serveOpts.Interval < 0 {
+				return errors.New("Interval must be a positive duration")
+			}
+			if serveOpts.Token == "" && !isLoopback(listen) {
+				logrus.Warnf("Listening on %s without token, anyone able to reach it can trigger scans and read their results", listen)
+			}
+			return serveRun(opts, serveOpts, listen)
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.AddFlagSet(scanCmd.Flags())
+	// Results are served over HTTP instead of being written to outputs
+	_ = fl.MarkHidden("output")
+	_ = fl.MarkHidden("quiet")
+
+	fl.String("listen", "127.0.0.1:8080", "Address the HTTP server listens on, use :8080 to listen on every interface")
+	fl.StringVar(&serveOpts.Token, "token", "", "Bearer token required to call the API, prefer the DCTL_TOKEN environment variable to keep it out of the process list")
+	fl.DurationVar(&serveOpts.Interval, "interval", 0, "Interval between two scheduled scans, e.g. 1h. Scans only run when triggered through the API by default")
+	fl.IntVar(&serveOpts.KeepResults, "keep-results", 10, "Number of scan results kept in memory")
+
+	return cmd
+}
+
+// isLoopback tells whether the listen address only accepts local connections
+func isLoopback(listen string) bool {
+	host, _, err := net.SplitHostPort(listen)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func serveRun(opts *pkg.ScanOptions, serveOpts serve.Options, listen string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := serve.NewServer(ctx, func(ctx context.Context) (*analyser.Analysis, error) {
+		analysis, err := runScan(ctx, opts, memstore.New())
+		if err != nil {
+			return nil, err
+		}
+		recordHistory(opts, analysis)
+		return analysis, nil
+	}, serveOpts)
+
+	httpServer := &http.Server{
+		Addr:    listen,
+		Handler: server.Handler(),
+	}
+
+	go server.Schedule()
+
+	errs := make(chan error, 1)
+	go func() {
+		logrus.Infof("Listening on %s", listen)
+		errs <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	logrus.Warn("Detected interrupt, shutting down ...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := httpServer.Shutdown(shutdownCtx)
+	server.Wait()
+	return err
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/snyk/driftctl/pkg"
+)
+
+func TestServeCmd_DefaultListen(t *testing.T) {
+	cmd := NewServeCmd(&pkg.ScanOptions{})
+	listen, err := cmd.Flags().GetString("listen")
+	assert.NoError(t, err)
+	assert.True(t, isLoopback(listen), "serve should only listen on loopback by default, got %s", listen)
+}
+
+func Test_isLoopback(t *testing.T) {
+	tests := []struct {
+		listen string
+		want   bool
+	}{
+		{listen: "127.0.0.1:8080", want: true},
+		{listen: "localhost:8080", want: true},
+		{listen: "[::1]:8080", want: true},
+		{listen: ":8080", want: false},
+		{listen: "0.0.0.0:8080", want: false},
+		{listen: "10.0.0.12:8080", want: false},
+		{listen: "invalid", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.listen, func(t *testing.T) {
+			assert.Equal(t, tt.want, isLoopback(tt.listen))
+		})
+	}
+}
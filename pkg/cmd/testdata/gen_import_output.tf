@@ -0,0 +1,60 @@
+import {
+  to = github_membership.driftctl-test_eliecharra
+  id = "driftctl-test:eliecharra"
+}
+
+resource "github_membership" "driftctl-test_eliecharra" {
+  role     = "admin"
+  username = "eliecharra"
+}
+
+import {
+  to = github_repository.driftctl
+  id = "driftctl"
+}
+
+resource "github_repository" "driftctl" {
+  allow_merge_commit     = true
+  allow_rebase_merge     = false
+  allow_squash_merge     = false
+  archived               = false
+  default_branch         = "main"
+  delete_branch_on_merge = true
+  description            = "Detect, track and alert on infrastructure drift"
+  has_downloads          = true
+  has_issues             = true
+  has_projects           = true
+  has_wiki               = false
+  homepage_url           = "https://driftctl.com"
+  is_template            = false
+  name                   = "driftctl"
+  private                = false
+  topics                 = ["aws", "drift", "iac", "infrastructure-drift", "terraform"]
+  visibility             = "public"
+  vulnerability_alerts   = false
+}
+
+import {
+  to = github_team._4556812
+  id = "4556812"
+}
+
+resource "github_team" "_4556812" {
+  create_default_maintainer = false
+  description               = "test 2"
+  name                      = "team2"
+  privacy                   = "secret"
+}
+
+import {
+  to = github_team._4556814
+  id = "4556814"
+}
+
+resource "github_team" "_4556814" {
+  create_default_maintainer = false
+  description               = "test parent team"
+  name                      = "new team with parent"
+  parent_team_id            = 4556811
+  privacy                   = "closed"
+}
@@ -16,6 +16,8 @@ import (
 	"github.com/snyk/driftctl/pkg/iac/config"
 	"github.com/snyk/driftctl/pkg/iac/terraform/state/backend"
 	"github.com/snyk/driftctl/pkg/middlewares"
+	"github.com/snyk/driftctl/pkg/policy"
+	"github.com/snyk/driftctl/pkg/remote/aws"
 	"github.com/snyk/driftctl/pkg/resource"
 )
 
@@ -23,17 +25,27 @@ type ScanOptions struct {
 	Coverage         bool
 	Detect           bool
 	From             []config.SupplierConfig
-	To               string
+	To               []string
 	Output           []output.OutputConfig
 	Filter           *jmespath.JMESPath
 	Quiet            bool
 	BackendOptions   *backend.Options
 	StrictMode       bool
 	DisableTelemetry bool
-	ProviderVersion  string
+	DisableHistory   bool
+	// Number of scans kept in history, every scan is kept when zero
+	HistoryRetention int
+	// Terraform provider versions indexed by remote, e.g. aws+tf
+	ProviderVersions map[string]string
 	ConfigDir        string
 	DriftignorePath  string
 	Deep             bool
+	AWSOptions       aws.Options
+	// Conditions failing the scan, any drift fails it when empty
+	FailOn []policy.Condition
+	// Changes below MinSeverity are not reported
+	MinSeverity   analyser.Severity
+	SeverityRules *analyser.SeverityRules
 }
 
 type DriftCTL struct {
@@ -0,0 +1,141 @@
+package filter
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/snyk/driftctl/pkg/resource"
+)
+
+// Prefix of driftignore rules evaluated against attributes of resources instead of their type and id
+const attributeRulePrefix = "@attr "
+
+// @attr [TYPE] PATH OPERATOR VALUE, where TYPE is a glob and VALUE may be double quoted
+var attributeRuleRegexp = regexp.MustCompile(`^@attr\s+(?:(\S+)\s+)?([^\s=!^$*~]+)\s*(==|!=|\^=|\$=|\*=|=~)\s*(.+?)\s*$`)
+
+// attributeRule ignores resources whose attribute at path compares to value, resources without the attribute never match
+type attributeRule struct {
+	ty       string
+	path     []string
+	operator string
+	value    string
+	regexp   *regexp.Regexp
+}
+
+func parseAttributeRule(rule string) (*attributeRule, error) {
+	matches := attributeRuleRegexp.FindStringSubmatch(rule)
+	if matches == nil {
+		return nil, errors.Errorf("invalid attribute rule '%s', expected %sTYPE PATH OPERATOR VALUE", rule, attributeRulePrefix)
+	}
+
+	r := &attributeRule{
+		ty:       matches[1],
+		path:     strings.Split(matches[2], "."),
+		operator: matches[3],
+		value:    matches[4],
+	}
+	if _, err := path.Match(r.ty, ""); err != nil {
+		return nil, errors.Wrapf(err, "invalid type of attribute rule '%s'", rule)
+	}
+	if strings.HasPrefix(r.value, `"`) {
+		value, err := strconv.Unquote(r.value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid value of attribute rule '%s'", rule)
+		}
+		r.value = value
+	}
+	if r.operator == "=~" {
+		re, err := regexp.Compile(r.value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid regular expression of attribute rule '%s'", rule)
+		}
+		r.regexp = re
+	}
+	return r, nil
+}
+
+func (r *attributeRule) Match(res *resource.Resource) bool {
+	if res == nil || res.Attributes() == nil {
+		return false
+	}
+	if r.ty != "" {
+		if ok, _ := path.Match(r.ty, res.ResourceType()); !ok {
+			return false
+		}
+	}
+
+	value, found := lookupAttribute(map[string]interface{}(*res.Attributes()), r.path)
+	if !found {
+		return false
+	}
+	str, ok := attributeString(value)
+	if !ok {
+		return false
+	}
+
+	switch r.operator {
+	case "==":
+		return str == r.value
+	case "!=":
+		return str != r.value
+	case "^=":
+		return strings.HasPrefix(str, r.value)
+	case "$=":
+		return strings.HasSuffix(str, r.value)
+	case "*=":
+		return strings.Contains(str, r.value)
+	case "=~":
+		return r.regexp.MatchString(str)
+	}
+	return false
+}
+
+// lookupAttribute follows a dotted path, keys may contain dots themselves (e.g. tags.kubernetes.io/cluster)
+// and fallback on a case insensitive comparison (e.g. Tags.Name)
+func lookupAttribute(value interface{}, segments []string) (interface{}, bool) {
+	if len(segments) == 0 {
+		return value, value != nil
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for n := len(segments); n > 0; n-- {
+			key := strings.Join(segments[:n], ".")
+			if child, exist := v[key]; exist {
+				if res, found := lookupAttribute(child, segments[n:]); found {
+					return res, true
+				}
+			}
+			for k, child := range v {
+				if k != key && strings.EqualFold(k, key) {
+					if res, found := lookupAttribute(child, segments[n:]); found {
+						return res, true
+					}
+				}
+			}
+		}
+	case []interface{}:
+		i, err := strconv.Atoi(segments[0])
+		if err != nil || i < 0 || i >= len(v) {
+			return nil, false
+		}
+		return lookupAttribute(v[i], segments[1:])
+	}
+	return nil, false
+}
+
+// attributeString renders scalar values, lists and maps cannot be compared
+func attributeString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case bool, int, int64, float64:
+		return fmt.Sprint(v), true
+	}
+	return "", false
+}
@@ -0,0 +1,113 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/snyk/driftctl/pkg/resource"
+)
+
+func TestParseAttributeRule(t *testing.T) {
+	cases := []struct {
+		name     string
+		rule     string
+		expected *attributeRule
+		err      string
+	}{
+		{
+			name:     "rule without type",
+			rule:     `@attr Tags.managed-by == "karpenter"`,
+			expected: &attributeRule{path: []string{"Tags", "managed-by"}, operator: "==", value: "karpenter"},
+		},
+		{
+			name:     "rule with type and unquoted value",
+			rule:     "@attr aws_iam_role path ^= /aws-reserved/",
+			expected: &attributeRule{ty: "aws_iam_role", path: []string{"path"}, operator: "^=", value: "/aws-reserved/"},
+		},
+		{
+			name:     "quoted value with spaces",
+			rule:     `@attr aws_* tags.Name != "do not touch"`,
+			expected: &attributeRule{ty: "aws_*", path: []string{"tags", "Name"}, operator: "!=", value: "do not touch"},
+		},
+		{
+			name: "missing operator",
+			rule: "@attr tags.Name karpenter",
+			err:  "invalid attribute rule '@attr tags.Name karpenter', expected @attr TYPE PATH OPERATOR VALUE",
+		},
+		{
+			name: "invalid quoted value",
+			rule: `@attr tags.Name == "karpenter`,
+			err:  `invalid value of attribute rule '@attr tags.Name == "karpenter': invalid syntax`,
+		},
+		{
+			name: "invalid regular expression",
+			rule: "@attr tags.Name =~ kar(",
+			err:  "invalid regular expression of attribute rule '@attr tags.Name =~ kar('",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rule, err := parseAttributeRule(c.rule)
+			if c.err != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), c.err)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, rule)
+		})
+	}
+}
+
+func TestAttributeRule_Match(t *testing.T) {
+	role := &resource.Resource{
+		Id:   "AWSReservedSSO_Admin",
+		Type: "aws_iam_role",
+		Attrs: &resource.Attributes{
+			"path": "/aws-reserved/sso.amazonaws.com/",
+			"tags": map[string]interface{}{
+				"managed-by":            "karpenter",
+				"kubernetes.io/cluster": "owned",
+			},
+			"max_session_duration": float64(3600),
+			"inline_policy": []interface{}{
+				map[string]interface{}{"name": "admin"},
+			},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		rule     string
+		res      *resource.Resource
+		expected bool
+	}{
+		{name: "equal", rule: `@attr tags.managed-by == "karpenter"`, res: role, expected: true},
+		{name: "case insensitive keys", rule: `@attr Tags.managed-by == "karpenter"`, res: role, expected: true},
+		{name: "keys containing dots", rule: `@attr tags.kubernetes.io/cluster == owned`, res: role, expected: true},
+		{name: "not equal", rule: `@attr tags.managed-by != "karpenter"`, res: role, expected: false},
+		{name: "starts with", rule: "@attr aws_iam_role path ^= /aws-reserved/", res: role, expected: true},
+		{name: "ends with", rule: "@attr path $= .amazonaws.com/", res: role, expected: true},
+		{name: "contains", rule: "@attr path *= sso", res: role, expected: true},
+		{name: "regular expression", rule: "@attr path =~ ^/aws-(reserved|service-role)/", res: role, expected: true},
+		{name: "number", rule: "@attr max_session_duration == 3600", res: role, expected: true},
+		{name: "list index", rule: "@attr inline_policy.0.name == admin", res: role, expected: true},
+		{name: "other type", rule: "@attr aws_iam_user path ^= /aws-reserved/", res: role, expected: false},
+		{name: "type glob", rule: "@attr aws_iam_* path ^= /aws-reserved/", res: role, expected: true},
+		{name: "missing attribute", rule: "@attr tags.team != platform", res: role, expected: false},
+		{name: "map attribute", rule: "@attr tags == karpenter", res: role, expected: false},
+		{name: "resource without attributes", rule: "@attr path ^= /aws-reserved/", res: &resource.Resource{Id: "role", Type: "aws_iam_role"}, expected: false},
+		{name: "no resource", rule: "@attr path ^= /aws-reserved/", expected: false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rule, err := parseAttributeRule(c.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, c.expected, rule.Match(c.res))
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
 	"github.com/sirupsen/logrus"
@@ -13,15 +15,78 @@ import (
 
 const separator = "_-_"
 
+// Layout of expiry dates of driftignore rules
+const expiresLayout = "2006-01-02"
+
+// IgnoreRule is a line of a driftignore file, with the metadata of its trailing comment if any, e.g.
+// aws_s3_bucket.foo # expires=2026-12-31 owner=team-net reason=legacy bucket
+type IgnoreRule struct {
+	Pattern string     `json:"pattern"`
+	Line    int        `json:"line"`
+	Expires *time.Time `json:"expires,omitempty"`
+	Owner   string     `json:"owner,omitempty"`
+	Reason  string     `json:"reason,omitempty"`
+	// Matches counts resources, types and fields ignored by the rule during the scan
+	Matches int64 `json:"matches"`
+}
+
+// IsExpired tells whether the expiry date of the rule is past, rules expire at the end of that day
+func (r IgnoreRule) IsExpired(now time.Time) bool {
+	return r.Expires != nil && !now.Before(r.Expires.AddDate(0, 0, 1))
+}
+
+type ExpiredIgnoreRuleAlert struct {
+	rule IgnoreRule
+}
+
+func NewExpiredIgnoreRuleAlert(rule IgnoreRule) *ExpiredIgnoreRuleAlert {
+	return &ExpiredIgnoreRuleAlert{rule}
+}
+
+func (a *ExpiredIgnoreRuleAlert) Message() string {
+	msg := fmt.Sprintf("Driftignore rule '%s' (line %d) expired on %s and is not applied anymore", a.rule.Pattern, a.rule.Line, a.rule.Expires.Format(expiresLayout))
+	if a.rule.Owner != "" {
+		msg += fmt.Sprintf(", ask %s whether it is still needed", a.rule.Owner)
+	}
+	return msg
+}
+
+func (a *ExpiredIgnoreRuleAlert) ShouldIgnoreResource() bool {
+	return false
+}
+
+type AttributeRuleWithoutDeepAlert struct {
+	rule IgnoreRule
+}
+
+func NewAttributeRuleWithoutDeepAlert(rule IgnoreRule) *AttributeRuleWithoutDeepAlert {
+	return &AttributeRuleWithoutDeepAlert{rule}
+}
+
+func (a *AttributeRuleWithoutDeepAlert) Message() string {
+	return fmt.Sprintf("Driftignore rule '%s' (line %d) only applies to resources read with their attributes, unmanaged resources are not read with them unless the scan runs with --deep", a.rule.Pattern, a.rule.Line)
+}
+
+func (a *AttributeRuleWithoutDeepAlert) ShouldIgnoreResource() bool {
+	return false
+}
+
+type ignoreRule struct {
+	// First field to be 64-bit aligned for atomic operations
+	matches int64
+	IgnoreRule
+	patterns  []gitignore.Pattern
+	attribute *attributeRule
+}
+
 type DriftIgnore struct {
 	driftignorePath string
-	matcher         gitignore.Matcher
+	rules           []*ignoreRule
 }
 
 func NewDriftIgnore(path string) *DriftIgnore {
 	d := DriftIgnore{
 		driftignorePath: path,
-		matcher:         gitignore.NewMatcher(nil),
 	}
 	err := d.readIgnoreFile()
 	if err != nil {
@@ -37,7 +102,8 @@ func (r *DriftIgnore) readIgnoreFile() error {
 	}
 	defer file.Close()
 
-	var lines []gitignore.Pattern
+	now := time.Now()
+	var rules []*ignoreRule
 	scanner := bufio.NewScanner(file)
 	for lineNumber := 1; scanner.Scan(); lineNumber++ {
 		line := scanner.Text()
@@ -49,12 +115,28 @@ func (r *DriftIgnore) readIgnoreFile() error {
 		if strings.HasPrefix(line, "#") {
 			continue // this is a comment
 		}
-		line = strings.ReplaceAll(line, "/", separator)
 
-		lines = append(lines, gitignore.ParsePattern(line, nil))
-		if !strings.HasSuffix(line, "*") {
-			line := fmt.Sprintf("%s.*", line)
-			lines = append(lines, gitignore.ParsePattern(line, nil))
+		rule := &ignoreRule{IgnoreRule: parseIgnoreRule(line, lineNumber)}
+		rules = append(rules, rule)
+		if rule.IsExpired(now) {
+			continue // expired rules are kept to be reported but do not match anymore
+		}
+
+		if strings.HasPrefix(rule.Pattern, attributeRulePrefix) {
+			attribute, err := parseAttributeRule(rule.Pattern)
+			if err != nil {
+				logrus.WithField("line", lineNumber).Warnf("Ignoring driftignore rule: %s", err)
+				continue
+			}
+			rule.attribute = attribute
+			continue
+		}
+
+		pattern := strings.ReplaceAll(rule.Pattern, "/", separator)
+		rule.patterns = append(rule.patterns, gitignore.ParsePattern(pattern, nil))
+		if !strings.HasSuffix(pattern, "*") {
+			pattern := fmt.Sprintf("%s.*", pattern)
+			rule.patterns = append(rule.patterns, gitignore.ParsePattern(pattern, nil))
 		}
 	}
 
@@ -62,15 +144,94 @@ func (r *DriftIgnore) readIgnoreFile() error {
 		return err
 	}
 
-	r.matcher = gitignore.NewMatcher(lines)
+	r.rules = rules
 
 	return nil
 }
 
+// parseIgnoreRule splits a line into its pattern and the metadata of its trailing comment,
+// a trailing comment without any known key is considered as part of the pattern
+func parseIgnoreRule(line string, lineNumber int) IgnoreRule {
+	rule := IgnoreRule{Pattern: line, Line: lineNumber}
+	i := strings.LastIndex(line, " #")
+	if i == -1 {
+		return rule
+	}
+
+	metadata := make(map[string]string)
+	key := ""
+	for _, token := range strings.Fields(line[i+2:]) {
+		if parts := strings.SplitN(token, "=", 2); len(parts) == 2 && isIgnoreRuleKey(parts[0]) {
+			key = parts[0]
+			metadata[key] = parts[1]
+			continue
+		}
+		if key == "" {
+			return rule
+		}
+		// Values span up to the next key so that reasons can contain spaces
+		metadata[key] = strings.TrimSpace(metadata[key] + " " + token)
+	}
+	if key == "" {
+		return rule
+	}
+
+	rule.Pattern = strings.TrimRight(line[:i], " ")
+	rule.Owner = metadata["owner"]
+	rule.Reason = strings.Trim(metadata["reason"], "\"")
+	if expires, ok := metadata["expires"]; ok {
+		date, err := time.Parse(expiresLayout, expires)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{"line": lineNumber, "expires": expires}).Warn("Invalid expiry date in driftignore, expected YYYY-MM-DD")
+		} else {
+			rule.Expires = &date
+		}
+	}
+	return rule
+}
+
+func isIgnoreRuleKey(key string) bool {
+	return key == "expires" || key == "owner" || key == "reason"
+}
+
+// Rules returns rules read from the driftignore file with the number of times each of them matched so far
+func (r *DriftIgnore) Rules() []IgnoreRule {
+	rules := make([]IgnoreRule, 0, len(r.rules))
+	for _, rule := range r.rules {
+		res := rule.IgnoreRule
+		res.Matches = atomic.LoadInt64(&rule.matches)
+		rules = append(rules, res)
+	}
+	return rules
+}
+
+// ExpiredRules returns rules not applied because of their expiry date
+func (r *DriftIgnore) ExpiredRules() []IgnoreRule {
+	now := time.Now()
+	expired := make([]IgnoreRule, 0)
+	for _, rule := range r.Rules() {
+		if rule.IsExpired(now) {
+			expired = append(expired, rule)
+		}
+	}
+	return expired
+}
+
+// AttributeRules returns applied rules matching attributes of resources
+func (r *DriftIgnore) AttributeRules() []IgnoreRule {
+	rules := make([]IgnoreRule, 0)
+	for _, rule := range r.rules {
+		if rule.attribute != nil {
+			rules = append(rules, rule.IgnoreRule)
+		}
+	}
+	return rules
+}
+
 func (r *DriftIgnore) isAnyOfChildrenTypesNotIgnored(ty resource.ResourceType) bool {
 	childrenTypes := resource.GetMeta(ty).GetChildrenTypes()
 	for _, childrenType := range childrenTypes {
-		if !r.match(fmt.Sprintf("%s.*", childrenType)) {
+		if !r.match(fmt.Sprintf("%s.*", childrenType), nil) {
 			return true
 		}
 		if r.isAnyOfChildrenTypesNotIgnored(childrenType) {
@@ -87,18 +248,34 @@ func (r *DriftIgnore) IsTypeIgnored(ty resource.ResourceType) bool {
 		return false
 	}
 
-	return r.match(fmt.Sprintf("%s.*", ty))
+	return r.match(fmt.Sprintf("%s.*", ty), nil)
 }
 
 func (r *DriftIgnore) IsResourceIgnored(res *resource.Resource) bool {
-	return r.match(fmt.Sprintf("%s.%s", res.ResourceType(), res.ResourceId()))
+	return r.match(fmt.Sprintf("%s.%s", res.ResourceType(), res.ResourceId()), res)
 }
 
 func (r *DriftIgnore) IsFieldIgnored(res *resource.Resource, path []string) bool {
 	full := fmt.Sprintf("%s.%s.%s", res.ResourceType(), res.ResourceId(), strings.Join(path, "."))
-	return r.match(full)
+	return r.match(full, res)
 }
 
-func (r *DriftIgnore) match(strRes string) bool {
-	return r.matcher.Match([]string{strings.ReplaceAll(strRes, "/", separator)}, false)
+// match applies rules the way gitignore does, the last matching pattern wins, and counts matches of the deciding rule.
+// Attribute rules only apply when the resource is given, they ignore every field of matching resources.
+func (r *DriftIgnore) match(strRes string, res *resource.Resource) bool {
+	path := []string{strings.ReplaceAll(strRes, "/", separator)}
+	for i := len(r.rules) - 1; i >= 0; i-- {
+		rule := r.rules[i]
+		if rule.attribute != nil && rule.attribute.Match(res) {
+			atomic.AddInt64(&rule.matches, 1)
+			return true
+		}
+		for j := len(rule.patterns) - 1; j >= 0; j-- {
+			if m := rule.patterns[j].Match(path, false); m > gitignore.NoMatch {
+				atomic.AddInt64(&rule.matches, 1)
+				return m == gitignore.Exclude
+			}
+		}
+	}
+	return false
 }
@@ -4,6 +4,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -534,3 +535,78 @@ func TestDriftIgnore_IsTypeIgnored(t *testing.T) {
 		})
 	}
 }
+
+func TestDriftIgnore_Rules(t *testing.T) {
+	r := NewDriftIgnore("testdata/drift_ignore_metadata/.driftignore")
+
+	assert.False(t, r.IsResourceIgnored(&resource.Resource{Type: "aws_s3_bucket", Id: "legacy"}))
+	assert.True(t, r.IsResourceIgnored(&resource.Resource{Type: "aws_s3_bucket", Id: "kept"}))
+	assert.True(t, r.IsResourceIgnored(&resource.Resource{Type: "aws_iam_user", Id: "foo"}))
+	assert.True(t, r.IsResourceIgnored(&resource.Resource{Type: "aws_iam_user", Id: "bar"}))
+	assert.True(t, r.IsFieldIgnored(&resource.Resource{Type: "aws_instance", Id: "invalid"}, []string{"tags"}))
+
+	expired := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	kept := time.Date(2999, 12, 31, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []IgnoreRule{
+		{Pattern: "aws_s3_bucket.legacy", Line: 2, Expires: &expired, Owner: "team-storage", Reason: "migrated in Q1"},
+		{Pattern: "aws_s3_bucket.kept", Line: 3, Expires: &kept, Owner: "team-storage", Reason: "kept for audit", Matches: 1},
+		{Pattern: "aws_iam_user.*", Line: 4, Owner: "team-iam", Matches: 2},
+		{Pattern: "aws_instance.unowned", Line: 5},
+		{Pattern: "aws_instance.with#hash # not metadata", Line: 6},
+		{Pattern: "aws_instance.invalid", Line: 7, Owner: "team-compute", Matches: 1},
+	}, r.Rules())
+
+	assert.Equal(t, []IgnoreRule{
+		{Pattern: "aws_s3_bucket.legacy", Line: 2, Expires: &expired, Owner: "team-storage", Reason: "migrated in Q1"},
+	}, r.ExpiredRules())
+}
+
+func TestExpiredIgnoreRuleAlert_Message(t *testing.T) {
+	expires := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	rule := IgnoreRule{Pattern: "aws_s3_bucket.legacy", Line: 2, Expires: &expires}
+	assert.Equal(t, "Driftignore rule 'aws_s3_bucket.legacy' (line 2) expired on 2000-01-01 and is not applied anymore", NewExpiredIgnoreRuleAlert(rule).Message())
+
+	rule.Owner = "team-storage"
+	assert.Equal(t, "Driftignore rule 'aws_s3_bucket.legacy' (line 2) expired on 2000-01-01 and is not applied anymore, ask team-storage whether it is still needed", NewExpiredIgnoreRuleAlert(rule).Message())
+}
+
+func TestDriftIgnore_AttributeRulesList(t *testing.T) {
+	r := NewDriftIgnore("testdata/drift_ignore_attributes/.driftignore")
+	assert.Equal(t, []IgnoreRule{
+		{Pattern: `@attr tags.managed-by == "karpenter"`, Line: 1, Owner: "team-k8s"},
+		{Pattern: "@attr aws_iam_role path ^= /aws-reserved/", Line: 2},
+	}, r.AttributeRules())
+}
+
+func TestAttributeRuleWithoutDeepAlert_Message(t *testing.T) {
+	rule := IgnoreRule{Pattern: "@attr aws_iam_role path ^= /aws-reserved/", Line: 2}
+	assert.Equal(t, "Driftignore rule '@attr aws_iam_role path ^= /aws-reserved/' (line 2) only applies to resources read with their attributes, unmanaged resources are not read with them unless the scan runs with --deep", NewAttributeRuleWithoutDeepAlert(rule).Message())
+}
+
+func TestDriftIgnore_AttributeRules(t *testing.T) {
+	r := NewDriftIgnore("testdata/drift_ignore_attributes/.driftignore")
+
+	karpenter := func(ty, id string) *resource.Resource {
+		return &resource.Resource{Type: ty, Id: id, Attrs: &resource.Attributes{
+			"tags": map[string]interface{}{"managed-by": "karpenter"},
+		}}
+	}
+
+	assert.True(t, r.IsResourceIgnored(karpenter("aws_instance", "node")))
+	assert.True(t, r.IsResourceIgnored(karpenter("aws_launch_template", "node")))
+	assert.False(t, r.IsResourceIgnored(karpenter("aws_instance", "kept")), "later negations take precedence")
+	assert.True(t, r.IsResourceIgnored(&resource.Resource{Type: "aws_iam_role", Id: "sso", Attrs: &resource.Attributes{"path": "/aws-reserved/sso.amazonaws.com/"}}))
+	assert.False(t, r.IsResourceIgnored(&resource.Resource{Type: "aws_iam_role", Id: "app", Attrs: &resource.Attributes{"path": "/"}}))
+	assert.False(t, r.IsResourceIgnored(&resource.Resource{Type: "aws_instance", Id: "web", Attrs: &resource.Attributes{}}))
+
+	assert.True(t, r.IsFieldIgnored(karpenter("aws_instance", "node"), []string{"ami"}))
+	assert.True(t, r.IsFieldIgnored(&resource.Resource{Type: "aws_instance", Id: "web"}, []string{"tags"}))
+	assert.False(t, r.IsTypeIgnored("aws_iam_role"))
+
+	rules := r.Rules()
+	if assert.Len(t, rules, 5) {
+		assert.Equal(t, IgnoreRule{Pattern: `@attr tags.managed-by == "karpenter"`, Line: 1, Owner: "team-k8s", Matches: 3}, rules[0])
+		assert.Equal(t, int64(1), rules[1].Matches)
+		assert.Equal(t, IgnoreRule{Pattern: "@attr invalid rule", Line: 5}, rules[4])
+	}
+}
@@ -0,0 +1,227 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/pkg/filter"
+)
+
+const (
+	indexFileName = "index.json"
+	lockFileName  = "index.lock"
+	idDateFormat  = "20060102-150405"
+
+	// DefaultRetention is the number of scans kept unless configured otherwise
+	DefaultRetention = 100
+
+	lockTimeout = 10 * time.Second
+	// Locks older than that were left by an interrupted process
+	staleLockAge = time.Minute
+)
+
+// Entry sums up a recorded analysis, entries are kept in an index to list them without reading every analysis
+type Entry struct {
+	ID       string           `json:"id"`
+	Date     time.Time        `json:"date"`
+	Duration time.Duration    `json:"duration"`
+	Coverage int              `json:"coverage"`
+	Summary  analyser.Summary `json:"summary"`
+}
+
+type record struct {
+	Entry    Entry              `json:"entry"`
+	Analysis *analyser.Analysis `json:"analysis"`
+	// Driftignore rules of the scan with their number of matches, for driftignore audit
+	IgnoreRules []filter.IgnoreRule `json:"ignore_rules,omitempty"`
+}
+
+// Store records analysis as JSON files in a directory, along with an index of them
+type Store struct {
+	dir string
+	// Number of scans kept, the oldest ones are deleted when saving a new one. Every scan is kept when zero
+	Retention int
+}
+
+// NewStore returns a store located in the driftctl directory of the given config directory
+func NewStore(configDir string) *Store {
+	return &Store{
+		dir:       filepath.Join(configDir, ".driftctl", "history"),
+		Retention: DefaultRetention,
+	}
+}
+
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Save records the given analysis and returns its entry, the index is locked so that concurrent scans can be saved
+func (s *Store) Save(analysis *analyser.Analysis) (*Entry, error) {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "unable to create history directory")
+	}
+
+	unlock, err := s.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	entries, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	date := analysis.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	entry := Entry{
+		ID:       newID(date, entries),
+		Date:     date,
+		Duration: analysis.Duration,
+		Coverage: analysis.Coverage(),
+		Summary:  analysis.Summary(),
+	}
+
+	if err := writeJSON(filepath.Join(s.dir, fmt.Sprintf("%s.json", entry.ID)), record{entry, analysis, analysis.IgnoreRules()}); err != nil {
+		return nil, err
+	}
+
+	entries = append(entries, entry)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Date.Before(entries[j].Date)
+	})
+	var expired []Entry
+	if s.Retention > 0 && len(entries) > s.Retention {
+		expired = entries[:len(entries)-s.Retention]
+		entries = entries[len(entries)-s.Retention:]
+	}
+	if err := writeJSON(filepath.Join(s.dir, indexFileName), entries); err != nil {
+		return nil, err
+	}
+
+	// Records are removed once they are not referenced by the index anymore
+	for _, e := range expired {
+		if err := os.Remove(filepath.Join(s.dir, fmt.Sprintf("%s.json", e.ID))); err != nil && !os.IsNotExist(err) {
+			return nil, errors.Wrapf(err, "unable to delete scan %s from history", e.ID)
+		}
+	}
+
+	return &entry, nil
+}
+
+// lock creates a lock file next to the index, it waits for other processes to release it
+func (s *Store) lock() (func(), error) {
+	path := filepath.Join(s.dir, lockFileName)
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			f.Close()
+			return func() {
+				_ = os.Remove(path)
+			}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, errors.Wrap(err, "unable to lock history index")
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleLockAge {
+			_ = os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.Errorf("unable to lock history index, remove %s if no other scan is running", path)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// List returns recorded entries from the oldest to the most recent one
+func (s *Store) List() ([]Entry, error) {
+	entries := make([]Entry, 0)
+
+	content, err := os.ReadFile(filepath.Join(s.dir, indexFileName))
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return nil, errors.Wrap(err, "history index is corrupted")
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Date.Before(entries[j].Date)
+	})
+	return entries, nil
+}
+
+// Get returns a recorded analysis, the scan date, duration and driftignore rules are restored
+func (s *Store) Get(id string) (*analyser.Analysis, *Entry, error) {
+	content, err := os.ReadFile(filepath.Join(s.dir, fmt.Sprintf("%s.json", filepath.Base(id))))
+	if os.IsNotExist(err) {
+		return nil, nil, errors.Errorf("scan %s not found in history", id)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r := record{Analysis: &analyser.Analysis{}}
+	if err := json.Unmarshal(content, &r); err != nil {
+		return nil, nil, errors.Wrapf(err, "scan %s is corrupted", id)
+	}
+	r.Analysis.Date = r.Entry.Date
+	r.Analysis.Duration = r.Entry.Duration
+	r.Analysis.SetIgnoreRules(r.IgnoreRules)
+
+	return r.Analysis, &r.Entry, nil
+}
+
+// newID builds a sortable identifier from the scan date, suffixed in case of collision
+func newID(date time.Time, entries []Entry) string {
+	base := date.UTC().Format(idDateFormat)
+	id := base
+	for i := 2; containsID(entries, id); i++ {
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+	return id
+}
+
+func containsID(entries []Entry, id string) bool {
+	for _, e := range entries {
+		if e.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// writeJSON writes to a temporary file first so that a record is never partially written
+func writeJSON(path string, v interface{}) error {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,183 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/pkg/filter"
+	"github.com/snyk/driftctl/pkg/resource"
+)
+
+func fakeAnalysis(date time.Time) *analyser.Analysis {
+	a := analyser.NewAnalysis(analyser.AnalyzerOptions{})
+	a.AddManaged(&resource.Resource{Id: "bucket", Type: "aws_s3_bucket"})
+	a.AddUnmanaged(&resource.Resource{Id: "user", Type: "aws_iam_user"})
+	a.Date = date
+	a.Duration = 12 * time.Second
+	return a
+}
+
+func TestStore(t *testing.T) {
+	configDir := t.TempDir()
+	store := NewStore(configDir)
+	assert.Equal(t, filepath.Join(configDir, ".driftctl", "history"), store.Dir())
+
+	entries, err := store.List()
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	date := time.Date(2021, 10, 13, 10, 0, 0, 0, time.UTC)
+	first, err := store.Save(fakeAnalysis(date))
+	assert.NoError(t, err)
+	assert.Equal(t, "20211013-100000", first.ID)
+	assert.Equal(t, 50, first.Coverage)
+
+	// Scans recorded at the same time get distinct identifiers
+	second, err := store.Save(fakeAnalysis(date))
+	assert.NoError(t, err)
+	assert.Equal(t, "20211013-100000-2", second.ID)
+
+	older, err := store.Save(fakeAnalysis(date.Add(-24 * time.Hour)))
+	assert.NoError(t, err)
+
+	entries, err = store.List()
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 3) {
+		assert.Equal(t, older.ID, entries[0].ID)
+		assert.Equal(t, first.ID, entries[1].ID)
+		assert.Equal(t, second.ID, entries[2].ID)
+		assert.Equal(t, 2, entries[0].Summary.TotalResources)
+	}
+
+	analysis, entry, err := store.Get(first.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, first.ID, entry.ID)
+	assert.True(t, date.Equal(analysis.Date))
+	assert.Equal(t, 12*time.Second, analysis.Duration)
+	assert.Equal(t, 50, analysis.Coverage())
+	assert.Len(t, analysis.Unmanaged(), 1)
+}
+
+func TestStore_Get_NotFound(t *testing.T) {
+	store := NewStore(t.TempDir())
+	analysis, entry, err := store.Get("20211013-100000")
+	assert.Nil(t, analysis)
+	assert.Nil(t, entry)
+	assert.EqualError(t, err, "scan 20211013-100000 not found in history")
+}
+
+func TestStore_List_Corrupted(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if err := os.MkdirAll(store.Dir(), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(store.Dir(), indexFileName), []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := store.List()
+	assert.Nil(t, entries)
+	assert.EqualError(t, err, "history index is corrupted: unexpected end of JSON input")
+}
+
+func TestStore_Retention(t *testing.T) {
+	store := NewStore(t.TempDir())
+	store.Retention = 2
+
+	date := time.Date(2021, 10, 13, 10, 0, 0, 0, time.UTC)
+	saved := make([]*Entry, 0, 3)
+	for i := 0; i < 3; i++ {
+		entry, err := store.Save(fakeAnalysis(date.Add(time.Duration(i) * time.Hour)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		saved = append(saved, entry)
+	}
+
+	entries, err := store.List()
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, saved[1].ID, entries[0].ID)
+		assert.Equal(t, saved[2].ID, entries[1].ID)
+	}
+
+	// The oldest record is deleted along with its index entry
+	_, _, err = store.Get(saved[0].ID)
+	assert.EqualError(t, err, "scan 20211013-100000 not found in history")
+	files, err := os.ReadDir(store.Dir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, files, 3)
+}
+
+func TestStore_Save_Concurrent(t *testing.T) {
+	configDir := t.TempDir()
+	date := time.Date(2021, 10, 13, 10, 0, 0, 0, time.UTC)
+
+	// Each save uses its own store as separate processes would
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := NewStore(configDir).Save(fakeAnalysis(date))
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	entries, err := NewStore(configDir).List()
+	assert.NoError(t, err)
+	assert.Len(t, entries, 10)
+	_, err = os.Stat(filepath.Join(NewStore(configDir).Dir(), lockFileName))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestStore_Save_StaleLock(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if err := os.MkdirAll(store.Dir(), 0700); err != nil {
+		t.Fatal(err)
+	}
+	lockPath := filepath.Join(store.Dir(), lockFileName)
+	if err := os.WriteFile(lockPath, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	staleDate := time.Now().Add(-2 * staleLockAge)
+	if err := os.Chtimes(lockPath, staleDate, staleDate); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := store.Save(fakeAnalysis(time.Now()))
+	assert.NoError(t, err)
+}
+
+func TestStore_IgnoreRules(t *testing.T) {
+	store := NewStore(t.TempDir())
+	rules := []filter.IgnoreRule{
+		{Pattern: "aws_s3_bucket.legacy", Line: 1, Owner: "team-storage", Matches: 3},
+		{Pattern: "aws_iam_user.*", Line: 2},
+	}
+	a := fakeAnalysis(time.Date(2021, 10, 13, 10, 0, 0, 0, time.UTC))
+	a.SetIgnoreRules(rules)
+
+	entry, err := store.Save(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	analysis, _, err := store.Get(entry.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, rules, analysis.IgnoreRules())
+}
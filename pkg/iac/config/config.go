@@ -6,6 +6,8 @@ type SupplierConfig struct {
 	Key     string
 	Backend string
 	Path    string
+	// Account the state is bound to, empty when it can match resources of any account
+	Account string
 }
 
 func (c *SupplierConfig) String() string {
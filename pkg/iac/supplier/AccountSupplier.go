@@ -0,0 +1,34 @@
+package supplier
+
+import (
+	"github.com/snyk/driftctl/pkg/resource"
+)
+
+// AccountSupplier binds every resource of a supplier to a cloud account,
+// so that states of distinct accounts are only matched against their own account
+type AccountSupplier struct {
+	account  string
+	supplier resource.IaCSupplier
+}
+
+func NewAccountSupplier(account string, supplier resource.IaCSupplier) *AccountSupplier {
+	return &AccountSupplier{
+		account:  account,
+		supplier: supplier,
+	}
+}
+
+func (s *AccountSupplier) SourceCount() uint {
+	return s.supplier.SourceCount()
+}
+
+func (s *AccountSupplier) Resources() ([]*resource.Resource, error) {
+	resources, err := s.supplier.Resources()
+	if err != nil {
+		return nil, err
+	}
+	for _, res := range resources {
+		res.Account = s.account
+	}
+	return resources, nil
+}
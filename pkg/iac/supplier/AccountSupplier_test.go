@@ -0,0 +1,37 @@
+package supplier
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/snyk/driftctl/pkg/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountSupplier_Resources(t *testing.T) {
+	sup := &resource.MockIaCSupplier{}
+	sup.On("Resources").Return([]*resource.Resource{
+		{Id: "vpc-1", Type: "aws_vpc"},
+		{Id: "vpc-2", Type: "aws_vpc"},
+	}, nil)
+	sup.On("SourceCount").Return(uint(2))
+
+	s := NewAccountSupplier("111111111111", sup)
+	got, err := s.Resources()
+	assert.NoError(t, err)
+	assert.Equal(t, []*resource.Resource{
+		{Id: "vpc-1", Type: "aws_vpc", Account: "111111111111"},
+		{Id: "vpc-2", Type: "aws_vpc", Account: "111111111111"},
+	}, got)
+	assert.Equal(t, uint(2), s.SourceCount())
+}
+
+func TestAccountSupplier_ResourcesError(t *testing.T) {
+	readErr := errors.New("unable to read state")
+	sup := &resource.MockIaCSupplier{}
+	sup.On("Resources").Return(nil, readErr)
+
+	got, err := NewAccountSupplier("111111111111", sup).Resources()
+	assert.Equal(t, readErr, err)
+	assert.Nil(t, got)
+}
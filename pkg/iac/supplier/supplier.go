@@ -13,6 +13,8 @@ import (
 
 	"github.com/snyk/driftctl/pkg/iac/config"
 
+	"github.com/snyk/driftctl/pkg/iac/terraform/configuration"
+	"github.com/snyk/driftctl/pkg/iac/terraform/plan"
 	"github.com/snyk/driftctl/pkg/iac/terraform/state"
 
 	"github.com/snyk/driftctl/pkg/resource"
@@ -20,6 +22,8 @@ import (
 
 var supportedSuppliers = []string{
 	state.TerraformStateReaderSupplier,
+	plan.TerraformPlanReaderSupplier,
+	configuration.TerraformConfigReaderSupplier,
 }
 
 func IsSupplierSupported(supplierKey string) bool {
@@ -52,6 +56,10 @@ func GetIACSupplier(configs []config.SupplierConfig,
 		switch config.Key {
 		case state.TerraformStateReaderSupplier:
 			supplier, err = state.NewReader(config, library, backendOpts, progress, alerter, deserializer, filter)
+		case plan.TerraformPlanReaderSupplier:
+			supplier, err = plan.NewReader(config, library, progress, deserializer, filter)
+		case configuration.TerraformConfigReaderSupplier:
+			supplier, err = configuration.NewReader(config, library, progress, deserializer, filter)
 		default:
 			return nil, errors.Errorf("Unsupported supplier '%s'", config.Key)
 		}
@@ -60,10 +68,15 @@ func GetIACSupplier(configs []config.SupplierConfig,
 			return nil, err
 		}
 
+		if config.Account != "" {
+			supplier = NewAccountSupplier(config.Account, supplier)
+		}
+
 		logrus.WithFields(logrus.Fields{
 			"supplier": config.Key,
 			"backend":  config.Backend,
 			"path":     config.Path,
+			"account":  config.Account,
 		}).Debug("Found IAC supplier")
 
 		chainSupplier.AddSupplier(supplier)
@@ -75,11 +88,19 @@ func GetSupportedSuppliers() []string {
 	return supportedSuppliers
 }
 
+// IsBackendSupported tells whether the supplier can read from a remote backend,
+// only states can be fetched remotely
+func IsBackendSupported(supplierKey string) bool {
+	return supplierKey == state.TerraformStateReaderSupplier
+}
+
 func GetSupportedSchemes() []string {
-	schemes := []string{
-		"tfstate://",
-	}
+	schemes := make([]string, 0)
 	for _, supplier := range supportedSuppliers {
+		schemes = append(schemes, fmt.Sprintf("%s://", supplier))
+		if !IsBackendSupported(supplier) {
+			continue
+		}
 		for _, backend := range backend.GetSupportedBackends() {
 			schemes = append(schemes, fmt.Sprintf("%s+%s://", supplier, backend))
 		}
@@ -83,13 +83,39 @@ func TestGetIACSupplier(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name: "test valid states mixed with configuration",
+			args: args{
+				config: []config.SupplierConfig{
+					{Key: "tfstate", Backend: "", Path: "terraform.tfstate"},
+					{Key: "tfconfig", Backend: "", Path: "."},
+				},
+				options: &backend.Options{
+					Headers: map[string]string{},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "test configuration from a backend",
+			args: args{
+				config: []config.SupplierConfig{
+					{Key: "tfconfig", Backend: "s3", Path: "bucket/module"},
+				},
+				options: &backend.Options{
+					Headers: map[string]string{},
+				},
+			},
+			wantErr: fmt.Errorf("Terraform configuration can only be read from local files, backend 's3' is not supported"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			progress := &output.MockProgress{}
 			progress.On("Start").Return().Times(1)
 
-			repo := resource.InitFakeSchemaRepository("aws", "3.19.0")
+			// Suppliers are only built, resources are never deserialized
+			repo := resource.InitFakeSchemaRepository("", "")
 			factory := terraform.NewTerraformResourceFactory(repo)
 			alerter := alerter.NewAlerter()
 
@@ -114,6 +140,8 @@ func TestGetSupportedSchemes(t *testing.T) {
 		"tfstate+https://",
 		"tfstate+tfcloud://",
 		"tfstate+gs://",
+		"tfplan://",
+		"tfconfig://",
 	}
 
 	if got := GetSupportedSchemes(); !reflect.DeepEqual(got, want) {
@@ -0,0 +1,125 @@
+package configuration
+
+import (
+	"os"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/dynblock"
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/hashicorp/terraform/configs"
+	"github.com/hashicorp/terraform/lang"
+	"github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// evaluator resolves expressions of a single module.
+// Everything that can only be known after apply (attributes of other resources,
+// data sources, module outputs, impure functions...) evaluates to an unknown value.
+type evaluator struct {
+	ctx *hcl.EvalContext
+}
+
+func newEvaluator(module *configs.Module, rootDir string, variables map[string]cty.Value) *evaluator {
+	cwd, _ := os.Getwd()
+	e := &evaluator{
+		ctx: &hcl.EvalContext{
+			Variables: map[string]cty.Value{
+				"var": cty.ObjectVal(moduleVariables(module, variables)),
+				"path": cty.ObjectVal(map[string]cty.Value{
+					"module": cty.StringVal(module.SourceDir),
+					"root":   cty.StringVal(rootDir),
+					"cwd":    cty.StringVal(cwd),
+				}),
+				"terraform": cty.ObjectVal(map[string]cty.Value{
+					"workspace": cty.StringVal("default"),
+				}),
+			},
+			Functions: (&lang.Scope{BaseDir: module.SourceDir, PureOnly: true}).Functions(),
+		},
+	}
+	e.resolveLocals(module.Locals)
+	return e
+}
+
+// moduleVariables gives a value to every variable declared in the module,
+// values that are neither given nor defaulted are unknown
+func moduleVariables(module *configs.Module, values map[string]cty.Value) map[string]cty.Value {
+	variables := make(map[string]cty.Value, len(module.Variables))
+	for name, variable := range module.Variables {
+		val, exists := values[name]
+		if !exists {
+			val = variable.Default
+		}
+		if val == cty.NilVal {
+			variables[name] = cty.DynamicVal
+			continue
+		}
+		if variable.Type != cty.NilType && variable.Type != cty.DynamicPseudoType {
+			converted, err := convert.Convert(val, variable.Type)
+			if err != nil {
+				logrus.WithFields(logrus.Fields{
+					"variable": name,
+					"module":   module.SourceDir,
+				}).Warnf("Invalid value for variable: %s", err)
+				converted = cty.UnknownVal(variable.Type)
+			}
+			val = converted
+		}
+		variables[name] = val
+	}
+	return variables
+}
+
+func (e *evaluator) resolveLocals(decls map[string]*configs.Local) {
+	locals := make(map[string]cty.Value, len(decls))
+	for name := range decls {
+		locals[name] = cty.DynamicVal
+	}
+	e.ctx.Variables["local"] = cty.ObjectVal(locals)
+
+	// Locals may reference each other, evaluate them as many times as there are locals
+	// so that each one had a chance to see the values of its dependencies
+	for i := 0; i < len(decls); i++ {
+		for name, decl := range decls {
+			val, diags := e.evaluate(decl.Expr, e.ctx)
+			if diags.HasErrors() {
+				val = cty.DynamicVal
+			}
+			locals[name] = val
+		}
+		e.ctx.Variables["local"] = cty.ObjectVal(locals)
+	}
+}
+
+func (e *evaluator) evaluate(expr hcl.Expression, ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+	return expr.Value(withUnknownReferences(ctx, expr.Variables()))
+}
+
+func (e *evaluator) decode(body hcl.Body, spec hcldec.Spec, ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+	ctx = withUnknownReferences(ctx, dynblock.VariablesHCLDec(body, spec))
+	return hcldec.Decode(dynblock.Expand(body, ctx), spec, ctx)
+}
+
+// withUnknownReferences returns a child context where every referenced
+// object that is not part of the given context is unknown
+func withUnknownReferences(ctx *hcl.EvalContext, traversals []hcl.Traversal) *hcl.EvalContext {
+	child := ctx.NewChild()
+	child.Variables = make(map[string]cty.Value)
+	for _, traversal := range traversals {
+		root := traversal.RootName()
+		if !isDefined(ctx, root) {
+			child.Variables[root] = cty.DynamicVal
+		}
+	}
+	return child
+}
+
+func isDefined(ctx *hcl.EvalContext, name string) bool {
+	for ; ctx != nil; ctx = ctx.Parent() {
+		if _, exists := ctx.Variables[name]; exists {
+			return true
+		}
+	}
+	return false
+}
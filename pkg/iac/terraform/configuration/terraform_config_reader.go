@@ -0,0 +1,303 @@
+package configuration
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform/configs"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/gocty"
+
+	"github.com/snyk/driftctl/pkg/filter"
+	"github.com/snyk/driftctl/pkg/iac/config"
+	"github.com/snyk/driftctl/pkg/output"
+	"github.com/snyk/driftctl/pkg/resource"
+	"github.com/snyk/driftctl/pkg/terraform"
+)
+
+const TerraformConfigReaderSupplier = "tfconfig"
+
+type instance struct {
+	name string
+	ctx  *hcl.EvalContext
+}
+
+// TerraformConfigReader reads resources declared in Terraform configuration files.
+// Resources whose identifier is only known after apply get an empty identifier
+// and are matched against remote resources using their identifying attributes.
+type TerraformConfigReader struct {
+	library      *terraform.ProviderLibrary
+	config       config.SupplierConfig
+	deserializer *resource.Deserializer
+	progress     output.Progress
+	filter       filter.Filter
+}
+
+func NewReader(config config.SupplierConfig, library *terraform.ProviderLibrary, progress output.Progress, deserializer *resource.Deserializer, filter filter.Filter) (*TerraformConfigReader, error) {
+	if config.Backend != "" {
+		return nil, errors.Errorf("Terraform configuration can only be read from local files, backend '%s' is not supported", config.Backend)
+	}
+	return &TerraformConfigReader{
+		library:      library,
+		config:       config,
+		deserializer: deserializer,
+		progress:     progress,
+		filter:       filter,
+	}, nil
+}
+
+func (r *TerraformConfigReader) SourceCount() uint {
+	return 1
+}
+
+func (r *TerraformConfigReader) Resources() ([]*resource.Resource, error) {
+	logrus.WithFields(logrus.Fields{
+		"path": r.config.Path,
+	}).Debug("Reading resources from Terraform configuration")
+	r.progress.Inc()
+
+	parser := configs.NewParser(afero.NewOsFs())
+	if !parser.IsConfigDir(r.config.Path) {
+		return nil, errors.Errorf("%s is not a directory containing Terraform configuration files", r.config.String())
+	}
+
+	module, diags := parser.LoadConfigDir(r.config.Path)
+	if diags.HasErrors() {
+		return nil, errors.Wrap(diags, r.config.String())
+	}
+	variables, err := rootVariables(parser, module)
+	if err != nil {
+		return nil, errors.Wrap(err, r.config.String())
+	}
+
+	results, err := r.readModule(parser, module, "", variables)
+	return results, errors.Wrap(err, r.config.String())
+}
+
+func (r *TerraformConfigReader) readModule(parser *configs.Parser, module *configs.Module, moduleName string, variables map[string]cty.Value) ([]*resource.Resource, error) {
+	eval := newEvaluator(module, r.config.Path, variables)
+
+	managedResources := make([]*configs.Resource, 0, len(module.ManagedResources))
+	for _, res := range module.ManagedResources {
+		managedResources = append(managedResources, res)
+	}
+	sort.Slice(managedResources, func(i, j int) bool {
+		return managedResources[i].Addr().String() < managedResources[j].Addr().String()
+	})
+
+	results := make([]*resource.Resource, 0)
+	for _, res := range managedResources {
+		resources, err := r.readResource(res, moduleName, eval)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"module": moduleName,
+				"name":   res.Name,
+				"type":   res.Type,
+			}).Warnf("Could not read from configuration: %s", err)
+			continue
+		}
+		results = append(results, resources...)
+	}
+
+	calls := make([]*configs.ModuleCall, 0, len(module.ModuleCalls))
+	for _, call := range module.ModuleCalls {
+		calls = append(calls, call)
+	}
+	sort.Slice(calls, func(i, j int) bool {
+		return calls[i].Name < calls[j].Name
+	})
+
+	for _, call := range calls {
+		childName := fmt.Sprintf("module.%s", call.Name)
+		if moduleName != "" {
+			childName = fmt.Sprintf("%s.%s", moduleName, childName)
+		}
+
+		if !isLocalSource(call.SourceAddr) {
+			logrus.WithFields(logrus.Fields{
+				"module": childName,
+				"source": call.SourceAddr,
+			}).Debug("Skipping module that is not stored locally")
+			continue
+		}
+		if call.Count != nil || call.ForEach != nil {
+			logrus.WithFields(logrus.Fields{
+				"module": childName,
+			}).Warn("Skipping module using count or for_each, their expansion is not supported")
+			continue
+		}
+
+		child, diags := parser.LoadConfigDir(filepath.Join(module.SourceDir, call.SourceAddr))
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		args, diags := call.Config.JustAttributes()
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		childVariables := make(map[string]cty.Value, len(args))
+		for name, arg := range args {
+			val, diags := eval.evaluate(arg.Expr, eval.ctx)
+			if diags.HasErrors() {
+				val = cty.DynamicVal
+			}
+			childVariables[name] = val
+		}
+
+		resources, err := r.readModule(parser, child, childName, childVariables)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, resources...)
+	}
+
+	return results, nil
+}
+
+func (r *TerraformConfigReader) readResource(res *configs.Resource, moduleName string, eval *evaluator) ([]*resource.Resource, error) {
+	if !resource.IsResourceTypeSupported(res.Type) {
+		logrus.WithFields(logrus.Fields{
+			"name": res.Name,
+			"type": res.Type,
+		}).Debug("Ignored unsupported resource from configuration")
+		return nil, nil
+	}
+
+	if r.filter != nil && r.filter.IsTypeIgnored(resource.ResourceType(res.Type)) {
+		logrus.WithFields(logrus.Fields{
+			"name": res.Name,
+			"type": res.Type,
+		}).Debug("Ignored resource from configuration since it is ignored in filter")
+		return nil, nil
+	}
+
+	provider := r.library.Provider(res.Provider.Type)
+	if provider == nil {
+		logrus.WithFields(logrus.Fields{
+			"providerKey": res.Provider.Type,
+		}).Debug("Unsupported provider found in configuration")
+		return nil, nil
+	}
+	schema, exists := provider.Schema()[res.Type]
+	if !exists || schema.Block == nil {
+		return nil, errors.Errorf("no schema found for %s", res.Type)
+	}
+
+	instances, err := expand(res, eval)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*resource.Resource, 0, len(instances))
+	for _, inst := range instances {
+		val, diags := eval.decode(res.Config, schema.Block.DecoderSpec(), inst.ctx)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		// Sensitive values are marked, unmark them as we only need their value
+		val, _ = val.UnmarkDeep()
+		val = cty.UnknownAsNull(val)
+
+		attrs := val.AsValueMap()
+		if attrs == nil {
+			attrs = make(map[string]cty.Value)
+		}
+		attrs["id"] = cty.StringVal(identifier(res.Type, val))
+
+		decoded, err := r.deserializer.DeserializeOne(res.Type, cty.ObjectVal(attrs))
+		if err != nil {
+			return nil, err
+		}
+		decoded.Source = resource.NewTerraformConfigSource(r.config.String(), moduleName, inst.name)
+		results = append(results, decoded)
+	}
+
+	return results, nil
+}
+
+// expand computes instances of a resource according to its count or for_each meta-argument
+func expand(res *configs.Resource, eval *evaluator) ([]instance, error) {
+	if res.Count != nil {
+		val, diags := eval.evaluate(res.Count, eval.ctx)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		if !val.IsWhollyKnown() || val.IsNull() {
+			return nil, errors.New("count is only known after apply")
+		}
+		var count int
+		if err := gocty.FromCtyValue(val, &count); err != nil {
+			return nil, errors.Wrap(err, "invalid count")
+		}
+		instances := make([]instance, 0, count)
+		for i := 0; i < count; i++ {
+			ctx := eval.ctx.NewChild()
+			ctx.Variables = map[string]cty.Value{
+				"count": cty.ObjectVal(map[string]cty.Value{
+					"index": cty.NumberIntVal(int64(i)),
+				}),
+			}
+			instances = append(instances, instance{fmt.Sprintf("%s[%d]", res.Name, i), ctx})
+		}
+		return instances, nil
+	}
+
+	if res.ForEach != nil {
+		val, diags := eval.evaluate(res.ForEach, eval.ctx)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		if !val.IsWhollyKnown() || val.IsNull() {
+			return nil, errors.New("for_each is only known after apply")
+		}
+		if !val.Type().IsMapType() && !val.Type().IsObjectType() && !val.Type().IsSetType() {
+			return nil, errors.New("for_each must be a map or a set of strings")
+		}
+		instances := make([]instance, 0, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			key, value := it.Element()
+			if val.Type().IsSetType() {
+				key = value
+			}
+			key, err := convert.Convert(key, cty.String)
+			if err != nil {
+				return nil, errors.New("for_each must be a map or a set of strings")
+			}
+			ctx := eval.ctx.NewChild()
+			ctx.Variables = map[string]cty.Value{
+				"each": cty.ObjectVal(map[string]cty.Value{
+					"key":   key,
+					"value": value,
+				}),
+			}
+			instances = append(instances, instance{fmt.Sprintf("%s[%q]", res.Name, key.AsString()), ctx})
+		}
+		return instances, nil
+	}
+
+	return []instance{{res.Name, eval.ctx}}, nil
+}
+
+// identifier returns the identifier of a resource when it can be known before apply, an empty string otherwise
+func identifier(ty string, val cty.Value) string {
+	attributes, exists := resource.GetIdentifyingAttributes(ty)
+	if !exists || attributes.Identifier == "" || !val.Type().HasAttribute(attributes.Identifier) {
+		return ""
+	}
+	id := val.GetAttr(attributes.Identifier)
+	if id.IsNull() || !id.Type().Equals(cty.String) {
+		return ""
+	}
+	return id.AsString()
+}
+
+func isLocalSource(source string) bool {
+	return strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../")
+}
@@ -0,0 +1,144 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/snyk/driftctl/pkg/iac/config"
+	"github.com/snyk/driftctl/pkg/output"
+	"github.com/snyk/driftctl/pkg/resource"
+	"github.com/snyk/driftctl/pkg/terraform"
+	testresource "github.com/snyk/driftctl/test/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func newGithubReader(t *testing.T, path string) *TerraformConfigReader {
+	library := testresource.InitFakeProviderLibrary(terraform.GITHUB, "4.4.0")
+	repo := testresource.InitFakeSchemaRepository(terraform.GITHUB, "4.4.0")
+	factory := terraform.NewTerraformResourceFactory(repo)
+
+	progress := &output.MockProgress{}
+	progress.On("Inc").Return()
+
+	reader, err := NewReader(config.SupplierConfig{Key: TerraformConfigReaderSupplier, Path: path}, library, progress, resource.NewDeserializer(factory), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reader
+}
+
+func TestTerraformConfigReader_Resources(t *testing.T) {
+	t.Setenv("TF_VAR_visibility", "public")
+
+	reader := newGithubReader(t, "testdata/github")
+	got, err := reader.Resources()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), reader.SourceCount())
+
+	type expectedResource struct {
+		source string
+		id     string
+		attrs  map[string]interface{}
+	}
+	expected := []expectedResource{
+		{
+			source: "github_membership.member",
+			id:     "",
+			attrs:  map[string]interface{}{"role": "admin"},
+		},
+		{
+			source: "github_repository.counted[0]",
+			id:     "driftctl-counted-0",
+			attrs:  map[string]interface{}{"name": "driftctl-counted-0"},
+		},
+		{
+			source: "github_repository.counted[1]",
+			id:     "driftctl-counted-1",
+			attrs:  map[string]interface{}{"name": "driftctl-counted-1"},
+		},
+		{
+			source: "github_repository.main",
+			id:     "driftctl-repository",
+			attrs:  map[string]interface{}{"name": "driftctl-repository", "visibility": "public"},
+		},
+		{
+			source: "github_team.team[\"admins\"]",
+			id:     "",
+			attrs:  map[string]interface{}{"name": "admins", "description": "ADMINS"},
+		},
+		{
+			source: "github_team.team[\"developers\"]",
+			id:     "",
+			attrs:  map[string]interface{}{"name": "developers", "description": "DEVELOPERS"},
+		},
+		{
+			source: "module.membership.github_team_membership.member",
+			id:     "",
+			attrs:  map[string]interface{}{"team_id": "4567", "username": "driftctl-bot", "role": "maintainer"},
+		},
+	}
+
+	if !assert.Len(t, got, len(expected)) {
+		return
+	}
+	for i, res := range got {
+		assert.Equal(t, expected[i].source, res.SourceString())
+		assert.Equal(t, "tfconfig://testdata/github", res.Src().Source())
+		assert.Equal(t, expected[i].id, res.ResourceId())
+		for key, value := range expected[i].attrs {
+			assert.Equal(t, value, (*res.Attributes())[key], "%s of %s", key, expected[i].source)
+		}
+	}
+
+	// Attributes only known after apply are left unset
+	_, exist := (*got[0].Attributes())["username"]
+	assert.False(t, exist)
+	_, exist = (*got[3].Attributes())["description"]
+	assert.False(t, exist)
+}
+
+func TestTerraformConfigReader_NotAConfigDirectory(t *testing.T) {
+	reader := newGithubReader(t, "testdata/missing")
+	got, err := reader.Resources()
+	assert.Nil(t, got)
+	assert.EqualError(t, err, "tfconfig://testdata/missing is not a directory containing Terraform configuration files")
+}
+
+func TestNewReader_WithBackend(t *testing.T) {
+	reader, err := NewReader(config.SupplierConfig{Key: TerraformConfigReaderSupplier, Backend: "s3", Path: "bucket/module"}, terraform.NewProviderLibrary(), nil, nil, nil)
+	assert.Nil(t, reader)
+	assert.EqualError(t, err, "Terraform configuration can only be read from local files, backend 's3' is not supported")
+}
+
+func Test_identifier(t *testing.T) {
+	tests := []struct {
+		name string
+		ty   string
+		val  cty.Value
+		want string
+	}{
+		{
+			name: "identifier attribute is known",
+			ty:   "aws_s3_bucket",
+			val:  cty.ObjectVal(map[string]cty.Value{"bucket": cty.StringVal("my-bucket")}),
+			want: "my-bucket",
+		},
+		{
+			name: "identifier attribute is only known after apply",
+			ty:   "aws_s3_bucket",
+			val:  cty.ObjectVal(map[string]cty.Value{"bucket": cty.NullVal(cty.String)}),
+			want: "",
+		},
+		{
+			name: "identifier is always computed",
+			ty:   "aws_sqs_queue",
+			val:  cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("my-queue")}),
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, identifier(tt.ty, tt.val))
+		})
+	}
+}
@@ -0,0 +1,56 @@
+variable "prefix" {
+  type    = string
+  default = "default"
+}
+
+variable "visibility" {
+  type = string
+}
+
+variable "teams" {
+  type    = list(string)
+  default = ["admins", "developers"]
+}
+
+locals {
+  name = "${var.prefix}-repository"
+}
+
+resource "github_repository" "main" {
+  name        = local.name
+  visibility  = var.visibility
+  description = "Managed by ${github_team.team["admins"].name}"
+}
+
+resource "github_repository" "counted" {
+  count = 2
+  name  = "${var.prefix}-counted-${count.index}"
+}
+
+resource "github_team" "team" {
+  for_each    = toset(var.teams)
+  name        = each.key
+  description = upper(each.value)
+}
+
+resource "github_membership" "member" {
+  username = data.github_user.current.login
+  role     = "admin"
+}
+
+resource "github_repository" "unknown_count" {
+  count = length(data.github_repositories.all.names)
+  name  = "unknown"
+}
+
+resource "null_resource" "unsupported" {}
+
+module "membership" {
+  source = "./modules/membership"
+  team   = "4567"
+}
+
+module "registry" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "3.0.0"
+}
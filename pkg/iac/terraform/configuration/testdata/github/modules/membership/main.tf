@@ -0,0 +1,9 @@
+variable "team" {
+  type = string
+}
+
+resource "github_team_membership" "member" {
+  team_id  = var.team
+  username = "driftctl-bot"
+  role     = "maintainer"
+}
@@ -0,0 +1,76 @@
+package configuration
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform/configs"
+	"github.com/pkg/errors"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const varEnvPrefix = "TF_VAR_"
+
+// rootVariables reads values of the root module variables with the same precedence as Terraform:
+// TF_VAR_ environment variables, then terraform.tfvars files, then *.auto.tfvars files in lexical order
+func rootVariables(parser *configs.Parser, module *configs.Module) (map[string]cty.Value, error) {
+	values := make(map[string]cty.Value)
+
+	for _, env := range os.Environ() {
+		if !strings.HasPrefix(env, varEnvPrefix) {
+			continue
+		}
+		nameValue := strings.SplitN(strings.TrimPrefix(env, varEnvPrefix), "=", 2)
+		variable, declared := module.Variables[nameValue[0]]
+		if !declared || len(nameValue) != 2 {
+			continue
+		}
+		val, diags := variable.ParsingMode.Parse(variable.Name, nameValue[1])
+		if diags.HasErrors() {
+			return nil, errors.Wrapf(diags, "unable to parse environment variable %s%s", varEnvPrefix, variable.Name)
+		}
+		values[variable.Name] = val
+	}
+
+	files, err := varFiles(module.SourceDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		fileValues, diags := parser.LoadValuesFile(file)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		for name, val := range fileValues {
+			values[name] = val
+		}
+	}
+
+	return values, nil
+}
+
+func varFiles(dir string) ([]string, error) {
+	files := make([]string, 0)
+	for _, name := range []string{"terraform.tfvars", "terraform.tfvars.json"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			files = append(files, filepath.Join(dir, name))
+		}
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if strings.HasSuffix(entry.Name(), ".auto.tfvars") || strings.HasSuffix(entry.Name(), ".auto.tfvars.json") {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,263 @@
+package imports
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/snyk/driftctl/pkg/resource"
+)
+
+const (
+	// DefaultFileName is the name of the file generated when resources are not grouped
+	DefaultFileName = "imports.tf"
+	untaggedGroup   = "_untagged"
+)
+
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+type Options struct {
+	// Also generate resource blocks filled with attributes of resources
+	WithResources bool
+	// Write resources of each type in a dedicated file
+	GroupByType bool
+	// Write resources in a file per value of the given tag
+	GroupByTag string
+}
+
+type File struct {
+	Name    string
+	Content []byte
+}
+
+// Generate returns Terraform import blocks for the given resources, as well as their resource block when asked to.
+// Resources are split in several files according to the grouping options.
+func Generate(resources []*resource.Resource, opts Options) []File {
+	resources = resource.Sort(resources)
+	names := newNamer()
+
+	groups := make(map[string]*hclwrite.File)
+	for _, res := range resources {
+		id, ok := importId(res)
+		if !ok {
+			logrus.WithFields(logrus.Fields{
+				"id":   res.ResourceId(),
+				"type": res.ResourceType(),
+			}).Warn("Skipping resource, its import identifier cannot be built from its attributes")
+			continue
+		}
+
+		group := groupName(res, opts)
+		file, exists := groups[group]
+		if !exists {
+			file = hclwrite.NewEmptyFile()
+			groups[group] = file
+		} else {
+			file.Body().AppendNewline()
+		}
+
+		name := names.name(res)
+		importBlock := file.Body().AppendNewBlock("import", nil)
+		importBlock.Body().SetAttributeTraversal("to", hcl.Traversal{
+			hcl.TraverseRoot{Name: res.ResourceType()},
+			hcl.TraverseAttr{Name: name},
+		})
+		importBlock.Body().SetAttributeValue("id", cty.StringVal(id))
+
+		if opts.WithResources {
+			file.Body().AppendNewline()
+			resourceBlock := file.Body().AppendNewBlock("resource", []string{res.ResourceType(), name})
+			writeAttributes(resourceBlock.Body(), res.Attributes(), res.Schema(), nil)
+		}
+	}
+
+	files := make([]File, 0, len(groups))
+	for group, file := range groups {
+		files = append(files, File{
+			Name:    fmt.Sprintf("%s.tf", group),
+			Content: hclwrite.Format(file.Bytes()),
+		})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+	return files
+}
+
+func groupName(res *resource.Resource, opts Options) string {
+	if opts.GroupByType {
+		return res.ResourceType()
+	}
+	if opts.GroupByTag != "" {
+		value := tagValue(res, opts.GroupByTag)
+		if value == "" {
+			return untaggedGroup
+		}
+		return invalidNameChars.ReplaceAllString(value, "_")
+	}
+	return strings.TrimSuffix(DefaultFileName, ".tf")
+}
+
+// tagValue looks for the given tag in tags, then in labels as used by Google resources
+func tagValue(res *resource.Resource, key string) string {
+	if res.Attributes() == nil {
+		return ""
+	}
+	for _, field := range []string{"tags", "labels"} {
+		tags, ok := (*res.Attributes())[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if value, ok := tags[key].(string); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// writeAttributes writes attributes that are set, nested lists of objects are written as blocks unless the schema
+// of the resource tells they are attributes. Attributes only computed by the provider are skipped when the schema
+// of the resource is known.
+func writeAttributes(body *hclwrite.Body, attrs *resource.Attributes, schema *resource.Schema, path []string) {
+	if attrs == nil {
+		return
+	}
+
+	keys := make([]string, 0, len(*attrs))
+	for key := range *attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if len(path) == 0 && key == "id" {
+			continue
+		}
+		attrPath := append(append([]string{}, path...), key)
+		if isComputedOnly(schema, attrPath) {
+			continue
+		}
+
+		value := (*attrs)[key]
+		if blocks, ok := asBlocks(value); ok && !isAttribute(schema, attrPath) {
+			for _, block := range blocks {
+				nested := resource.Attributes(block)
+				writeAttributes(body.AppendNewBlock(key, nil).Body(), &nested, schema, attrPath)
+			}
+			continue
+		}
+
+		val := toCtyValue(value)
+		if val == cty.NilVal {
+			continue
+		}
+		body.SetAttributeValue(key, val)
+	}
+}
+
+func isComputedOnly(schema *resource.Schema, path []string) bool {
+	if schema == nil {
+		return false
+	}
+	attr, exists := schema.Attributes[strings.Join(path, ".")]
+	return exists && attr.ConfigSchema.Computed && !attr.ConfigSchema.Optional
+}
+
+// isAttribute tells whether the schema of the resource declares an attribute at path, nested blocks are not attributes
+func isAttribute(schema *resource.Schema, path []string) bool {
+	if schema == nil {
+		return false
+	}
+	_, exists := schema.Attributes[strings.Join(path, ".")]
+	return exists
+}
+
+// asBlocks tells whether value is a non empty list of objects
+func asBlocks(value interface{}) ([]map[string]interface{}, bool) {
+	list, ok := value.([]interface{})
+	if !ok || len(list) == 0 {
+		return nil, false
+	}
+	blocks := make([]map[string]interface{}, 0, len(list))
+	for _, elem := range list {
+		block, ok := elem.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, true
+}
+
+// toCtyValue converts attributes read from the provider, empty values are returned as cty.NilVal
+func toCtyValue(value interface{}) cty.Value {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return cty.NilVal
+		}
+		return cty.StringVal(v)
+	case bool:
+		return cty.BoolVal(v)
+	case float64:
+		return cty.NumberFloatVal(v)
+	case int:
+		return cty.NumberIntVal(int64(v))
+	case []interface{}:
+		values := make([]cty.Value, 0, len(v))
+		for _, elem := range v {
+			if val := toCtyValue(elem); val != cty.NilVal {
+				values = append(values, val)
+			}
+		}
+		if len(values) == 0 {
+			return cty.NilVal
+		}
+		return cty.TupleVal(values)
+	case map[string]interface{}:
+		values := make(map[string]cty.Value, len(v))
+		for key, elem := range v {
+			if val := toCtyValue(elem); val != cty.NilVal {
+				values[key] = val
+			}
+		}
+		if len(values) == 0 {
+			return cty.NilVal
+		}
+		return cty.ObjectVal(values)
+	}
+	return cty.NilVal
+}
+
+// namer gives each resource a unique name built from its identifier
+type namer struct {
+	used map[string]bool
+}
+
+func newNamer() *namer {
+	return &namer{used: make(map[string]bool)}
+}
+
+func (n *namer) name(res *resource.Resource) string {
+	base := strings.Trim(invalidNameChars.ReplaceAllString(res.ResourceId(), "_"), "_")
+	if base == "" {
+		base = "imported"
+	}
+	// Names must start with a letter or an underscore
+	if first := base[0]; !(first >= 'a' && first <= 'z') && !(first >= 'A' && first <= 'Z') {
+		base = "_" + base
+	}
+
+	name := base
+	for i := 2; n.used[res.ResourceType()+"."+name]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	n.used[res.ResourceType()+"."+name] = true
+	return name
+}
@@ -0,0 +1,266 @@
+package imports
+
+import (
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"github.com/hashicorp/terraform/configs/configschema"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/snyk/driftctl/pkg/resource"
+	"github.com/snyk/driftctl/test/goldenfile"
+)
+
+func fakeResources() []*resource.Resource {
+	return []*resource.Resource{
+		{
+			Id:   "my-bucket",
+			Type: "aws_s3_bucket",
+			Attrs: &resource.Attributes{
+				"id":            "my-bucket",
+				"bucket":        "my-bucket",
+				"arn":           "arn:aws:s3:::my-bucket",
+				"acl":           "private",
+				"force_destroy": false,
+				"policy":        "",
+				"tags": map[string]interface{}{
+					"Env":                      "prod",
+					"aws:cloudformation:stack": "stack",
+				},
+				"versioning": []interface{}{
+					map[string]interface{}{
+						"enabled":    true,
+						"mfa_delete": false,
+					},
+				},
+				"grant": []interface{}{},
+			},
+			Sch: &resource.Schema{
+				Attributes: map[string]resource.AttributeSchema{
+					"arn":    {ConfigSchema: configschema.Attribute{Computed: true}},
+					"bucket": {ConfigSchema: configschema.Attribute{Optional: true, Computed: true}},
+				},
+			},
+		},
+		{
+			Id:   "driftctl",
+			Type: "aws_iam_user",
+			Attrs: &resource.Attributes{
+				"name":          "driftctl",
+				"path":          "/",
+				"force_destroy": false,
+				"tags": map[string]interface{}{
+					"Env": "dev",
+				},
+			},
+		},
+		{
+			Id:   "driftctl_assume_role:driftctl_policy.10",
+			Type: "aws_iam_role_policy",
+			Attrs: &resource.Attributes{
+				"name":   "driftctl_policy.10",
+				"role":   "driftctl_assume_role",
+				"policy": "{\"Version\":\"2012-10-17\"}",
+			},
+		},
+		{
+			Id:   "123456789012",
+			Type: "aws_iam_role_policy",
+		},
+		{
+			Id:   "sg-0123456789",
+			Type: "aws_security_group",
+			Attrs: &resource.Attributes{
+				"id":       "sg-0123456789",
+				"arn":      "arn:aws:ec2:us-east-1:123456789012:security-group/sg-0123456789",
+				"name":     "web",
+				"owner_id": "123456789012",
+				"ingress": []interface{}{
+					map[string]interface{}{
+						"cidr_blocks": []interface{}{"0.0.0.0/0"},
+						"from_port":   float64(443),
+						"to_port":     float64(443),
+						"protocol":    "tcp",
+					},
+				},
+			},
+			Sch: &resource.Schema{
+				Attributes: map[string]resource.AttributeSchema{
+					"arn":      {ConfigSchema: configschema.Attribute{Computed: true}},
+					"owner_id": {ConfigSchema: configschema.Attribute{Computed: true}},
+					"name":     {ConfigSchema: configschema.Attribute{Optional: true, Computed: true}},
+					// Set in attribute mode by the provider, it is written as a list of objects
+					"ingress": {ConfigSchema: configschema.Attribute{Optional: true, Computed: true}},
+				},
+			},
+		},
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        Options
+		goldenfiles map[string]string
+	}{
+		{
+			name: "import blocks only",
+			opts: Options{},
+			goldenfiles: map[string]string{
+				"imports.tf": "imports_only.tf",
+			},
+		},
+		{
+			name: "import and resource blocks",
+			opts: Options{WithResources: true},
+			goldenfiles: map[string]string{
+				"imports.tf": "imports_with_resources.tf",
+			},
+		},
+		{
+			name: "grouped by type",
+			opts: Options{GroupByType: true},
+			goldenfiles: map[string]string{
+				"aws_iam_role_policy.tf": "group_by_type_aws_iam_role_policy.tf",
+				"aws_iam_user.tf":        "group_by_type_aws_iam_user.tf",
+				"aws_s3_bucket.tf":       "group_by_type_aws_s3_bucket.tf",
+				"aws_security_group.tf":  "group_by_type_aws_security_group.tf",
+			},
+		},
+		{
+			name: "grouped by tag",
+			opts: Options{GroupByTag: "Env"},
+			goldenfiles: map[string]string{
+				"dev.tf":       "group_by_tag_dev.tf",
+				"prod.tf":      "group_by_tag_prod.tf",
+				"_untagged.tf": "group_by_tag_untagged.tf",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := Generate(fakeResources(), tt.opts)
+			assert.Len(t, files, len(tt.goldenfiles))
+
+			for _, file := range files {
+				golden, exists := tt.goldenfiles[file.Name]
+				if !assert.True(t, exists, "unexpected file %s", file.Name) {
+					continue
+				}
+				expectedFilePath := path.Join("./testdata/", golden)
+				if *goldenfile.Update == golden {
+					if err := ioutil.WriteFile(expectedFilePath, file.Content, 0600); err != nil {
+						t.Fatal(err)
+					}
+				}
+				expected, err := ioutil.ReadFile(expectedFilePath)
+				if err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t, string(expected), string(file.Content))
+			}
+		})
+	}
+}
+
+func TestGenerate_ImportIds(t *testing.T) {
+	resources := []*resource.Resource{
+		{
+			Id:   "driftctl_role-driftctl_policy",
+			Type: "aws_iam_role_policy_attachment",
+			Attrs: &resource.Attributes{
+				"role":       "driftctl_role",
+				"policy_arn": "arn:aws:iam::123456789012:policy/driftctl_policy",
+			},
+		},
+		{
+			Id:   "sgrule-3970541193",
+			Type: "aws_security_group_rule",
+			Attrs: &resource.Attributes{
+				"security_group_id": "sg-0123456789",
+				"type":              "ingress",
+				"protocol":          "tcp",
+				"from_port":         float64(443),
+				"to_port":           float64(443),
+				"cidr_blocks":       []interface{}{"10.0.0.0/16", "10.1.0.0/16"},
+				"ipv6_cidr_blocks":  []interface{}{"::/0"},
+			},
+		},
+		{
+			Id:   "sgrule-1707973622",
+			Type: "aws_security_group_rule",
+			Attrs: &resource.Attributes{
+				"security_group_id":        "sg-0123456789",
+				"type":                     "egress",
+				"protocol":                 "-1",
+				"from_port":                float64(0),
+				"to_port":                  float64(0),
+				"self":                     false,
+				"source_security_group_id": "sg-9876543210",
+			},
+		},
+		{
+			Id:   "r-rtb-0123456789179966490",
+			Type: "aws_route",
+			Attrs: &resource.Attributes{
+				"route_table_id":         "rtb-0123456789",
+				"destination_cidr_block": "0.0.0.0/0",
+			},
+		},
+		{
+			Id:   "rtbassoc-0123456789",
+			Type: "aws_route_table_association",
+			Attrs: &resource.Attributes{
+				"route_table_id": "rtb-0123456789",
+				"subnet_id":      "subnet-0123456789",
+			},
+		},
+		{
+			Id:   "rtbassoc-9876543210",
+			Type: "aws_route_table_association",
+			Attrs: &resource.Attributes{
+				"route_table_id": "rtb-0123456789",
+				"gateway_id":     "igw-0123456789",
+			},
+		},
+		// Attributes are not known without deep mode, these resources are skipped
+		{
+			Id:   "r-rtb-98765432101080289494",
+			Type: "aws_route",
+		},
+		{
+			Id:    "sgrule-2582518759",
+			Type:  "aws_security_group_rule",
+			Attrs: &resource.Attributes{},
+		},
+	}
+
+	files := Generate(resources, Options{})
+	if !assert.Len(t, files, 1) {
+		return
+	}
+
+	golden := "import_ids.tf"
+	expectedFilePath := path.Join("./testdata/", golden)
+	if *goldenfile.Update == golden {
+		if err := ioutil.WriteFile(expectedFilePath, files[0].Content, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := ioutil.ReadFile(expectedFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(expected), string(files[0].Content))
+}
+
+func Test_namer(t *testing.T) {
+	n := newNamer()
+	assert.Equal(t, "my-bucket", n.name(&resource.Resource{Id: "my-bucket", Type: "aws_s3_bucket"}))
+	assert.Equal(t, "my-bucket", n.name(&resource.Resource{Id: "my-bucket", Type: "aws_s3_bucket_policy"}))
+	assert.Equal(t, "my_bucket", n.name(&resource.Resource{Id: "my_bucket", Type: "aws_s3_bucket"}))
+	assert.Equal(t, "my_bucket_2", n.name(&resource.Resource{Id: "my.bucket", Type: "aws_s3_bucket"}))
+	assert.Equal(t, "_123456789012", n.name(&resource.Resource{Id: "123456789012", Type: "aws_iam_role_policy"}))
+	assert.Equal(t, "imported", n.name(&resource.Resource{Id: "/", Type: "aws_iam_role_policy"}))
+}
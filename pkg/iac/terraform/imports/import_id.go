@@ -0,0 +1,96 @@
+package imports
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/snyk/driftctl/pkg/resource"
+)
+
+// importIdBuilders build the identifier expected by terraform import for types whose driftctl identifier differs
+var importIdBuilders = map[string]func(attrs *resource.Attributes) (string, bool){
+	"aws_iam_role_policy_attachment": func(attrs *resource.Attributes) (string, bool) {
+		return joinAttributes(attrs, "/", "role", "policy_arn")
+	},
+	"aws_route": func(attrs *resource.Attributes) (string, bool) {
+		for _, destination := range []string{"destination_cidr_block", "destination_ipv6_cidr_block", "destination_prefix_list_id"} {
+			if id, ok := joinAttributes(attrs, "_", "route_table_id", destination); ok {
+				return id, true
+			}
+		}
+		return "", false
+	},
+	"aws_route_table_association": func(attrs *resource.Attributes) (string, bool) {
+		for _, target := range []string{"subnet_id", "gateway_id"} {
+			if id, ok := joinAttributes(attrs, "/", target, "route_table_id"); ok {
+				return id, true
+			}
+		}
+		return "", false
+	},
+	"aws_security_group_rule": securityGroupRuleImportId,
+}
+
+// importId returns the identifier to import the resource with, false is returned when attributes needed to build it
+// are not known
+func importId(res *resource.Resource) (string, bool) {
+	build, exists := importIdBuilders[res.ResourceType()]
+	if !exists {
+		return res.ResourceId(), true
+	}
+	if res.Attributes() == nil {
+		return "", false
+	}
+	return build(res.Attributes())
+}
+
+// securityGroupRuleImportId builds the SECURITYGROUPID_TYPE_PROTOCOL_FROMPORT_TOPORT_SOURCE identifier, the source
+// being every CIDR block or prefix list of the rule, its source security group or self
+func securityGroupRuleImportId(attrs *resource.Attributes) (string, bool) {
+	id, ok := joinAttributes(attrs, "_", "security_group_id", "type", "protocol")
+	if !ok {
+		return "", false
+	}
+	parts := []string{id}
+	for _, key := range []string{"from_port", "to_port"} {
+		port, ok := (*attrs)[key].(float64)
+		if !ok {
+			return "", false
+		}
+		parts = append(parts, fmt.Sprintf("%d", int(port)))
+	}
+
+	sources := make([]string, 0)
+	for _, key := range []string{"cidr_blocks", "ipv6_cidr_blocks", "prefix_list_ids"} {
+		values, _ := (*attrs)[key].([]interface{})
+		for _, value := range values {
+			if str, ok := value.(string); ok && str != "" {
+				sources = append(sources, str)
+			}
+		}
+	}
+	if self, _ := (*attrs)["self"].(bool); self {
+		sources = append(sources, "self")
+	}
+	if group, _ := (*attrs)["source_security_group_id"].(string); group != "" {
+		sources = append(sources, group)
+	}
+	if len(sources) == 0 {
+		return "", false
+	}
+
+	return strings.Join(append(parts, sources...), "_"), true
+}
+
+// joinAttributes joins string attributes with sep, false is returned when one of them is not set
+func joinAttributes(attrs *resource.Attributes, sep string, keys ...string) (string, bool) {
+	values := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value, _ := (*attrs)[key].(string)
+		if value == "" {
+			return "", false
+		}
+		values = append(values, value)
+	}
+	return strings.Join(values, sep), true
+}
@@ -0,0 +1,4 @@
+import {
+  to = aws_iam_user.driftctl
+  id = "driftctl"
+}
@@ -0,0 +1,14 @@
+import {
+  to = aws_iam_role_policy._123456789012
+  id = "123456789012"
+}
+
+import {
+  to = aws_iam_role_policy.driftctl_assume_role_driftctl_policy_10
+  id = "driftctl_assume_role:driftctl_policy.10"
+}
+
+import {
+  to = aws_security_group.sg-0123456789
+  id = "sg-0123456789"
+}
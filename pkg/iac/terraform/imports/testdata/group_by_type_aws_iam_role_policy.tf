@@ -0,0 +1,9 @@
+import {
+  to = aws_iam_role_policy._123456789012
+  id = "123456789012"
+}
+
+import {
+  to = aws_iam_role_policy.driftctl_assume_role_driftctl_policy_10
+  id = "driftctl_assume_role:driftctl_policy.10"
+}
@@ -0,0 +1,4 @@
+import {
+  to = aws_security_group.sg-0123456789
+  id = "sg-0123456789"
+}
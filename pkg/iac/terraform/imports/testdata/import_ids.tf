@@ -0,0 +1,29 @@
+import {
+  to = aws_iam_role_policy_attachment.driftctl_role-driftctl_policy
+  id = "driftctl_role/arn:aws:iam::123456789012:policy/driftctl_policy"
+}
+
+import {
+  to = aws_route.r-rtb-0123456789179966490
+  id = "rtb-0123456789_0.0.0.0/0"
+}
+
+import {
+  to = aws_route_table_association.rtbassoc-0123456789
+  id = "subnet-0123456789/rtb-0123456789"
+}
+
+import {
+  to = aws_route_table_association.rtbassoc-9876543210
+  id = "igw-0123456789/rtb-0123456789"
+}
+
+import {
+  to = aws_security_group_rule.sgrule-1707973622
+  id = "sg-0123456789_egress_-1_0_0_sg-9876543210"
+}
+
+import {
+  to = aws_security_group_rule.sgrule-3970541193
+  id = "sg-0123456789_ingress_tcp_443_443_10.0.0.0/16_10.1.0.0/16_::/0"
+}
@@ -0,0 +1,66 @@
+import {
+  to = aws_iam_role_policy._123456789012
+  id = "123456789012"
+}
+
+resource "aws_iam_role_policy" "_123456789012" {
+}
+
+import {
+  to = aws_iam_role_policy.driftctl_assume_role_driftctl_policy_10
+  id = "driftctl_assume_role:driftctl_policy.10"
+}
+
+resource "aws_iam_role_policy" "driftctl_assume_role_driftctl_policy_10" {
+  name   = "driftctl_policy.10"
+  policy = "{\"Version\":\"2012-10-17\"}"
+  role   = "driftctl_assume_role"
+}
+
+import {
+  to = aws_iam_user.driftctl
+  id = "driftctl"
+}
+
+resource "aws_iam_user" "driftctl" {
+  force_destroy = false
+  name          = "driftctl"
+  path          = "/"
+  tags = {
+    Env = "dev"
+  }
+}
+
+import {
+  to = aws_s3_bucket.my-bucket
+  id = "my-bucket"
+}
+
+resource "aws_s3_bucket" "my-bucket" {
+  acl           = "private"
+  bucket        = "my-bucket"
+  force_destroy = false
+  tags = {
+    Env                        = "prod"
+    "aws:cloudformation:stack" = "stack"
+  }
+  versioning {
+    enabled    = true
+    mfa_delete = false
+  }
+}
+
+import {
+  to = aws_security_group.sg-0123456789
+  id = "sg-0123456789"
+}
+
+resource "aws_security_group" "sg-0123456789" {
+  ingress = [{
+    cidr_blocks = ["0.0.0.0/0"]
+    from_port   = 443
+    protocol    = "tcp"
+    to_port     = 443
+  }]
+  name = "web"
+}
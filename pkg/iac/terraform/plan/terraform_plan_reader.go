@@ -0,0 +1,300 @@
+package plan
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform/addrs"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+	ctyconvert "github.com/zclconf/go-cty/cty/convert"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/snyk/driftctl/pkg/filter"
+	"github.com/snyk/driftctl/pkg/iac/config"
+	"github.com/snyk/driftctl/pkg/output"
+	"github.com/snyk/driftctl/pkg/resource"
+	"github.com/snyk/driftctl/pkg/terraform"
+)
+
+const TerraformPlanReaderSupplier = "tfplan"
+
+// Subset of the JSON plan format produced by terraform show -json
+type plan struct {
+	FormatVersion string       `json:"format_version"`
+	PlannedValues *stateValues `json:"planned_values"`
+	PriorState    *struct {
+		Values *stateValues `json:"values"`
+	} `json:"prior_state"`
+}
+
+type stateValues struct {
+	RootModule *module `json:"root_module"`
+}
+
+type module struct {
+	Address      string             `json:"address"`
+	Resources    []*plannedResource `json:"resources"`
+	ChildModules []*module          `json:"child_modules"`
+}
+
+type plannedResource struct {
+	Address      string                     `json:"address"`
+	Mode         string                     `json:"mode"`
+	Type         string                     `json:"type"`
+	Name         string                     `json:"name"`
+	ProviderName string                     `json:"provider_name"`
+	Values       map[string]json.RawMessage `json:"values"`
+	module       string
+}
+
+// TerraformPlanReader reads resources as they will be once a plan is applied.
+// Resources created or replaced by the plan do not know their identifier yet,
+// they are matched against remote resources using their identifying attributes.
+type TerraformPlanReader struct {
+	library      *terraform.ProviderLibrary
+	config       config.SupplierConfig
+	deserializer *resource.Deserializer
+	progress     output.Progress
+	filter       filter.Filter
+}
+
+func NewReader(config config.SupplierConfig, library *terraform.ProviderLibrary, progress output.Progress, deserializer *resource.Deserializer, filter filter.Filter) (*TerraformPlanReader, error) {
+	if config.Backend != "" {
+		return nil, errors.Errorf("Terraform plan can only be read from local files, backend '%s' is not supported", config.Backend)
+	}
+	return &TerraformPlanReader{
+		library:      library,
+		config:       config,
+		deserializer: deserializer,
+		progress:     progress,
+		filter:       filter,
+	}, nil
+}
+
+func (r *TerraformPlanReader) SourceCount() uint {
+	return 1
+}
+
+func (r *TerraformPlanReader) Resources() ([]*resource.Resource, error) {
+	logrus.WithFields(logrus.Fields{
+		"path": r.config.Path,
+	}).Debug("Reading resources from Terraform plan")
+	r.progress.Inc()
+
+	p, err := read(r.config.Path)
+	if err != nil {
+		return nil, errors.Wrap(err, r.config.String())
+	}
+
+	priorResources := make(map[string]*plannedResource)
+	if p.PriorState != nil && p.PriorState.Values != nil {
+		for _, res := range managedResources(p.PriorState.Values.RootModule) {
+			priorResources[res.Address] = res
+		}
+	}
+
+	results := make([]*resource.Resource, 0)
+	if p.PlannedValues == nil {
+		return results, nil
+	}
+	for _, res := range managedResources(p.PlannedValues.RootModule) {
+		decoded, err := r.decode(res, priorResources[res.Address])
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"address": res.Address,
+				"plan":    r.config.String(),
+			}).Warnf("Could not read from plan: %+v", err)
+			continue
+		}
+		if decoded != nil {
+			results = append(results, decoded)
+		}
+	}
+
+	return results, nil
+}
+
+func (r *TerraformPlanReader) decode(res, prior *plannedResource) (*resource.Resource, error) {
+	if !resource.IsResourceTypeSupported(res.Type) {
+		logrus.WithFields(logrus.Fields{
+			"name": res.Name,
+			"type": res.Type,
+		}).Debug("Ignored unsupported resource from plan")
+		return nil, nil
+	}
+
+	if r.filter != nil && r.filter.IsTypeIgnored(resource.ResourceType(res.Type)) {
+		logrus.WithFields(logrus.Fields{
+			"name": res.Name,
+			"type": res.Type,
+		}).Debug("Ignored resource from plan since it is ignored in filter")
+		return nil, nil
+	}
+
+	providerAddr, diags := addrs.ParseProviderSourceString(res.ProviderName)
+	if diags.HasErrors() {
+		return nil, diags.Err()
+	}
+	provider := r.library.Provider(providerAddr.Type)
+	if provider == nil {
+		logrus.WithFields(logrus.Fields{
+			"providerKey": providerAddr.Type,
+		}).Debug("Unsupported provider found in plan")
+		return nil, nil
+	}
+	schema, exists := provider.Schema()[res.Type]
+	if !exists || schema.Block == nil {
+		return nil, errors.Errorf("no schema found for %s", res.Type)
+	}
+
+	val, err := decodeValues(mergeValues(res.Values, prior), schema.Block.ImpliedType())
+	if err != nil {
+		return nil, err
+	}
+
+	// Resources to be created or replaced only get their identifier on apply
+	attrs := val.AsValueMap()
+	if attrs == nil {
+		attrs = make(map[string]cty.Value)
+	}
+	if id, exists := attrs["id"]; !exists || id.IsNull() {
+		attrs["id"] = cty.StringVal("")
+	}
+
+	decoded, err := r.deserializer.DeserializeOne(res.Type, cty.ObjectVal(attrs))
+	if err != nil {
+		return nil, err
+	}
+	source := resource.NewTerraformPlanSource(r.config.String(), res.module, res.Name)
+	// Addresses end with the instance key of resources declared with count or for_each
+	if i := strings.LastIndex(res.Address, res.Type+"."+res.Name); i >= 0 {
+		source.Key = res.Address[i+len(res.Type+"."+res.Name):]
+	}
+	decoded.Source = source
+	return decoded, nil
+}
+
+// mergeValues fills values only known after apply with their current value,
+// as long as the plan updates the resource in place
+func mergeValues(values map[string]json.RawMessage, prior *plannedResource) map[string]json.RawMessage {
+	if prior == nil || isNull(values["id"]) || string(values["id"]) != string(prior.Values["id"]) {
+		return values
+	}
+
+	merged := make(map[string]json.RawMessage, len(prior.Values))
+	for key, value := range prior.Values {
+		merged[key] = value
+	}
+	for key, value := range values {
+		if isNull(value) {
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+func decodeValues(values map[string]json.RawMessage, ty cty.Type) (cty.Value, error) {
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	val, err := ctyjson.Unmarshal(raw, ty)
+	if err == nil {
+		return val, nil
+	}
+
+	// Plan may have been generated with another version of the provider than the supported one,
+	// convert values manually to ignore new fields and to set missing ones to null
+	inputType, err := ctyjson.ImpliedType(raw)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	input, err := ctyjson.Unmarshal(raw, inputType)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	return ctyconvert.Convert(fillMissingAttributes(input, ty), ty)
+}
+
+// fillMissingAttributes adds attributes of the given type missing from the value as typed nulls,
+// object conversion fails otherwise
+func fillMissingAttributes(val cty.Value, ty cty.Type) cty.Value {
+	if val.IsNull() || !val.IsKnown() {
+		return val
+	}
+
+	switch {
+	case ty.IsObjectType() && val.Type().IsObjectType():
+		attrs := make(map[string]cty.Value, len(ty.AttributeTypes()))
+		for name, attrType := range ty.AttributeTypes() {
+			if !val.Type().HasAttribute(name) {
+				attrs[name] = cty.NullVal(attrType)
+				continue
+			}
+			attrs[name] = fillMissingAttributes(val.GetAttr(name), attrType)
+		}
+		return cty.ObjectVal(attrs)
+	case ty.IsMapType() && val.Type().IsObjectType():
+		attrs := make(map[string]cty.Value, len(val.Type().AttributeTypes()))
+		for name := range val.Type().AttributeTypes() {
+			attrs[name] = fillMissingAttributes(val.GetAttr(name), ty.ElementType())
+		}
+		return cty.ObjectVal(attrs)
+	case (ty.IsListType() || ty.IsSetType()) && val.Type().IsTupleType():
+		elems := make([]cty.Value, 0, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			_, elem := it.Element()
+			elems = append(elems, fillMissingAttributes(elem, ty.ElementType()))
+		}
+		return cty.TupleVal(elems)
+	}
+	return val
+}
+
+func isNull(value json.RawMessage) bool {
+	return len(value) == 0 || string(value) == "null"
+}
+
+func managedResources(root *module) []*plannedResource {
+	if root == nil {
+		return nil
+	}
+	resources := make([]*plannedResource, 0, len(root.Resources))
+	for _, res := range root.Resources {
+		if res.Mode != "managed" {
+			continue
+		}
+		res.module = root.Address
+		resources = append(resources, res)
+	}
+	for _, child := range root.ChildModules {
+		resources = append(resources, managedResources(child)...)
+	}
+	return resources
+}
+
+func read(path string) (*plan, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	p := &plan{}
+	if err := json.NewDecoder(file).Decode(p); err != nil {
+		return nil, errors.Wrap(err, "given file is not a valid JSON plan, make sure it was generated with terraform show -json")
+	}
+
+	// Format versions only break compatibility on major version bumps
+	major := strings.SplitN(p.FormatVersion, ".", 2)[0]
+	if major != "0" && major != "1" {
+		return nil, errors.Errorf("unsupported plan format version '%s'", p.FormatVersion)
+	}
+
+	return p, nil
+}
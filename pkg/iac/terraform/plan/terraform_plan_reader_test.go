@@ -0,0 +1,209 @@
+package plan
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/snyk/driftctl/pkg/iac/config"
+	"github.com/snyk/driftctl/pkg/output"
+	"github.com/snyk/driftctl/pkg/resource"
+	"github.com/snyk/driftctl/pkg/terraform"
+	testresource "github.com/snyk/driftctl/test/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func newGithubReader(t *testing.T, path string) *TerraformPlanReader {
+	library := testresource.InitFakeProviderLibrary(terraform.GITHUB, "4.4.0")
+	repo := testresource.InitFakeSchemaRepository(terraform.GITHUB, "4.4.0")
+	factory := terraform.NewTerraformResourceFactory(repo)
+
+	progress := &output.MockProgress{}
+	progress.On("Inc").Return()
+
+	reader, err := NewReader(config.SupplierConfig{Key: TerraformPlanReaderSupplier, Path: path}, library, progress, resource.NewDeserializer(factory), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reader
+}
+
+func TestTerraformPlanReader_Resources(t *testing.T) {
+	reader := newGithubReader(t, "testdata/plan.json")
+	got, err := reader.Resources()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), reader.SourceCount())
+
+	if !assert.Len(t, got, 3) {
+		return
+	}
+
+	// Updated in place, values only known after apply are the current ones
+	assert.Equal(t, "github_repository.main", got[0].SourceString())
+	assert.Equal(t, "tfplan://testdata/plan.json", got[0].Src().Source())
+	assert.Equal(t, "driftctl", got[0].ResourceId())
+	assert.Equal(t, "Detect, track and alert on infrastructure drift", *got[0].Attributes().GetString("description"))
+	assert.Equal(t, "https://github.com/snyk/driftctl", *got[0].Attributes().GetString("html_url"))
+
+	// Created by the plan, identifier is only known after apply
+	assert.Equal(t, "github_team.new", got[1].SourceString())
+	assert.Equal(t, "", got[1].ResourceId())
+	assert.Equal(t, "new-team", *got[1].Attributes().GetString("name"))
+	assert.IsType(t, &resource.TerraformPlanSource{}, got[1].Src())
+
+	assert.Equal(t, "module.membership.github_membership.member", got[2].SourceString())
+	assert.Equal(t, "", got[2].ResourceId())
+	assert.Equal(t, "driftctl-bot", *got[2].Attributes().GetString("username"))
+}
+
+func TestTerraformPlanReader_InvalidPlan(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{
+			name:    "missing file",
+			path:    "testdata/missing.json",
+			wantErr: "tfplan://testdata/missing.json: open testdata/missing.json: no such file or directory",
+		},
+		{
+			name:    "not a JSON plan",
+			path:    "testdata/invalid.json",
+			wantErr: "tfplan://testdata/invalid.json: given file is not a valid JSON plan, make sure it was generated with terraform show -json: invalid character 'h' in literal true (expecting 'r')",
+		},
+		{
+			name:    "unsupported format version",
+			path:    "testdata/unsupported_version.json",
+			wantErr: "tfplan://testdata/unsupported_version.json: unsupported plan format version '2.0'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newGithubReader(t, tt.path).Resources()
+			assert.Nil(t, got)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestNewReader_WithBackend(t *testing.T) {
+	reader, err := NewReader(config.SupplierConfig{Key: TerraformPlanReaderSupplier, Backend: "s3", Path: "bucket/plan.json"}, terraform.NewProviderLibrary(), nil, nil, nil)
+	assert.Nil(t, reader)
+	assert.EqualError(t, err, "Terraform plan can only be read from local files, backend 's3' is not supported")
+}
+
+func Test_mergeValues(t *testing.T) {
+	prior := &plannedResource{
+		Values: map[string]json.RawMessage{
+			"id":   json.RawMessage(`"bucket"`),
+			"arn":  json.RawMessage(`"arn:aws:s3:::bucket"`),
+			"acl":  json.RawMessage(`"private"`),
+			"tags": json.RawMessage(`{"Name":"old"}`),
+		},
+	}
+
+	tests := []struct {
+		name   string
+		values map[string]json.RawMessage
+		prior  *plannedResource
+		want   map[string]json.RawMessage
+	}{
+		{
+			name:   "created resource",
+			values: map[string]json.RawMessage{"acl": json.RawMessage(`"private"`)},
+			prior:  nil,
+			want:   map[string]json.RawMessage{"acl": json.RawMessage(`"private"`)},
+		},
+		{
+			name:   "replaced resource",
+			values: map[string]json.RawMessage{"id": json.RawMessage(`null`), "acl": json.RawMessage(`"public-read"`)},
+			prior:  prior,
+			want:   map[string]json.RawMessage{"id": json.RawMessage(`null`), "acl": json.RawMessage(`"public-read"`)},
+		},
+		{
+			name: "updated resource",
+			values: map[string]json.RawMessage{
+				"id":   json.RawMessage(`"bucket"`),
+				"arn":  json.RawMessage(`null`),
+				"tags": json.RawMessage(`{"Name":"new"}`),
+			},
+			prior: prior,
+			want: map[string]json.RawMessage{
+				"id":   json.RawMessage(`"bucket"`),
+				"arn":  json.RawMessage(`"arn:aws:s3:::bucket"`),
+				"acl":  json.RawMessage(`"private"`),
+				"tags": json.RawMessage(`{"Name":"new"}`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, mergeValues(tt.values, tt.prior))
+		})
+	}
+}
+
+func Test_decodeValues(t *testing.T) {
+	ty := cty.Object(map[string]cty.Type{
+		"id":   cty.String,
+		"name": cty.String,
+		"tags": cty.Map(cty.String),
+		"rule": cty.List(cty.Object(map[string]cty.Type{
+			"port":     cty.Number,
+			"protocol": cty.String,
+		})),
+	})
+
+	tests := []struct {
+		name   string
+		values map[string]json.RawMessage
+		want   cty.Value
+	}{
+		{
+			name: "values of the supported provider",
+			values: map[string]json.RawMessage{
+				"id":   json.RawMessage(`"sg"`),
+				"name": json.RawMessage(`"web"`),
+				"tags": json.RawMessage(`{"Env":"prod"}`),
+				"rule": json.RawMessage(`[{"port":443,"protocol":"tcp"}]`),
+			},
+			want: cty.ObjectVal(map[string]cty.Value{
+				"id":   cty.StringVal("sg"),
+				"name": cty.StringVal("web"),
+				"tags": cty.MapVal(map[string]cty.Value{"Env": cty.StringVal("prod")}),
+				"rule": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{
+					"port":     cty.NumberIntVal(443),
+					"protocol": cty.StringVal("tcp"),
+				})}),
+			}),
+		},
+		{
+			name: "values of another provider version with new and missing attributes",
+			values: map[string]json.RawMessage{
+				"id":          json.RawMessage(`"sg"`),
+				"description": json.RawMessage(`"added in a newer provider"`),
+				"tags":        json.RawMessage(`{"Env":"prod"}`),
+				"rule":        json.RawMessage(`[{"port":443,"ipv6":false}]`),
+			},
+			want: cty.ObjectVal(map[string]cty.Value{
+				"id":   cty.StringVal("sg"),
+				"name": cty.NullVal(cty.String),
+				"tags": cty.MapVal(map[string]cty.Value{"Env": cty.StringVal("prod")}),
+				"rule": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{
+					"port":     cty.NumberIntVal(443),
+					"protocol": cty.NullVal(cty.String),
+				})}),
+			}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeValues(tt.values, ty)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.True(t, tt.want.RawEquals(got), "expected %#v, got %#v", tt.want, got)
+		})
+	}
+}
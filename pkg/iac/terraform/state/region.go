@@ -0,0 +1,31 @@
+package state
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/snyk/driftctl/pkg/resource"
+)
+
+var awsRegionRegexp = regexp.MustCompile(`^[a-z]{2}(-gov|-iso[a-z]*)?-[a-z]+-\d$`)
+
+// resourceRegion guesses the region of a resource read from a state from its region attribute, its ARN or the alias
+// of its provider when named after a region (e.g. provider "aws" { alias = "eu_west_3" }), it is empty when unknown
+func resourceRegion(res *resource.Resource, providerAlias string) string {
+	if res.Attrs != nil {
+		if region := res.Attrs.GetString("region"); region != nil && *region != "" {
+			return *region
+		}
+		// arn:PARTITION:SERVICE:REGION:ACCOUNT:RESOURCE, global resources have an empty region
+		if arn := res.Attrs.GetString("arn"); arn != nil {
+			if parts := strings.SplitN(*arn, ":", 6); len(parts) == 6 && parts[0] == "arn" && parts[3] != "" {
+				return parts[3]
+			}
+		}
+	}
+	alias := strings.ReplaceAll(providerAlias, "_", "-")
+	if awsRegionRegexp.MatchString(alias) {
+		return alias
+	}
+	return ""
+}
@@ -0,0 +1,51 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/snyk/driftctl/pkg/resource"
+)
+
+func Test_resourceRegion(t *testing.T) {
+	tests := []struct {
+		name  string
+		res   *resource.Resource
+		alias string
+		want  string
+	}{
+		{
+			name: "region attribute",
+			res:  &resource.Resource{Attrs: &resource.Attributes{"region": "eu-west-3", "arn": "arn:aws:s3:::bucket"}},
+			want: "eu-west-3",
+		},
+		{
+			name: "regional arn",
+			res:  &resource.Resource{Attrs: &resource.Attributes{"arn": "arn:aws:lambda:us-east-1:123456789012:function:foo"}},
+			want: "us-east-1",
+		},
+		{
+			name:  "global arn and provider alias named after a region",
+			res:   &resource.Resource{Attrs: &resource.Attributes{"arn": "arn:aws:iam::123456789012:role/foo"}},
+			alias: "eu_central_1",
+			want:  "eu-central-1",
+		},
+		{
+			name:  "provider alias not named after a region",
+			res:   &resource.Resource{Attrs: &resource.Attributes{}},
+			alias: "replica",
+			want:  "",
+		},
+		{
+			name: "resource without attributes",
+			res:  &resource.Resource{},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, resourceRegion(tt.res, tt.alias))
+		})
+	}
+}
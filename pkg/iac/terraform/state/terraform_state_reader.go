@@ -27,8 +27,9 @@ import (
 const TerraformStateReaderSupplier = "tfstate"
 
 type decodedRes struct {
-	source resource.Source
-	val    cty.Value
+	source        resource.Source
+	val           cty.Value
+	providerAlias string
 }
 
 type TerraformStateReader struct {
@@ -123,7 +124,7 @@ func (r *TerraformStateReader) retrieve() (map[string][]decodedRes, error) {
 				continue
 			}
 			schema := provider.Schema()[stateRes.Addr.Resource.Type]
-			for _, instance := range stateRes.Instances {
+			for key, instance := range stateRes.Instances {
 				decodedVal, err := instance.Current.Decode(schema.Block.ImpliedType())
 				if err != nil {
 					// Try to do a manual type conversion if we got a path error
@@ -150,9 +151,14 @@ func (r *TerraformStateReader) retrieve() (map[string][]decodedRes, error) {
 					}
 				}
 				_, exists := resMap[stateRes.Addr.Resource.Type]
+				source := resource.NewTerraformStateSource(r.config.String(), moduleName, resName)
+				if key != addrs.NoKey {
+					source.Key = key.String()
+				}
 				val := decodedRes{
-					source: resource.NewTerraformStateSource(r.config.String(), moduleName, resName),
-					val:    decodedVal.Value,
+					source:        source,
+					val:           decodedVal.Value,
+					providerAlias: stateRes.ProviderConfig.Alias,
 				}
 				if !exists {
 					resMap[stateRes.Addr.Resource.Type] = []decodedRes{val}
@@ -209,6 +215,7 @@ func (r *TerraformStateReader) decode(valFromState map[string][]decodedRes) ([]*
 				continue
 			}
 			res.Source = stateVal.source
+			res.Region = resourceRegion(res, stateVal.providerAlias)
 			results = append(results, res)
 		}
 	}
@@ -0,0 +1,280 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/pkg/resource"
+)
+
+// Exit codes of each kind of condition, 1 is kept for an infrastructure not in sync and 2 for crashes
+const (
+	ExitCodeCoverage        = 3
+	ExitCodeMissing         = 4
+	ExitCodeDrift           = 5
+	ExitCodeUnmanagedGrowth = 6
+	ExitCodeDuplicated      = 7
+	ExitCodeSeverity        = 8
+)
+
+const (
+	coveragePrefix        = "coverage<"
+	missingKey            = "missing"
+	driftKey              = "drift"
+	unmanagedGrowthPrefix = "unmanaged-growth:"
+	duplicatedKey         = "duplicated"
+	severityPrefix        = "severity:"
+)
+
+// Examples of accepted conditions
+var Examples = []string{
+	"coverage<80",
+	"missing",
+	"missing:aws_s3_bucket,aws_iam_role",
+	"drift",
+	"drift:acl,tags.Env",
+	"unmanaged-growth:PATH/TO/BASELINE.json",
+	"duplicated",
+	"duplicated:aws_s3_bucket",
+	"severity:high",
+}
+
+// Condition fails a scan when the analysis meets it
+type Condition interface {
+	String() string
+	ExitCode() int
+	// Check returns why the analysis meets the condition, or an empty string when it does not
+	Check(analysis *analyser.Analysis) string
+}
+
+// Parse reads a condition from its flag representation, see Examples
+func Parse(flag string) (Condition, error) {
+	switch {
+	case strings.HasPrefix(flag, coveragePrefix):
+		threshold, err := strconv.Atoi(strings.TrimPrefix(flag, coveragePrefix))
+		if err != nil || threshold < 0 || threshold > 100 {
+			return nil, errors.Errorf("Unable to parse fail condition '%s', coverage threshold must be a percentage", flag)
+		}
+		return &coverageCondition{flag, threshold}, nil
+	case flag == missingKey || strings.HasPrefix(flag, missingKey+":"):
+		return &missingCondition{flag, parseList(flag, missingKey)}, nil
+	case flag == driftKey || strings.HasPrefix(flag, driftKey+":"):
+		return &driftCondition{flag, parseList(flag, driftKey)}, nil
+	case flag == duplicatedKey || strings.HasPrefix(flag, duplicatedKey+":"):
+		return &duplicatedCondition{flag, parseList(flag, duplicatedKey)}, nil
+	case strings.HasPrefix(flag, severityPrefix):
+		severity, err := analyser.ParseSeverity(strings.TrimPrefix(flag, severityPrefix))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Unable to parse fail condition '%s'", flag)
+		}
+		return &severityCondition{flag, severity}, nil
+	case strings.HasPrefix(flag, unmanagedGrowthPrefix):
+		path := strings.TrimPrefix(flag, unmanagedGrowthPrefix)
+		baseline, err := readBaseline(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Unable to read baseline of fail condition '%s'", flag)
+		}
+		return &unmanagedGrowthCondition{flag, len(baseline.Unmanaged())}, nil
+	}
+	return nil, errors.Errorf("Unable to parse fail condition '%s', accepted conditions are: %s", flag, strings.Join(Examples, ", "))
+}
+
+// Evaluate returns conditions met by the analysis, in the order they were given
+func Evaluate(conditions []Condition, analysis *analyser.Analysis) []analyser.FailedCondition {
+	failed := make([]analyser.FailedCondition, 0)
+	for _, condition := range conditions {
+		if message := condition.Check(analysis); message != "" {
+			failed = append(failed, analyser.FailedCondition{
+				Condition: condition.String(),
+				Message:   message,
+				ExitCode:  condition.ExitCode(),
+			})
+		}
+	}
+	return failed
+}
+
+type coverageCondition struct {
+	flag      string
+	threshold int
+}
+
+func (c *coverageCondition) String() string { return c.flag }
+func (c *coverageCondition) ExitCode() int  { return ExitCodeCoverage }
+
+func (c *coverageCondition) Check(analysis *analyser.Analysis) string {
+	if analysis.Coverage() >= c.threshold {
+		return ""
+	}
+	return fmt.Sprintf("Coverage is %d%%, expected at least %d%%", analysis.Coverage(), c.threshold)
+}
+
+type missingCondition struct {
+	flag  string
+	types []string
+}
+
+func (c *missingCondition) String() string { return c.flag }
+func (c *missingCondition) ExitCode() int  { return ExitCodeMissing }
+
+func (c *missingCondition) Check(analysis *analyser.Analysis) string {
+	missing := make([]string, 0)
+	for _, res := range analysis.Deleted() {
+		if len(c.types) == 0 || contains(c.types, res.ResourceType()) {
+			missing = append(missing, fmt.Sprintf("%s.%s", res.ResourceType(), res.ResourceId()))
+		}
+	}
+	if len(missing) == 0 {
+		return ""
+	}
+	sort.Strings(missing)
+	return fmt.Sprintf("%d resource(s) missing: %s", len(missing), strings.Join(missing, ", "))
+}
+
+type driftCondition struct {
+	flag  string
+	paths []string
+}
+
+func (c *driftCondition) String() string { return c.flag }
+func (c *driftCondition) ExitCode() int  { return ExitCodeDrift }
+
+func (c *driftCondition) Check(analysis *analyser.Analysis) string {
+	drifts := make([]string, 0)
+	for _, difference := range analysis.Differences() {
+		for _, change := range difference.Changelog {
+			path := strings.Join(change.Path, ".")
+			if len(c.paths) > 0 && !matchesPath(c.paths, path) {
+				continue
+			}
+			drifts = append(drifts, fmt.Sprintf("%s.%s: %s", difference.Res.ResourceType(), difference.Res.ResourceId(), path))
+		}
+	}
+	if len(drifts) == 0 {
+		return ""
+	}
+	sort.Strings(drifts)
+	return fmt.Sprintf("%d change(s) detected: %s", len(drifts), strings.Join(drifts, ", "))
+}
+
+type unmanagedGrowthCondition struct {
+	flag     string
+	baseline int
+}
+
+func (c *unmanagedGrowthCondition) String() string { return c.flag }
+func (c *unmanagedGrowthCondition) ExitCode() int  { return ExitCodeUnmanagedGrowth }
+
+func (c *unmanagedGrowthCondition) Check(analysis *analyser.Analysis) string {
+	count := len(analysis.Unmanaged())
+	if count <= c.baseline {
+		return ""
+	}
+	return fmt.Sprintf("%d unmanaged resource(s), up from %d in baseline", count, c.baseline)
+}
+
+type duplicatedCondition struct {
+	flag  string
+	types []string
+}
+
+func (c *duplicatedCondition) String() string { return c.flag }
+func (c *duplicatedCondition) ExitCode() int  { return ExitCodeDuplicated }
+
+func (c *duplicatedCondition) Check(analysis *analyser.Analysis) string {
+	duplicated := make([]string, 0)
+	for _, duplicate := range analysis.Duplicated() {
+		if len(c.types) > 0 && !contains(c.types, duplicate.Res.ResourceType()) {
+			continue
+		}
+		claims := make([]string, 0, len(duplicate.Claims))
+		for _, claim := range duplicate.Claims {
+			claims = append(claims, claimString(claim))
+		}
+		duplicated = append(duplicated, fmt.Sprintf("%s.%s (%s)", duplicate.Res.ResourceType(), duplicate.Res.ResourceId(), strings.Join(claims, ", ")))
+	}
+	if len(duplicated) == 0 {
+		return ""
+	}
+	sort.Strings(duplicated)
+	return fmt.Sprintf("%d resource(s) managed more than once: %s", len(duplicated), strings.Join(duplicated, ", "))
+}
+
+type severityCondition struct {
+	flag     string
+	severity analyser.Severity
+}
+
+func (c *severityCondition) String() string { return c.flag }
+func (c *severityCondition) ExitCode() int  { return ExitCodeSeverity }
+
+func (c *severityCondition) Check(analysis *analyser.Analysis) string {
+	drifts := make([]string, 0)
+	for _, difference := range analysis.Differences() {
+		for _, change := range difference.Changelog {
+			if change.Severity < c.severity {
+				continue
+			}
+			drifts = append(drifts, fmt.Sprintf("%s.%s: %s [%s]", difference.Res.ResourceType(), difference.Res.ResourceId(), strings.Join(change.Path, "."), change.Severity))
+		}
+	}
+	if len(drifts) == 0 {
+		return ""
+	}
+	sort.Strings(drifts)
+	return fmt.Sprintf("%d change(s) of severity %s or higher: %s", len(drifts), c.severity, strings.Join(drifts, ", "))
+}
+
+// claimString locates an IaC resource by its address and the state it was read from
+func claimString(res *resource.Resource) string {
+	if res.Src() == nil {
+		return fmt.Sprintf("%s.%s", res.ResourceType(), res.ResourceId())
+	}
+	return fmt.Sprintf("%s in %s", res.Address(), res.Src().Source())
+}
+
+func parseList(flag, key string) []string {
+	list := strings.TrimPrefix(strings.TrimPrefix(flag, key), ":")
+	if list == "" {
+		return nil
+	}
+	return strings.Split(list, ",")
+}
+
+// matchesPath tells whether path is one of the given paths or is nested into one of them
+func matchesPath(paths []string, path string) bool {
+	for _, p := range paths {
+		if path == p || strings.HasPrefix(path, p+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func readBaseline(path string) (*analyser.Analysis, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	analysis := &analyser.Analysis{}
+	if err := json.Unmarshal(content, analysis); err != nil {
+		return nil, err
+	}
+	return analysis, nil
+}
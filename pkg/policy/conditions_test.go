@@ -0,0 +1,201 @@
+package policy
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/r3labs/diff/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/pkg/resource"
+)
+
+func fakeAnalysis() *analyser.Analysis {
+	a := analyser.NewAnalysis(analyser.AnalyzerOptions{Deep: true})
+	a.AddManaged(
+		&resource.Resource{Id: "bucket", Type: "aws_s3_bucket"},
+		&resource.Resource{Id: "role", Type: "aws_iam_role"},
+	)
+	a.AddUnmanaged(
+		&resource.Resource{Id: "user", Type: "aws_iam_user"},
+		&resource.Resource{Id: "other-user", Type: "aws_iam_user"},
+	)
+	a.AddDeleted(
+		&resource.Resource{Id: "deleted-bucket", Type: "aws_s3_bucket"},
+		&resource.Resource{Id: "deleted-instance", Type: "aws_instance"},
+	)
+	a.AddDifference(analyser.Difference{
+		Res: &resource.Resource{Id: "bucket", Type: "aws_s3_bucket"},
+		Changelog: analyser.Changelog{
+			{Change: diff.Change{Type: diff.UPDATE, Path: []string{"acl"}, From: "private", To: "public-read"}, Severity: analyser.SeverityHigh},
+			{Change: diff.Change{Type: diff.CREATE, Path: []string{"tags", "Env"}, To: "prod"}, Severity: analyser.SeverityLow},
+		},
+	})
+	a.AddDuplicated(analyser.Duplicate{
+		Res: &resource.Resource{Id: "role", Type: "aws_iam_role"},
+		Claims: []*resource.Resource{
+			{Id: "role", Type: "aws_iam_role", Source: resource.NewTerraformStateSource("tfstate://first.tfstate", "", "role")},
+			{Id: "role", Type: "aws_iam_role", Source: resource.NewTerraformStateSource("tfstate://second.tfstate", "module.iam", "role")},
+		},
+	})
+	return a
+}
+
+func writeBaseline(t *testing.T, unmanaged int) string {
+	a := analyser.NewAnalysis(analyser.AnalyzerOptions{})
+	for i := 0; i < unmanaged; i++ {
+		a.AddUnmanaged(&resource.Resource{Id: string(rune('a' + i)), Type: "aws_iam_user"})
+	}
+	content, err := json.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseline := filepath.Join(t.TempDir(), "baseline.json")
+	if err := os.WriteFile(baseline, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return baseline
+}
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		name     string
+		flag     string
+		exitCode int
+		err      string
+	}{
+		{name: "coverage", flag: "coverage<80", exitCode: ExitCodeCoverage},
+		{name: "coverage not a number", flag: "coverage<abc", err: "Unable to parse fail condition 'coverage<abc', coverage threshold must be a percentage"},
+		{name: "coverage above 100", flag: "coverage<101", err: "Unable to parse fail condition 'coverage<101', coverage threshold must be a percentage"},
+		{name: "any missing resource", flag: "missing", exitCode: ExitCodeMissing},
+		{name: "missing resource types", flag: "missing:aws_s3_bucket,aws_iam_role", exitCode: ExitCodeMissing},
+		{name: "any drift", flag: "drift", exitCode: ExitCodeDrift},
+		{name: "drift paths", flag: "drift:acl,tags.Env", exitCode: ExitCodeDrift},
+		{name: "unmanaged growth", flag: "unmanaged-growth:" + writeBaseline(t, 1), exitCode: ExitCodeUnmanagedGrowth},
+		{name: "unmanaged growth without baseline", flag: "unmanaged-growth:" + filepath.Join(t.TempDir(), "nope.json"), err: "Unable to read baseline of fail condition"},
+		{name: "any duplicated resource", flag: "duplicated", exitCode: ExitCodeDuplicated},
+		{name: "duplicated resource types", flag: "duplicated:aws_s3_bucket", exitCode: ExitCodeDuplicated},
+		{name: "unknown condition", flag: "missings", err: "Unable to parse fail condition 'missings', accepted conditions are: coverage<80, missing, missing:aws_s3_bucket,aws_iam_role, drift, drift:acl,tags.Env, unmanaged-growth:PATH/TO/BASELINE.json, duplicated, duplicated:aws_s3_bucket, severity:high"},
+		{name: "severity", flag: "severity:high", exitCode: ExitCodeSeverity},
+		{name: "unknown severity", flag: "severity:urgent", err: "Unable to parse fail condition 'severity:urgent': unknown severity 'urgent'"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			condition, err := Parse(c.flag)
+			if c.err != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), c.err)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.flag, condition.String())
+			assert.Equal(t, c.exitCode, condition.ExitCode())
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	smallerBaseline := writeBaseline(t, 1)
+	sameBaseline := writeBaseline(t, 2)
+
+	cases := []struct {
+		name     string
+		flags    []string
+		expected []analyser.FailedCondition
+	}{
+		{
+			name:     "no condition",
+			flags:    []string{},
+			expected: []analyser.FailedCondition{},
+		},
+		{
+			name:  "coverage below threshold",
+			flags: []string{"coverage<50", "coverage<30"},
+			expected: []analyser.FailedCondition{
+				{Condition: "coverage<50", Message: "Coverage is 33%, expected at least 50%", ExitCode: ExitCodeCoverage},
+			},
+		},
+		{
+			name:  "any missing resource",
+			flags: []string{"missing"},
+			expected: []analyser.FailedCondition{
+				{Condition: "missing", Message: "2 resource(s) missing: aws_instance.deleted-instance, aws_s3_bucket.deleted-bucket", ExitCode: ExitCodeMissing},
+			},
+		},
+		{
+			name:  "missing resources of given types",
+			flags: []string{"missing:aws_s3_bucket", "missing:aws_iam_role"},
+			expected: []analyser.FailedCondition{
+				{Condition: "missing:aws_s3_bucket", Message: "1 resource(s) missing: aws_s3_bucket.deleted-bucket", ExitCode: ExitCodeMissing},
+			},
+		},
+		{
+			name:  "any drift",
+			flags: []string{"drift"},
+			expected: []analyser.FailedCondition{
+				{Condition: "drift", Message: "2 change(s) detected: aws_s3_bucket.bucket: acl, aws_s3_bucket.bucket: tags.Env", ExitCode: ExitCodeDrift},
+			},
+		},
+		{
+			name:  "drift on given paths",
+			flags: []string{"drift:tags", "drift:ac,policy"},
+			expected: []analyser.FailedCondition{
+				{Condition: "drift:tags", Message: "1 change(s) detected: aws_s3_bucket.bucket: tags.Env", ExitCode: ExitCodeDrift},
+			},
+		},
+		{
+			name:  "unmanaged growth",
+			flags: []string{"unmanaged-growth:" + smallerBaseline, "unmanaged-growth:" + sameBaseline},
+			expected: []analyser.FailedCondition{
+				{Condition: "unmanaged-growth:" + smallerBaseline, Message: "2 unmanaged resource(s), up from 1 in baseline", ExitCode: ExitCodeUnmanagedGrowth},
+			},
+		},
+		{
+			name:  "any duplicated resource",
+			flags: []string{"duplicated"},
+			expected: []analyser.FailedCondition{
+				{Condition: "duplicated", Message: "1 resource(s) managed more than once: aws_iam_role.role (aws_iam_role.role in tfstate://first.tfstate, module.iam.aws_iam_role.role in tfstate://second.tfstate)", ExitCode: ExitCodeDuplicated},
+			},
+		},
+		{
+			name:     "duplicated resources of given types",
+			flags:    []string{"duplicated:aws_s3_bucket"},
+			expected: []analyser.FailedCondition{},
+		},
+		{
+			name:  "changes of given severity",
+			flags: []string{"severity:high", "severity:critical"},
+			expected: []analyser.FailedCondition{
+				{Condition: "severity:high", Message: "1 change(s) of severity high or higher: aws_s3_bucket.bucket: acl [high]", ExitCode: ExitCodeSeverity},
+			},
+		},
+		{
+			name:  "changes of lower severity",
+			flags: []string{"severity:low"},
+			expected: []analyser.FailedCondition{
+				{Condition: "severity:low", Message: "2 change(s) of severity low or higher: aws_s3_bucket.bucket: acl [high], aws_s3_bucket.bucket: tags.Env [low]", ExitCode: ExitCodeSeverity},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			conditions := make([]Condition, 0, len(c.flags))
+			for _, flag := range c.flags {
+				condition, err := Parse(flag)
+				if err != nil {
+					t.Fatal(err)
+				}
+				conditions = append(conditions, condition)
+			}
+
+			failed := Evaluate(conditions, fakeAnalysis())
+			assert.Equal(t, c.expected, failed)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package aws
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/snyk/driftctl/pkg/remote/aws/repository"
+	"github.com/snyk/driftctl/pkg/remote/common"
+	"github.com/snyk/driftctl/pkg/resource"
+	"github.com/snyk/driftctl/pkg/terraform"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// AccountIDPlaceholder is replaced by the scanned account ID in the assumed role ARN
+const AccountIDPlaceholder = "{account_id}"
+
+const accountAliasSeparator = "/"
+
+var accountIDRegex = regexp.MustCompile(`^\d{12}$`)
+
+func IsValidAccountID(account string) bool {
+	return accountIDRegex.MatchString(account)
+}
+
+// roleARNForAccount renders the role ARN template for a given account
+func roleARNForAccount(roleARN, account string) string {
+	return strings.ReplaceAll(roleARN, AccountIDPlaceholder, account)
+}
+
+// accountAlias namespaces a terraform provider alias with an account ID,
+// so that each account gets its own gRPC client with its assumed role
+func accountAlias(account, region string) string {
+	return account + accountAliasSeparator + region
+}
+
+func parseAccountAlias(alias string) (account string, region string) {
+	parts := strings.SplitN(alias, accountAliasSeparator, 2)
+	if len(parts) != 2 {
+		return "", alias
+	}
+	return parts[0], parts[1]
+}
+
+// AccountEnumerator wraps an enumerator bound to a given account
+// and records that account on every enumerated resource
+type AccountEnumerator struct {
+	account    string
+	enumerator common.Enumerator
+}
+
+func NewAccountEnumerator(account string, enumerator common.Enumerator) *AccountEnumerator {
+	return &AccountEnumerator{
+		account:    account,
+		enumerator: enumerator,
+	}
+}
+
+func (e *AccountEnumerator) SupportedType() resource.ResourceType {
+	return e.enumerator.SupportedType()
+}
+
+func (e *AccountEnumerator) Enumerate() ([]*resource.Resource, error) {
+	resources, err := e.enumerator.Enumerate()
+	if err != nil {
+		return nil, err
+	}
+	for _, res := range resources {
+		if res == nil {
+			continue
+		}
+		res.Account = e.account
+	}
+	return resources, nil
+}
+
+// AccountDetailsFetcher dispatches details retrieval to the fetcher
+// bound to the account the resource was enumerated from
+type AccountDetailsFetcher struct {
+	fetchers map[string]common.DetailsFetcher
+}
+
+func NewAccountDetailsFetcher() *AccountDetailsFetcher {
+	return &AccountDetailsFetcher{
+		fetchers: make(map[string]common.DetailsFetcher),
+	}
+}
+
+func (f *AccountDetailsFetcher) AddDetailsFetcher(account string, fetcher common.DetailsFetcher) {
+	f.fetchers[account] = fetcher
+}
+
+func (f *AccountDetailsFetcher) ReadDetails(res *resource.Resource) (*resource.Resource, error) {
+	fetcher, exist := f.fetchers[res.Account]
+	if !exist {
+		logrus.WithFields(logrus.Fields{
+			"type":    res.ResourceType(),
+			"id":      res.ResourceId(),
+			"account": res.Account,
+		}).Debug("No details fetcher found for account, skipping details retrieval")
+		return res, nil
+	}
+
+	resourceWithDetails, err := fetcher.ReadDetails(res)
+	if err != nil {
+		return nil, err
+	}
+	if resourceWithDetails != nil {
+		resourceWithDetails.Account = res.Account
+	}
+	return resourceWithDetails, nil
+}
+
+// accountResourceReader reads resources using the terraform provider alias
+// of a given account, keeping the region of the requested alias
+type accountResourceReader struct {
+	reader        terraform.ResourceReader
+	account       string
+	defaultRegion string
+}
+
+func newAccountResourceReader(reader terraform.ResourceReader, account, defaultRegion string) *accountResourceReader {
+	return &accountResourceReader{
+		reader:        reader,
+		account:       account,
+		defaultRegion: defaultRegion,
+	}
+}
+
+func (r *accountResourceReader) ReadResource(args terraform.ReadResourceArgs) (*cty.Value, error) {
+	region := args.Attributes["alias"]
+	if region == "" {
+		region = r.defaultRegion
+	}
+	attributes := make(map[string]string, len(args.Attributes)+1)
+	for k, v := range args.Attributes {
+		attributes[k] = v
+	}
+	attributes["alias"] = accountAlias(r.account, region)
+	args.Attributes = attributes
+	return r.reader.ReadResource(args)
+}
+
+// resolveAccounts returns the deduplicated list of accounts to scan,
+// active accounts of the organization are added when discovery is enabled
+func resolveAccounts(repo repository.OrganizationsRepository, opts Options) ([]string, error) {
+	accounts := append([]string{}, opts.Accounts...)
+
+	if opts.DiscoverAccounts {
+		organizationAccounts, err := repo.ListAllAccounts()
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to list accounts of the AWS organization")
+		}
+		for _, account := range organizationAccounts {
+			if account.Status == nil || *account.Status != organizations.AccountStatusActive {
+				continue
+			}
+			accounts = append(accounts, *account.Id)
+		}
+	}
+
+	uniqueAccounts := make(map[string]struct{}, len(accounts))
+	result := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		if _, exist := uniqueAccounts[account]; exist {
+			continue
+		}
+		if !IsValidAccountID(account) {
+			return nil, errors.Errorf("invalid AWS account ID '%s'", account)
+		}
+		uniqueAccounts[account] = struct{}{}
+		result = append(result, account)
+	}
+	sort.Strings(result)
+
+	return result, nil
+}
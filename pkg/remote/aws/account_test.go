@@ -0,0 +1,159 @@
+package aws
+
+import (
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/pkg/errors"
+	"github.com/snyk/driftctl/pkg/remote/aws/repository"
+	"github.com/snyk/driftctl/pkg/remote/common"
+	"github.com/snyk/driftctl/pkg/resource"
+	"github.com/snyk/driftctl/pkg/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountEnumerator_Enumerate(t *testing.T) {
+	enumerator := &common.MockEnumerator{}
+	enumerator.On("SupportedType").Return(resource.ResourceType("aws_iam_role"))
+	enumerator.On("Enumerate").Return([]*resource.Resource{
+		{Id: "admin", Type: "aws_iam_role"},
+		nil,
+	}, nil)
+
+	e := NewAccountEnumerator("111111111111", NewRegionalEnumerator("eu-west-3", enumerator))
+	assert.Equal(t, resource.ResourceType("aws_iam_role"), e.SupportedType())
+
+	got, err := e.Enumerate()
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+	assert.Equal(t, "111111111111", got[0].Account)
+	assert.Equal(t, "eu-west-3", got[0].Region)
+}
+
+func TestAccountEnumerator_EnumerateError(t *testing.T) {
+	listingErr := errors.New("access denied")
+	enumerator := &common.MockEnumerator{}
+	enumerator.On("Enumerate").Return(nil, listingErr)
+
+	got, err := NewAccountEnumerator("111111111111", enumerator).Enumerate()
+	assert.Nil(t, got)
+	assert.Equal(t, listingErr, err)
+}
+
+func TestAccountDetailsFetcher_ReadDetails(t *testing.T) {
+	prodFetcher := &fakeDetailsFetcher{}
+	stagingFetcher := &fakeDetailsFetcher{}
+
+	f := NewAccountDetailsFetcher()
+	f.AddDetailsFetcher("111111111111", prodFetcher)
+	f.AddDetailsFetcher("222222222222", stagingFetcher)
+
+	res := &resource.Resource{Id: "admin", Type: "aws_iam_role", Account: "222222222222"}
+	got, err := f.ReadDetails(res)
+	assert.NoError(t, err)
+	assert.Equal(t, "222222222222", got.Account)
+	assert.Len(t, stagingFetcher.read, 1)
+	assert.Len(t, prodFetcher.read, 0)
+
+	unknown := &resource.Resource{Id: "admin", Type: "aws_iam_role", Account: "333333333333"}
+	got, err = f.ReadDetails(unknown)
+	assert.NoError(t, err)
+	assert.Same(t, unknown, got)
+}
+
+func TestAccountResourceReader_ReadResource(t *testing.T) {
+	reader := &fakeResourceReader{}
+	r := newRegionalResourceReader(newAccountResourceReader(reader, "111111111111", "us-east-1"), "eu-west-3")
+	global := newAccountResourceReader(reader, "111111111111", "us-east-1")
+
+	attributes := map[string]string{"foo": "bar"}
+	_, err := r.ReadResource(terraform.ReadResourceArgs{Ty: "aws_instance", ID: "i-1", Attributes: attributes})
+	assert.NoError(t, err)
+	_, err = r.ReadResource(terraform.ReadResourceArgs{Ty: "aws_s3_bucket", ID: "bucket", Attributes: map[string]string{"alias": "us-west-2"}})
+	assert.NoError(t, err)
+	_, err = global.ReadResource(terraform.ReadResourceArgs{Ty: "aws_iam_role", ID: "admin"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"foo": "bar", "alias": "111111111111/eu-west-3"}, reader.args[0].Attributes)
+	assert.Equal(t, map[string]string{"alias": "111111111111/us-west-2"}, reader.args[1].Attributes)
+	assert.Equal(t, map[string]string{"alias": "111111111111/us-east-1"}, reader.args[2].Attributes)
+	// Caller attributes must be left untouched
+	assert.Equal(t, map[string]string{"foo": "bar"}, attributes)
+}
+
+func TestAWSTerraformProvider_providerConfig(t *testing.T) {
+	p := &AWSTerraformProvider{
+		assumeRoleARN:        "arn:aws:iam::{account_id}:role/driftctl",
+		assumeRoleExternalID: "external",
+	}
+
+	assert.Equal(t, awsConfig{Region: "eu-west-3", MaxRetries: 10}, p.providerConfig("eu-west-3"))
+	assert.Equal(t, awsConfig{
+		Region:     "eu-west-3",
+		MaxRetries: 10,
+		AssumeRole: []awsAssumeRoleConfig{
+			{RoleARN: "arn:aws:iam::111111111111:role/driftctl", ExternalID: "external"},
+		},
+		AllowedAccountIds: []string{"111111111111"},
+	}, p.providerConfig("111111111111/eu-west-3"))
+}
+
+func Test_resolveAccounts(t *testing.T) {
+	listingErr := errors.New("not in an organization")
+
+	tests := []struct {
+		name    string
+		opts    Options
+		mocks   func(repo *repository.MockOrganizationsRepository)
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "explicit accounts are sorted and deduplicated",
+			opts: Options{Accounts: []string{"222222222222", "111111111111", "222222222222"}},
+			want: []string{"111111111111", "222222222222"},
+		},
+		{
+			name: "active accounts of the organization",
+			opts: Options{Accounts: []string{"333333333333"}, DiscoverAccounts: true},
+			mocks: func(repo *repository.MockOrganizationsRepository) {
+				repo.On("ListAllAccounts").Return([]*organizations.Account{
+					{Id: awssdk.String("222222222222"), Status: awssdk.String(organizations.AccountStatusActive)},
+					{Id: awssdk.String("111111111111"), Status: awssdk.String(organizations.AccountStatusSuspended)},
+					{Id: awssdk.String("333333333333"), Status: awssdk.String(organizations.AccountStatusActive)},
+				}, nil)
+			},
+			want: []string{"222222222222", "333333333333"},
+		},
+		{
+			name:    "invalid account",
+			opts:    Options{Accounts: []string{"prod"}},
+			wantErr: "invalid AWS account ID 'prod'",
+		},
+		{
+			name: "cannot list accounts",
+			opts: Options{DiscoverAccounts: true},
+			mocks: func(repo *repository.MockOrganizationsRepository) {
+				repo.On("ListAllAccounts").Return(nil, listingErr)
+			},
+			wantErr: "unable to list accounts of the AWS organization: not in an organization",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository.MockOrganizationsRepository{}
+			if tt.mocks != nil {
+				tt.mocks(repo)
+			}
+			got, err := resolveAccounts(repo, tt.opts)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+			repo.AssertExpectations(t)
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package aws
 
 import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/pkg/errors"
 	"github.com/snyk/driftctl/pkg/alerter"
 	"github.com/snyk/driftctl/pkg/output"
 	"github.com/snyk/driftctl/pkg/remote/aws/client"
@@ -12,6 +16,25 @@ import (
 	"github.com/snyk/driftctl/pkg/terraform"
 )
 
+// AllRegions can be given in place of a region list to scan every region enabled for the account
+const AllRegions = "all"
+
+type Options struct {
+	// Regions to scan, defaults to the region of the session
+	Regions []string
+	// Accounts to scan by assuming AssumeRoleARN in each of them, defaults to the account of the session
+	Accounts []string
+	// DiscoverAccounts adds every active account of the AWS organization of the session to the scanned accounts
+	DiscoverAccounts bool
+	// AssumeRoleARN is the role assumed in every scanned account, AccountIDPlaceholder is replaced by the account ID
+	AssumeRoleARN        string
+	AssumeRoleExternalID string
+}
+
+func (o Options) IsMultiAccount() bool {
+	return len(o.Accounts) > 0 || o.DiscoverAccounts
+}
+
 /**
  * Initialize remote (configure credentials, launch tf providers and start gRPC clients)
  * Required to use Scanner
@@ -23,210 +46,68 @@ func Init(version string, alerter *alerter.Alerter,
 	progress output.Progress,
 	resourceSchemaRepository *resource.SchemaRepository,
 	factory resource.ResourceFactory,
-	configDir string) error {
+	configDir string,
+	opts Options) error {
 
 	provider, err := NewAWSTerraformProvider(version, progress, configDir)
 	if err != nil {
 		return err
 	}
+	provider.assumeRoleARN = opts.AssumeRoleARN
+	provider.assumeRoleExternalID = opts.AssumeRoleExternalID
 	err = provider.Init()
 	if err != nil {
 		return err
 	}
 
-	repositoryCache := cache.New(100)
-
-	s3Repository := repository.NewS3Repository(client.NewAWSClientFactory(provider.session), repositoryCache)
-	ec2repository := repository.NewEC2Repository(provider.session, repositoryCache)
-	route53repository := repository.NewRoute53Repository(provider.session, repositoryCache)
-	lambdaRepository := repository.NewLambdaRepository(provider.session, repositoryCache)
-	rdsRepository := repository.NewRDSRepository(provider.session, repositoryCache)
-	sqsRepository := repository.NewSQSRepository(provider.session, repositoryCache)
-	snsRepository := repository.NewSNSRepository(provider.session, repositoryCache)
-	cloudfrontRepository := repository.NewCloudfrontRepository(provider.session, repositoryCache)
-	dynamoDBRepository := repository.NewDynamoDBRepository(provider.session, repositoryCache)
-	ecrRepository := repository.NewECRRepository(provider.session, repositoryCache)
-	kmsRepository := repository.NewKMSRepository(provider.session, repositoryCache)
-	iamRepository := repository.NewIAMRepository(provider.session, repositoryCache)
-	cloudformationRepository := repository.NewCloudformationRepository(provider.session, repositoryCache)
-	apigatewayRepository := repository.NewApiGatewayRepository(provider.session, repositoryCache)
-	appAutoScalingRepository := repository.NewAppAutoScalingRepository(provider.session, repositoryCache)
-	apigatewayv2Repository := repository.NewApiGatewayV2Repository(provider.session, repositoryCache)
-	autoscalingRepository := repository.NewAutoScalingRepository(provider.session, repositoryCache)
-
 	deserializer := resource.NewDeserializer(factory)
 	providerLibrary.AddProvider(terraform.AWS, provider)
 
-	remoteLibrary.AddEnumerator(NewS3BucketEnumerator(s3Repository, factory, provider.Config, alerter))
-	remoteLibrary.AddDetailsFetcher(aws.AwsS3BucketResourceType, common.NewGenericDetailsFetcher(aws.AwsS3BucketResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewS3BucketInventoryEnumerator(s3Repository, factory, provider.Config, alerter))
-	remoteLibrary.AddDetailsFetcher(aws.AwsS3BucketInventoryResourceType, common.NewGenericDetailsFetcher(aws.AwsS3BucketInventoryResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewS3BucketNotificationEnumerator(s3Repository, factory, provider.Config, alerter))
-	remoteLibrary.AddDetailsFetcher(aws.AwsS3BucketNotificationResourceType, common.NewGenericDetailsFetcher(aws.AwsS3BucketNotificationResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewS3BucketMetricsEnumerator(s3Repository, factory, provider.Config, alerter))
-	remoteLibrary.AddDetailsFetcher(aws.AwsS3BucketMetricResourceType, common.NewGenericDetailsFetcher(aws.AwsS3BucketMetricResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewS3BucketPolicyEnumerator(s3Repository, factory, provider.Config, alerter))
-	remoteLibrary.AddDetailsFetcher(aws.AwsS3BucketPolicyResourceType, common.NewGenericDetailsFetcher(aws.AwsS3BucketPolicyResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewS3BucketAnalyticEnumerator(s3Repository, factory, provider.Config, alerter))
-	remoteLibrary.AddDetailsFetcher(aws.AwsS3BucketAnalyticsConfigurationResourceType, common.NewGenericDetailsFetcher(aws.AwsS3BucketAnalyticsConfigurationResourceType, provider, deserializer))
-
-	remoteLibrary.AddEnumerator(NewEC2EbsVolumeEnumerator(ec2repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsEbsVolumeResourceType, common.NewGenericDetailsFetcher(aws.AwsEbsVolumeResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewEC2EbsSnapshotEnumerator(ec2repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsEbsSnapshotResourceType, common.NewGenericDetailsFetcher(aws.AwsEbsSnapshotResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewEC2EipEnumerator(ec2repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsEipResourceType, common.NewGenericDetailsFetcher(aws.AwsEipResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewEC2AmiEnumerator(ec2repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsAmiResourceType, common.NewGenericDetailsFetcher(aws.AwsAmiResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewEC2KeyPairEnumerator(ec2repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsKeyPairResourceType, common.NewGenericDetailsFetcher(aws.AwsKeyPairResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewEC2EipAssociationEnumerator(ec2repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsEipAssociationResourceType, common.NewGenericDetailsFetcher(aws.AwsEipAssociationResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewEC2InstanceEnumerator(ec2repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsInstanceResourceType, common.NewGenericDetailsFetcher(aws.AwsInstanceResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewEC2InternetGatewayEnumerator(ec2repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsInternetGatewayResourceType, common.NewGenericDetailsFetcher(aws.AwsInternetGatewayResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewVPCEnumerator(ec2repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsVpcResourceType, common.NewGenericDetailsFetcher(aws.AwsVpcResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewDefaultVPCEnumerator(ec2repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsDefaultVpcResourceType, common.NewGenericDetailsFetcher(aws.AwsDefaultVpcResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewEC2RouteTableEnumerator(ec2repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsRouteTableResourceType, common.NewGenericDetailsFetcher(aws.AwsRouteTableResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewEC2DefaultRouteTableEnumerator(ec2repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsDefaultRouteTableResourceType, common.NewGenericDetailsFetcher(aws.AwsDefaultRouteTableResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewEC2RouteTableAssociationEnumerator(ec2repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsRouteTableAssociationResourceType, common.NewGenericDetailsFetcher(aws.AwsRouteTableAssociationResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewEC2SubnetEnumerator(ec2repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsSubnetResourceType, common.NewGenericDetailsFetcher(aws.AwsSubnetResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewEC2DefaultSubnetEnumerator(ec2repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsDefaultSubnetResourceType, common.NewGenericDetailsFetcher(aws.AwsDefaultSubnetResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewVPCSecurityGroupEnumerator(ec2repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsSecurityGroupResourceType, common.NewGenericDetailsFetcher(aws.AwsSecurityGroupResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewVPCDefaultSecurityGroupEnumerator(ec2repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsDefaultSecurityGroupResourceType, common.NewGenericDetailsFetcher(aws.AwsDefaultSecurityGroupResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewEC2NatGatewayEnumerator(ec2repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsNatGatewayResourceType, common.NewGenericDetailsFetcher(aws.AwsNatGatewayResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewEC2NetworkACLEnumerator(ec2repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsNetworkACLResourceType, common.NewGenericDetailsFetcher(aws.AwsNetworkACLResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewEC2NetworkACLRuleEnumerator(ec2repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsNetworkACLRuleResourceType, common.NewGenericDetailsFetcher(aws.AwsNetworkACLRuleResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewEC2DefaultNetworkACLEnumerator(ec2repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsDefaultNetworkACLResourceType, common.NewGenericDetailsFetcher(aws.AwsDefaultNetworkACLResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewEC2RouteEnumerator(ec2repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsRouteResourceType, common.NewGenericDetailsFetcher(aws.AwsRouteResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewVPCSecurityGroupRuleEnumerator(ec2repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsSecurityGroupRuleResourceType, common.NewGenericDetailsFetcher(aws.AwsSecurityGroupRuleResourceType, provider, deserializer))
-
-	remoteLibrary.AddEnumerator(NewKMSKeyEnumerator(kmsRepository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsKmsKeyResourceType, common.NewGenericDetailsFetcher(aws.AwsKmsKeyResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewKMSAliasEnumerator(kmsRepository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsKmsAliasResourceType, common.NewGenericDetailsFetcher(aws.AwsKmsAliasResourceType, provider, deserializer))
-
-	remoteLibrary.AddEnumerator(NewRoute53HealthCheckEnumerator(route53repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsRoute53HealthCheckResourceType, common.NewGenericDetailsFetcher(aws.AwsRoute53HealthCheckResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewRoute53ZoneEnumerator(route53repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsRoute53ZoneResourceType, common.NewGenericDetailsFetcher(aws.AwsRoute53ZoneResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewRoute53RecordEnumerator(route53repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsRoute53RecordResourceType, common.NewGenericDetailsFetcher(aws.AwsRoute53RecordResourceType, provider, deserializer))
-
-	remoteLibrary.AddEnumerator(NewCloudfrontDistributionEnumerator(cloudfrontRepository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsCloudfrontDistributionResourceType, common.NewGenericDetailsFetcher(aws.AwsCloudfrontDistributionResourceType, provider, deserializer))
-
-	remoteLibrary.AddEnumerator(NewRDSDBInstanceEnumerator(rdsRepository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsDbInstanceResourceType, common.NewGenericDetailsFetcher(aws.AwsDbInstanceResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewRDSDBSubnetGroupEnumerator(rdsRepository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsDbSubnetGroupResourceType, common.NewGenericDetailsFetcher(aws.AwsDbSubnetGroupResourceType, provider, deserializer))
-
-	remoteLibrary.AddEnumerator(NewSQSQueueEnumerator(sqsRepository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsSqsQueueResourceType, NewSQSQueueDetailsFetcher(provider, deserializer))
-	remoteLibrary.AddEnumerator(NewSQSQueuePolicyEnumerator(sqsRepository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsSqsQueuePolicyResourceType, common.NewGenericDetailsFetcher(aws.AwsSqsQueuePolicyResourceType, provider, deserializer))
-
-	remoteLibrary.AddEnumerator(NewSNSTopicEnumerator(snsRepository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsSnsTopicResourceType, common.NewGenericDetailsFetcher(aws.AwsSnsTopicResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewSNSTopicPolicyEnumerator(snsRepository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsSnsTopicPolicyResourceType, common.NewGenericDetailsFetcher(aws.AwsSnsTopicPolicyResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewSNSTopicSubscriptionEnumerator(snsRepository, factory, alerter))
-	remoteLibrary.AddDetailsFetcher(aws.AwsSnsTopicSubscriptionResourceType, common.NewGenericDetailsFetcher(aws.AwsSnsTopicSubscriptionResourceType, provider, deserializer))
-
-	remoteLibrary.AddEnumerator(NewDynamoDBTableEnumerator(dynamoDBRepository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsDynamodbTableResourceType, common.NewGenericDetailsFetcher(aws.AwsDynamodbTableResourceType, provider, deserializer))
-
-	remoteLibrary.AddEnumerator(NewIamPolicyEnumerator(iamRepository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsIamPolicyResourceType, common.NewGenericDetailsFetcher(aws.AwsIamPolicyResourceType, provider, deserializer))
-
-	remoteLibrary.AddEnumerator(NewLambdaFunctionEnumerator(lambdaRepository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsLambdaFunctionResourceType, common.NewGenericDetailsFetcher(aws.AwsLambdaFunctionResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewLambdaEventSourceMappingEnumerator(lambdaRepository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsLambdaEventSourceMappingResourceType, common.NewGenericDetailsFetcher(aws.AwsLambdaEventSourceMappingResourceType, provider, deserializer))
-
-	remoteLibrary.AddEnumerator(NewIamUserEnumerator(iamRepository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsIamUserResourceType, common.NewGenericDetailsFetcher(aws.AwsIamUserResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewIamUserPolicyEnumerator(iamRepository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsIamUserPolicyResourceType, common.NewGenericDetailsFetcher(aws.AwsIamUserPolicyResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewIamRoleEnumerator(iamRepository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsIamRoleResourceType, common.NewGenericDetailsFetcher(aws.AwsIamRoleResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewIamAccessKeyEnumerator(iamRepository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsIamAccessKeyResourceType, common.NewGenericDetailsFetcher(aws.AwsIamAccessKeyResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewIamRolePolicyAttachmentEnumerator(iamRepository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsIamRolePolicyAttachmentResourceType, common.NewGenericDetailsFetcher(aws.AwsIamRolePolicyAttachmentResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewIamRolePolicyEnumerator(iamRepository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsIamRolePolicyResourceType, common.NewGenericDetailsFetcher(aws.AwsIamRolePolicyResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewIamUserPolicyAttachmentEnumerator(iamRepository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsIamUserPolicyAttachmentResourceType, common.NewGenericDetailsFetcher(aws.AwsIamUserPolicyAttachmentResourceType, provider, deserializer))
-
-	remoteLibrary.AddEnumerator(NewECRRepositoryEnumerator(ecrRepository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsEcrRepositoryResourceType, common.NewGenericDetailsFetcher(aws.AwsEcrRepositoryResourceType, provider, deserializer))
-
-	remoteLibrary.AddEnumerator(NewRDSClusterEnumerator(rdsRepository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsRDSClusterResourceType, common.NewGenericDetailsFetcher(aws.AwsRDSClusterResourceType, provider, deserializer))
-
-	remoteLibrary.AddEnumerator(NewCloudformationStackEnumerator(cloudformationRepository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsCloudformationStackResourceType, common.NewGenericDetailsFetcher(aws.AwsCloudformationStackResourceType, provider, deserializer))
-
-	remoteLibrary.AddEnumerator(NewApiGatewayRestApiEnumerator(apigatewayRepository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayAccountEnumerator(apigatewayRepository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayApiKeyEnumerator(apigatewayRepository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayAuthorizerEnumerator(apigatewayRepository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayStageEnumerator(apigatewayRepository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayResourceEnumerator(apigatewayRepository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayDomainNameEnumerator(apigatewayRepository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayVpcLinkEnumerator(apigatewayRepository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayRequestValidatorEnumerator(apigatewayRepository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayRestApiPolicyEnumerator(apigatewayRepository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayBasePathMappingEnumerator(apigatewayRepository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayMethodEnumerator(apigatewayRepository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayModelEnumerator(apigatewayRepository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayMethodResponseEnumerator(apigatewayRepository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayGatewayResponseEnumerator(apigatewayRepository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayMethodSettingsEnumerator(apigatewayRepository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayIntegrationEnumerator(apigatewayRepository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayIntegrationResponseEnumerator(apigatewayRepository, factory))
-
-	remoteLibrary.AddEnumerator(NewApiGatewayV2ApiEnumerator(apigatewayv2Repository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayV2RouteEnumerator(apigatewayv2Repository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayV2DeploymentEnumerator(apigatewayv2Repository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayV2VpcLinkEnumerator(apigatewayv2Repository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayV2AuthorizerEnumerator(apigatewayv2Repository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayV2IntegrationEnumerator(apigatewayv2Repository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayV2ModelEnumerator(apigatewayv2Repository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayV2StageEnumerator(apigatewayv2Repository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayV2RouteResponseEnumerator(apigatewayv2Repository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayV2MappingEnumerator(apigatewayv2Repository, apigatewayRepository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayV2DomainNameEnumerator(apigatewayRepository, factory))
-	remoteLibrary.AddEnumerator(NewApiGatewayV2IntegrationResponseEnumerator(apigatewayv2Repository, factory))
-
-	remoteLibrary.AddEnumerator(NewAppAutoscalingTargetEnumerator(appAutoScalingRepository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsAppAutoscalingTargetResourceType, common.NewGenericDetailsFetcher(aws.AwsAppAutoscalingTargetResourceType, provider, deserializer))
-
-	remoteLibrary.AddEnumerator(NewAppAutoscalingPolicyEnumerator(appAutoScalingRepository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsAppAutoscalingPolicyResourceType, common.NewGenericDetailsFetcher(aws.AwsAppAutoscalingPolicyResourceType, provider, deserializer))
-
-	remoteLibrary.AddEnumerator(NewAppAutoscalingScheduledActionEnumerator(appAutoScalingRepository, factory))
-
-	remoteLibrary.AddEnumerator(NewLaunchTemplateEnumerator(ec2repository, factory))
-	remoteLibrary.AddDetailsFetcher(aws.AwsLaunchTemplateResourceType, common.NewGenericDetailsFetcher(aws.AwsLaunchTemplateResourceType, provider, deserializer))
-	remoteLibrary.AddEnumerator(NewLaunchConfigurationEnumerator(autoscalingRepository, factory))
+	if !opts.IsMultiAccount() {
+		err = initAccount(provider.session, provider, provider, opts.Regions, remoteLibrary, factory, deserializer, alerter)
+		if err != nil {
+			return err
+		}
+	} else {
+		accounts, err := resolveAccounts(repository.NewOrganizationsRepository(provider.session, cache.New(1)), opts)
+		if err != nil {
+			return err
+		}
+
+		accountDetailsFetchers := make(map[resource.ResourceType]*AccountDetailsFetcher)
+		for _, account := range accounts {
+			roleARN := roleARNForAccount(opts.AssumeRoleARN, account)
+			sess := provider.session.Copy(&awssdk.Config{
+				Credentials: stscreds.NewCredentials(provider.session, roleARN, func(p *stscreds.AssumeRoleProvider) {
+					if opts.AssumeRoleExternalID != "" {
+						p.ExternalID = awssdk.String(opts.AssumeRoleExternalID)
+					}
+				}),
+			})
+			reader := newAccountResourceReader(provider, account, provider.Config.DefaultAlias)
+
+			accountLibrary := common.NewRemoteLibrary()
+			err = initAccount(sess, provider, reader, opts.Regions, accountLibrary, factory, deserializer, alerter)
+			if err != nil {
+				return errors.Wrapf(err, "unable to initialize account %s with role %s", account, roleARN)
+			}
+
+			for _, enumerator := range accountLibrary.Enumerators() {
+				remoteLibrary.AddEnumerator(NewAccountEnumerator(account, enumerator))
+
+				ty := enumerator.SupportedType()
+				fetcher := accountLibrary.GetDetailsFetcher(ty)
+				if fetcher == nil {
+					continue
+				}
+				if _, exist := accountDetailsFetchers[ty]; !exist {
+					accountDetailsFetchers[ty] = NewAccountDetailsFetcher()
+					remoteLibrary.AddDetailsFetcher(ty, accountDetailsFetchers[ty])
+				}
+				accountDetailsFetchers[ty].AddDetailsFetcher(account, fetcher)
+			}
+		}
+	}
 
 	err = resourceSchemaRepository.Init(terraform.AWS, provider.Version(), provider.Schema())
 	if err != nil {
@@ -236,3 +117,257 @@ func Init(version string, alerter *alerter.Alerter,
 
 	return nil
 }
+
+// initAccount registers enumerators and details fetchers of every scanned region and of global services
+// for the account of the given session, resources details are read through the given reader
+func initAccount(sess *session.Session, provider *AWSTerraformProvider,
+	reader terraform.ResourceReader,
+	regionsOpt []string,
+	library *common.RemoteLibrary,
+	factory resource.ResourceFactory,
+	deserializer *resource.Deserializer,
+	alerter *alerter.Alerter) error {
+
+	repositoryCache := cache.New(100)
+
+	regions, err := resolveRegions(repository.NewEC2Repository(sess, repositoryCache), provider.Config.DefaultAlias, regionsOpt)
+	if err != nil {
+		return err
+	}
+
+	// Global services are scanned once, S3 buckets are listed globally but located in a region
+	s3Repository := repository.NewS3Repository(client.NewAWSClientFactory(sess), repositoryCache)
+	route53repository := repository.NewRoute53Repository(sess, repositoryCache)
+	cloudfrontRepository := repository.NewCloudfrontRepository(sess, repositoryCache)
+	iamRepository := repository.NewIAMRepository(sess, repositoryCache)
+
+	regionalDetailsFetchers := make(map[resource.ResourceType]*RegionalDetailsFetcher)
+	for _, region := range regions {
+		regionalSession := sess
+		if region != provider.Config.DefaultAlias {
+			regionalSession = sess.Copy(&awssdk.Config{Region: awssdk.String(region)})
+		}
+
+		regionalLibrary := common.NewRemoteLibrary()
+		initRegion(region, regionalSession, provider, reader, s3Repository, regionalLibrary, factory, deserializer, alerter)
+
+		for _, enumerator := range regionalLibrary.Enumerators() {
+			library.AddEnumerator(NewRegionalEnumerator(region, enumerator))
+
+			ty := enumerator.SupportedType()
+			fetcher := regionalLibrary.GetDetailsFetcher(ty)
+			if fetcher == nil {
+				continue
+			}
+			if _, exist := regionalDetailsFetchers[ty]; !exist {
+				regionalDetailsFetchers[ty] = NewRegionalDetailsFetcher()
+				library.AddDetailsFetcher(ty, regionalDetailsFetchers[ty])
+			}
+			regionalDetailsFetchers[ty].AddDetailsFetcher(region, fetcher)
+		}
+	}
+
+	library.AddEnumerator(NewRoute53HealthCheckEnumerator(route53repository, factory))
+	library.AddDetailsFetcher(aws.AwsRoute53HealthCheckResourceType, common.NewGenericDetailsFetcher(aws.AwsRoute53HealthCheckResourceType, reader, deserializer))
+	library.AddEnumerator(NewRoute53ZoneEnumerator(route53repository, factory))
+	library.AddDetailsFetcher(aws.AwsRoute53ZoneResourceType, common.NewGenericDetailsFetcher(aws.AwsRoute53ZoneResourceType, reader, deserializer))
+	library.AddEnumerator(NewRoute53RecordEnumerator(route53repository, factory))
+	library.AddDetailsFetcher(aws.AwsRoute53RecordResourceType, common.NewGenericDetailsFetcher(aws.AwsRoute53RecordResourceType, reader, deserializer))
+
+	library.AddEnumerator(NewCloudfrontDistributionEnumerator(cloudfrontRepository, factory))
+	library.AddDetailsFetcher(aws.AwsCloudfrontDistributionResourceType, common.NewGenericDetailsFetcher(aws.AwsCloudfrontDistributionResourceType, reader, deserializer))
+
+	library.AddEnumerator(NewIamPolicyEnumerator(iamRepository, factory))
+	library.AddDetailsFetcher(aws.AwsIamPolicyResourceType, common.NewGenericDetailsFetcher(aws.AwsIamPolicyResourceType, reader, deserializer))
+
+	library.AddEnumerator(NewIamUserEnumerator(iamRepository, factory))
+	library.AddDetailsFetcher(aws.AwsIamUserResourceType, common.NewGenericDetailsFetcher(aws.AwsIamUserResourceType, reader, deserializer))
+	library.AddEnumerator(NewIamUserPolicyEnumerator(iamRepository, factory))
+	library.AddDetailsFetcher(aws.AwsIamUserPolicyResourceType, common.NewGenericDetailsFetcher(aws.AwsIamUserPolicyResourceType, reader, deserializer))
+	library.AddEnumerator(NewIamRoleEnumerator(iamRepository, factory))
+	library.AddDetailsFetcher(aws.AwsIamRoleResourceType, common.NewGenericDetailsFetcher(aws.AwsIamRoleResourceType, reader, deserializer))
+	library.AddEnumerator(NewIamAccessKeyEnumerator(iamRepository, factory))
+	library.AddDetailsFetcher(aws.AwsIamAccessKeyResourceType, common.NewGenericDetailsFetcher(aws.AwsIamAccessKeyResourceType, reader, deserializer))
+	library.AddEnumerator(NewIamRolePolicyAttachmentEnumerator(iamRepository, factory))
+	library.AddDetailsFetcher(aws.AwsIamRolePolicyAttachmentResourceType, common.NewGenericDetailsFetcher(aws.AwsIamRolePolicyAttachmentResourceType, reader, deserializer))
+	library.AddEnumerator(NewIamRolePolicyEnumerator(iamRepository, factory))
+	library.AddDetailsFetcher(aws.AwsIamRolePolicyResourceType, common.NewGenericDetailsFetcher(aws.AwsIamRolePolicyResourceType, reader, deserializer))
+	library.AddEnumerator(NewIamUserPolicyAttachmentEnumerator(iamRepository, factory))
+	library.AddDetailsFetcher(aws.AwsIamUserPolicyAttachmentResourceType, common.NewGenericDetailsFetcher(aws.AwsIamUserPolicyAttachmentResourceType, reader, deserializer))
+
+	return nil
+}
+
+// initRegion registers enumerators and details fetchers of regional services for the given region
+func initRegion(region string, sess *session.Session, provider *AWSTerraformProvider,
+	accountReader terraform.ResourceReader,
+	s3Repository repository.S3Repository,
+	library *common.RemoteLibrary,
+	factory resource.ResourceFactory,
+	deserializer *resource.Deserializer,
+	alerter *alerter.Alerter) {
+
+	// Repositories cache keys are not namespaced by region, so each region needs its own cache
+	repositoryCache := cache.New(100)
+
+	regionalConfig := provider.Config
+	regionalConfig.DefaultAlias = region
+	reader := newRegionalResourceReader(accountReader, region)
+
+	ec2repository := repository.NewEC2Repository(sess, repositoryCache)
+	lambdaRepository := repository.NewLambdaRepository(sess, repositoryCache)
+	rdsRepository := repository.NewRDSRepository(sess, repositoryCache)
+	sqsRepository := repository.NewSQSRepository(sess, repositoryCache)
+	snsRepository := repository.NewSNSRepository(sess, repositoryCache)
+	dynamoDBRepository := repository.NewDynamoDBRepository(sess, repositoryCache)
+	ecrRepository := repository.NewECRRepository(sess, repositoryCache)
+	kmsRepository := repository.NewKMSRepository(sess, repositoryCache)
+	cloudformationRepository := repository.NewCloudformationRepository(sess, repositoryCache)
+	apigatewayRepository := repository.NewApiGatewayRepository(sess, repositoryCache)
+	appAutoScalingRepository := repository.NewAppAutoScalingRepository(sess, repositoryCache)
+	apigatewayv2Repository := repository.NewApiGatewayV2Repository(sess, repositoryCache)
+	autoscalingRepository := repository.NewAutoScalingRepository(sess, repositoryCache)
+
+	library.AddEnumerator(NewS3BucketEnumerator(s3Repository, factory, regionalConfig, alerter))
+	library.AddDetailsFetcher(aws.AwsS3BucketResourceType, common.NewGenericDetailsFetcher(aws.AwsS3BucketResourceType, reader, deserializer))
+	library.AddEnumerator(NewS3BucketInventoryEnumerator(s3Repository, factory, regionalConfig, alerter))
+	library.AddDetailsFetcher(aws.AwsS3BucketInventoryResourceType, common.NewGenericDetailsFetcher(aws.AwsS3BucketInventoryResourceType, reader, deserializer))
+	library.AddEnumerator(NewS3BucketNotificationEnumerator(s3Repository, factory, regionalConfig, alerter))
+	library.AddDetailsFetcher(aws.AwsS3BucketNotificationResourceType, common.NewGenericDetailsFetcher(aws.AwsS3BucketNotificationResourceType, reader, deserializer))
+	library.AddEnumerator(NewS3BucketMetricsEnumerator(s3Repository, factory, regionalConfig, alerter))
+	library.AddDetailsFetcher(aws.AwsS3BucketMetricResourceType, common.NewGenericDetailsFetcher(aws.AwsS3BucketMetricResourceType, reader, deserializer))
+	library.AddEnumerator(NewS3BucketPolicyEnumerator(s3Repository, factory, regionalConfig, alerter))
+	library.AddDetailsFetcher(aws.AwsS3BucketPolicyResourceType, common.NewGenericDetailsFetcher(aws.AwsS3BucketPolicyResourceType, reader, deserializer))
+	library.AddEnumerator(NewS3BucketAnalyticEnumerator(s3Repository, factory, regionalConfig, alerter))
+	library.AddDetailsFetcher(aws.AwsS3BucketAnalyticsConfigurationResourceType, common.NewGenericDetailsFetcher(aws.AwsS3BucketAnalyticsConfigurationResourceType, reader, deserializer))
+
+	library.AddEnumerator(NewEC2EbsVolumeEnumerator(ec2repository, factory))
+	library.AddDetailsFetcher(aws.AwsEbsVolumeResourceType, common.NewGenericDetailsFetcher(aws.AwsEbsVolumeResourceType, reader, deserializer))
+	library.AddEnumerator(NewEC2EbsSnapshotEnumerator(ec2repository, factory))
+	library.AddDetailsFetcher(aws.AwsEbsSnapshotResourceType, common.NewGenericDetailsFetcher(aws.AwsEbsSnapshotResourceType, reader, deserializer))
+	library.AddEnumerator(NewEC2EipEnumerator(ec2repository, factory))
+	library.AddDetailsFetcher(aws.AwsEipResourceType, common.NewGenericDetailsFetcher(aws.AwsEipResourceType, reader, deserializer))
+	library.AddEnumerator(NewEC2AmiEnumerator(ec2repository, factory))
+	library.AddDetailsFetcher(aws.AwsAmiResourceType, common.NewGenericDetailsFetcher(aws.AwsAmiResourceType, reader, deserializer))
+	library.AddEnumerator(NewEC2KeyPairEnumerator(ec2repository, factory))
+	library.AddDetailsFetcher(aws.AwsKeyPairResourceType, common.NewGenericDetailsFetcher(aws.AwsKeyPairResourceType, reader, deserializer))
+	library.AddEnumerator(NewEC2EipAssociationEnumerator(ec2repository, factory))
+	library.AddDetailsFetcher(aws.AwsEipAssociationResourceType, common.NewGenericDetailsFetcher(aws.AwsEipAssociationResourceType, reader, deserializer))
+	library.AddEnumerator(NewEC2InstanceEnumerator(ec2repository, factory))
+	library.AddDetailsFetcher(aws.AwsInstanceResourceType, common.NewGenericDetailsFetcher(aws.AwsInstanceResourceType, reader, deserializer))
+	library.AddEnumerator(NewEC2InternetGatewayEnumerator(ec2repository, factory))
+	library.AddDetailsFetcher(aws.AwsInternetGatewayResourceType, common.NewGenericDetailsFetcher(aws.AwsInternetGatewayResourceType, reader, deserializer))
+	library.AddEnumerator(NewVPCEnumerator(ec2repository, factory))
+	library.AddDetailsFetcher(aws.AwsVpcResourceType, common.NewGenericDetailsFetcher(aws.AwsVpcResourceType, reader, deserializer))
+	library.AddEnumerator(NewDefaultVPCEnumerator(ec2repository, factory))
+	library.AddDetailsFetcher(aws.AwsDefaultVpcResourceType, common.NewGenericDetailsFetcher(aws.AwsDefaultVpcResourceType, reader, deserializer))
+	library.AddEnumerator(NewEC2RouteTableEnumerator(ec2repository, factory))
+	library.AddDetailsFetcher(aws.AwsRouteTableResourceType, common.NewGenericDetailsFetcher(aws.AwsRouteTableResourceType, reader, deserializer))
+	library.AddEnumerator(NewEC2DefaultRouteTableEnumerator(ec2repository, factory))
+	library.AddDetailsFetcher(aws.AwsDefaultRouteTableResourceType, common.NewGenericDetailsFetcher(aws.AwsDefaultRouteTableResourceType, reader, deserializer))
+	library.AddEnumerator(NewEC2RouteTableAssociationEnumerator(ec2repository, factory))
+	library.AddDetailsFetcher(aws.AwsRouteTableAssociationResourceType, common.NewGenericDetailsFetcher(aws.AwsRouteTableAssociationResourceType, reader, deserializer))
+	library.AddEnumerator(NewEC2SubnetEnumerator(ec2repository, factory))
+	library.AddDetailsFetcher(aws.AwsSubnetResourceType, common.NewGenericDetailsFetcher(aws.AwsSubnetResourceType, reader, deserializer))
+	library.AddEnumerator(NewEC2DefaultSubnetEnumerator(ec2repository, factory))
+	library.AddDetailsFetcher(aws.AwsDefaultSubnetResourceType, common.NewGenericDetailsFetcher(aws.AwsDefaultSubnetResourceType, reader, deserializer))
+	library.AddEnumerator(NewVPCSecurityGroupEnumerator(ec2repository, factory))
+	library.AddDetailsFetcher(aws.AwsSecurityGroupResourceType, common.NewGenericDetailsFetcher(aws.AwsSecurityGroupResourceType, reader, deserializer))
+	library.AddEnumerator(NewVPCDefaultSecurityGroupEnumerator(ec2repository, factory))
+	library.AddDetailsFetcher(aws.AwsDefaultSecurityGroupResourceType, common.NewGenericDetailsFetcher(aws.AwsDefaultSecurityGroupResourceType, reader, deserializer))
+	library.AddEnumerator(NewEC2NatGatewayEnumerator(ec2repository, factory))
+	library.AddDetailsFetcher(aws.AwsNatGatewayResourceType, common.NewGenericDetailsFetcher(aws.AwsNatGatewayResourceType, reader, deserializer))
+	library.AddEnumerator(NewEC2NetworkACLEnumerator(ec2repository, factory))
+	library.AddDetailsFetcher(aws.AwsNetworkACLResourceType, common.NewGenericDetailsFetcher(aws.AwsNetworkACLResourceType, reader, deserializer))
+	library.AddEnumerator(NewEC2NetworkACLRuleEnumerator(ec2repository, factory))
+	library.AddDetailsFetcher(aws.AwsNetworkACLRuleResourceType, common.NewGenericDetailsFetcher(aws.AwsNetworkACLRuleResourceType, reader, deserializer))
+	library.AddEnumerator(NewEC2DefaultNetworkACLEnumerator(ec2repository, factory))
+	library.AddDetailsFetcher(aws.AwsDefaultNetworkACLResourceType, common.NewGenericDetailsFetcher(aws.AwsDefaultNetworkACLResourceType, reader, deserializer))
+	library.AddEnumerator(NewEC2RouteEnumerator(ec2repository, factory))
+	library.AddDetailsFetcher(aws.AwsRouteResourceType, common.NewGenericDetailsFetcher(aws.AwsRouteResourceType, reader, deserializer))
+	library.AddEnumerator(NewVPCSecurityGroupRuleEnumerator(ec2repository, factory))
+	library.AddDetailsFetcher(aws.AwsSecurityGroupRuleResourceType, common.NewGenericDetailsFetcher(aws.AwsSecurityGroupRuleResourceType, reader, deserializer))
+
+	library.AddEnumerator(NewKMSKeyEnumerator(kmsRepository, factory))
+	library.AddDetailsFetcher(aws.AwsKmsKeyResourceType, common.NewGenericDetailsFetcher(aws.AwsKmsKeyResourceType, reader, deserializer))
+	library.AddEnumerator(NewKMSAliasEnumerator(kmsRepository, factory))
+	library.AddDetailsFetcher(aws.AwsKmsAliasResourceType, common.NewGenericDetailsFetcher(aws.AwsKmsAliasResourceType, reader, deserializer))
+
+	library.AddEnumerator(NewRDSDBInstanceEnumerator(rdsRepository, factory))
+	library.AddDetailsFetcher(aws.AwsDbInstanceResourceType, common.NewGenericDetailsFetcher(aws.AwsDbInstanceResourceType, reader, deserializer))
+	library.AddEnumerator(NewRDSDBSubnetGroupEnumerator(rdsRepository, factory))
+	library.AddDetailsFetcher(aws.AwsDbSubnetGroupResourceType, common.NewGenericDetailsFetcher(aws.AwsDbSubnetGroupResourceType, reader, deserializer))
+
+	library.AddEnumerator(NewSQSQueueEnumerator(sqsRepository, factory))
+	library.AddDetailsFetcher(aws.AwsSqsQueueResourceType, NewSQSQueueDetailsFetcher(reader, deserializer))
+	library.AddEnumerator(NewSQSQueuePolicyEnumerator(sqsRepository, factory))
+	library.AddDetailsFetcher(aws.AwsSqsQueuePolicyResourceType, common.NewGenericDetailsFetcher(aws.AwsSqsQueuePolicyResourceType, reader, deserializer))
+
+	library.AddEnumerator(NewSNSTopicEnumerator(snsRepository, factory))
+	library.AddDetailsFetcher(aws.AwsSnsTopicResourceType, common.NewGenericDetailsFetcher(aws.AwsSnsTopicResourceType, reader, deserializer))
+	library.AddEnumerator(NewSNSTopicPolicyEnumerator(snsRepository, factory))
+	library.AddDetailsFetcher(aws.AwsSnsTopicPolicyResourceType, common.NewGenericDetailsFetcher(aws.AwsSnsTopicPolicyResourceType, reader, deserializer))
+	library.AddEnumerator(NewSNSTopicSubscriptionEnumerator(snsRepository, factory, alerter))
+	library.AddDetailsFetcher(aws.AwsSnsTopicSubscriptionResourceType, common.NewGenericDetailsFetcher(aws.AwsSnsTopicSubscriptionResourceType, reader, deserializer))
+
+	library.AddEnumerator(NewDynamoDBTableEnumerator(dynamoDBRepository, factory))
+	library.AddDetailsFetcher(aws.AwsDynamodbTableResourceType, common.NewGenericDetailsFetcher(aws.AwsDynamodbTableResourceType, reader, deserializer))
+
+	library.AddEnumerator(NewLambdaFunctionEnumerator(lambdaRepository, factory))
+	library.AddDetailsFetcher(aws.AwsLambdaFunctionResourceType, common.NewGenericDetailsFetcher(aws.AwsLambdaFunctionResourceType, reader, deserializer))
+	library.AddEnumerator(NewLambdaEventSourceMappingEnumerator(lambdaRepository, factory))
+	library.AddDetailsFetcher(aws.AwsLambdaEventSourceMappingResourceType, common.NewGenericDetailsFetcher(aws.AwsLambdaEventSourceMappingResourceType, reader, deserializer))
+
+	library.AddEnumerator(NewECRRepositoryEnumerator(ecrRepository, factory))
+	library.AddDetailsFetcher(aws.AwsEcrRepositoryResourceType, common.NewGenericDetailsFetcher(aws.AwsEcrRepositoryResourceType, reader, deserializer))
+
+	library.AddEnumerator(NewRDSClusterEnumerator(rdsRepository, factory))
+	library.AddDetailsFetcher(aws.AwsRDSClusterResourceType, common.NewGenericDetailsFetcher(aws.AwsRDSClusterResourceType, reader, deserializer))
+
+	library.AddEnumerator(NewCloudformationStackEnumerator(cloudformationRepository, factory))
+	library.AddDetailsFetcher(aws.AwsCloudformationStackResourceType, common.NewGenericDetailsFetcher(aws.AwsCloudformationStackResourceType, reader, deserializer))
+
+	library.AddEnumerator(NewApiGatewayRestApiEnumerator(apigatewayRepository, factory))
+	library.AddEnumerator(NewApiGatewayAccountEnumerator(apigatewayRepository, factory))
+	library.AddEnumerator(NewApiGatewayApiKeyEnumerator(apigatewayRepository, factory))
+	library.AddEnumerator(NewApiGatewayAuthorizerEnumerator(apigatewayRepository, factory))
+	library.AddEnumerator(NewApiGatewayStageEnumerator(apigatewayRepository, factory))
+	library.AddEnumerator(NewApiGatewayResourceEnumerator(apigatewayRepository, factory))
+	library.AddEnumerator(NewApiGatewayDomainNameEnumerator(apigatewayRepository, factory))
+	library.AddEnumerator(NewApiGatewayVpcLinkEnumerator(apigatewayRepository, factory))
+	library.AddEnumerator(NewApiGatewayRequestValidatorEnumerator(apigatewayRepository, factory))
+	library.AddEnumerator(NewApiGatewayRestApiPolicyEnumerator(apigatewayRepository, factory))
+	library.AddEnumerator(NewApiGatewayBasePathMappingEnumerator(apigatewayRepository, factory))
+	library.AddEnumerator(NewApiGatewayMethodEnumerator(apigatewayRepository, factory))
+	library.AddEnumerator(NewApiGatewayModelEnumerator(apigatewayRepository, factory))
+	library.AddEnumerator(NewApiGatewayMethodResponseEnumerator(apigatewayRepository, factory))
+	library.AddEnumerator(NewApiGatewayGatewayResponseEnumerator(apigatewayRepository, factory))
+	library.AddEnumerator(NewApiGatewayMethodSettingsEnumerator(apigatewayRepository, factory))
+	library.AddEnumerator(NewApiGatewayIntegrationEnumerator(apigatewayRepository, factory))
+	library.AddEnumerator(NewApiGatewayIntegrationResponseEnumerator(apigatewayRepository, factory))
+
+	library.AddEnumerator(NewApiGatewayV2ApiEnumerator(apigatewayv2Repository, factory))
+	library.AddEnumerator(NewApiGatewayV2RouteEnumerator(apigatewayv2Repository, factory))
+	library.AddEnumerator(NewApiGatewayV2DeploymentEnumerator(apigatewayv2Repository, factory))
+	library.AddEnumerator(NewApiGatewayV2VpcLinkEnumerator(apigatewayv2Repository, factory))
+	library.AddEnumerator(NewApiGatewayV2AuthorizerEnumerator(apigatewayv2Repository, factory))
+	library.AddEnumerator(NewApiGatewayV2IntegrationEnumerator(apigatewayv2Repository, factory))
+	library.AddEnumerator(NewApiGatewayV2ModelEnumerator(apigatewayv2Repository, factory))
+	library.AddEnumerator(NewApiGatewayV2StageEnumerator(apigatewayv2Repository, factory))
+	library.AddEnumerator(NewApiGatewayV2RouteResponseEnumerator(apigatewayv2Repository, factory))
+	library.AddEnumerator(NewApiGatewayV2MappingEnumerator(apigatewayv2Repository, apigatewayRepository, factory))
+	library.AddEnumerator(NewApiGatewayV2DomainNameEnumerator(apigatewayRepository, factory))
+	library.AddEnumerator(NewApiGatewayV2IntegrationResponseEnumerator(apigatewayv2Repository, factory))
+
+	library.AddEnumerator(NewAppAutoscalingTargetEnumerator(appAutoScalingRepository, factory))
+	library.AddDetailsFetcher(aws.AwsAppAutoscalingTargetResourceType, common.NewGenericDetailsFetcher(aws.AwsAppAutoscalingTargetResourceType, reader, deserializer))
+
+	library.AddEnumerator(NewAppAutoscalingPolicyEnumerator(appAutoScalingRepository, factory))
+	library.AddDetailsFetcher(aws.AwsAppAutoscalingPolicyResourceType, common.NewGenericDetailsFetcher(aws.AwsAppAutoscalingPolicyResourceType, reader, deserializer))
+
+	library.AddEnumerator(NewAppAutoscalingScheduledActionEnumerator(appAutoScalingRepository, factory))
+
+	library.AddEnumerator(NewLaunchTemplateEnumerator(ec2repository, factory))
+	library.AddDetailsFetcher(aws.AwsLaunchTemplateResourceType, common.NewGenericDetailsFetcher(aws.AwsLaunchTemplateResourceType, reader, deserializer))
+	library.AddEnumerator(NewLaunchConfigurationEnumerator(autoscalingRepository, factory))
+}
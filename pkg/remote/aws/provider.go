@@ -16,12 +16,9 @@ type awsConfig struct {
 	Region        string `cty:"region"`
 	MaxRetries    int
 
-	AssumeRoleARN         string
-	AssumeRoleExternalID  string
-	AssumeRoleSessionName string
-	AssumeRolePolicy      string
+	AssumeRole []awsAssumeRoleConfig `cty:"assume_role"`
 
-	AllowedAccountIds   []string
+	AllowedAccountIds   []string `cty:"allowed_account_ids"`
 	ForbiddenAccountIds []string
 
 	Endpoints        map[string]string
@@ -36,11 +33,21 @@ type awsConfig struct {
 	S3ForcePathStyle        bool
 }
 
+type awsAssumeRoleConfig struct {
+	RoleARN     string `cty:"role_arn"`
+	ExternalID  string `cty:"external_id"`
+	SessionName string `cty:"session_name"`
+	Policy      string `cty:"policy"`
+}
+
 type AWSTerraformProvider struct {
 	*terraform.TerraformProvider
 	session *session.Session
 	name    string
 	version string
+	// Role assumed in scanned accounts, see AccountIDPlaceholder
+	assumeRoleARN        string
+	assumeRoleExternalID string
 }
 
 func NewAWSTerraformProvider(version string, progress output.Progress, configDir string) (*AWSTerraformProvider, error) {
@@ -66,10 +73,7 @@ func NewAWSTerraformProvider(version string, progress output.Progress, configDir
 		Name:         p.name,
 		DefaultAlias: *p.session.Config.Region,
 		GetProviderConfig: func(alias string) interface{} {
-			return awsConfig{
-				Region:     alias,
-				MaxRetries: 10, // TODO make this configurable
-			}
+			return p.providerConfig(alias)
 		},
 	}, progress)
 	if err != nil {
@@ -79,6 +83,24 @@ func NewAWSTerraformProvider(version string, progress output.Progress, configDir
 	return p, err
 }
 
+func (p *AWSTerraformProvider) providerConfig(alias string) awsConfig {
+	account, region := parseAccountAlias(alias)
+	config := awsConfig{
+		Region:     region,
+		MaxRetries: 10, // TODO make this configurable
+	}
+	if account != "" {
+		config.AssumeRole = []awsAssumeRoleConfig{
+			{
+				RoleARN:    roleARNForAccount(p.assumeRoleARN, account),
+				ExternalID: p.assumeRoleExternalID,
+			},
+		}
+		config.AllowedAccountIds = []string{account}
+	}
+	return config
+}
+
 func (a *AWSTerraformProvider) Name() string {
 	return a.name
 }
@@ -0,0 +1,137 @@
+package aws
+
+import (
+	"sort"
+
+	"github.com/sirupsen/logrus"
+	"github.com/snyk/driftctl/pkg/remote/aws/repository"
+	"github.com/snyk/driftctl/pkg/remote/common"
+	"github.com/snyk/driftctl/pkg/resource"
+	"github.com/snyk/driftctl/pkg/terraform"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// RegionalEnumerator wraps an enumerator bound to a given region
+// and records that region on every enumerated resource
+type RegionalEnumerator struct {
+	region     string
+	enumerator common.Enumerator
+}
+
+func NewRegionalEnumerator(region string, enumerator common.Enumerator) *RegionalEnumerator {
+	return &RegionalEnumerator{
+		region:     region,
+		enumerator: enumerator,
+	}
+}
+
+func (e *RegionalEnumerator) SupportedType() resource.ResourceType {
+	return e.enumerator.SupportedType()
+}
+
+func (e *RegionalEnumerator) Enumerate() ([]*resource.Resource, error) {
+	resources, err := e.enumerator.Enumerate()
+	if err != nil {
+		return nil, err
+	}
+	for _, res := range resources {
+		if res == nil {
+			continue
+		}
+		res.Region = e.region
+	}
+	return resources, nil
+}
+
+// RegionalDetailsFetcher dispatches details retrieval to the fetcher
+// bound to the region the resource was enumerated from
+type RegionalDetailsFetcher struct {
+	fetchers map[string]common.DetailsFetcher
+}
+
+func NewRegionalDetailsFetcher() *RegionalDetailsFetcher {
+	return &RegionalDetailsFetcher{
+		fetchers: make(map[string]common.DetailsFetcher),
+	}
+}
+
+func (f *RegionalDetailsFetcher) AddDetailsFetcher(region string, fetcher common.DetailsFetcher) {
+	f.fetchers[region] = fetcher
+}
+
+func (f *RegionalDetailsFetcher) ReadDetails(res *resource.Resource) (*resource.Resource, error) {
+	fetcher, exist := f.fetchers[res.Region]
+	if !exist {
+		logrus.WithFields(logrus.Fields{
+			"type":   res.ResourceType(),
+			"id":     res.ResourceId(),
+			"region": res.Region,
+		}).Debug("No details fetcher found for region, skipping details retrieval")
+		return res, nil
+	}
+
+	resourceWithDetails, err := fetcher.ReadDetails(res)
+	if err != nil {
+		return nil, err
+	}
+	if resourceWithDetails != nil {
+		resourceWithDetails.Region = res.Region
+	}
+	return resourceWithDetails, nil
+}
+
+// regionalResourceReader reads resources using the terraform provider alias
+// of a given region, unless the caller already asked for a specific alias
+type regionalResourceReader struct {
+	reader terraform.ResourceReader
+	region string
+}
+
+func newRegionalResourceReader(reader terraform.ResourceReader, region string) *regionalResourceReader {
+	return &regionalResourceReader{
+		reader: reader,
+		region: region,
+	}
+}
+
+func (r *regionalResourceReader) ReadResource(args terraform.ReadResourceArgs) (*cty.Value, error) {
+	if _, exist := args.Attributes["alias"]; !exist {
+		attributes := make(map[string]string, len(args.Attributes)+1)
+		for k, v := range args.Attributes {
+			attributes[k] = v
+		}
+		attributes["alias"] = r.region
+		args.Attributes = attributes
+	}
+	return r.reader.ReadResource(args)
+}
+
+func resolveRegions(repo repository.EC2Repository, defaultRegion string, regions []string) ([]string, error) {
+	if len(regions) == 0 {
+		return []string{defaultRegion}, nil
+	}
+
+	if len(regions) == 1 && regions[0] == AllRegions {
+		enabledRegions, err := repo.ListAllRegions()
+		if err != nil {
+			return nil, err
+		}
+		regions = make([]string, 0, len(enabledRegions))
+		for _, r := range enabledRegions {
+			regions = append(regions, *r.RegionName)
+		}
+	}
+
+	uniqueRegions := make(map[string]struct{}, len(regions))
+	result := make([]string, 0, len(regions))
+	for _, region := range regions {
+		if _, exist := uniqueRegions[region]; exist {
+			continue
+		}
+		uniqueRegions[region] = struct{}{}
+		result = append(result, region)
+	}
+	sort.Strings(result)
+
+	return result, nil
+}
@@ -0,0 +1,154 @@
+package aws
+
+import (
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	"github.com/snyk/driftctl/pkg/remote/aws/repository"
+	"github.com/snyk/driftctl/pkg/remote/common"
+	"github.com/snyk/driftctl/pkg/resource"
+	"github.com/snyk/driftctl/pkg/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type fakeDetailsFetcher struct {
+	read []*resource.Resource
+}
+
+func (f *fakeDetailsFetcher) ReadDetails(res *resource.Resource) (*resource.Resource, error) {
+	f.read = append(f.read, res)
+	return &resource.Resource{Id: res.Id, Type: res.Type}, nil
+}
+
+type fakeResourceReader struct {
+	args []terraform.ReadResourceArgs
+}
+
+func (r *fakeResourceReader) ReadResource(args terraform.ReadResourceArgs) (*cty.Value, error) {
+	r.args = append(r.args, args)
+	val := cty.NullVal(cty.DynamicPseudoType)
+	return &val, nil
+}
+
+func TestRegionalEnumerator_Enumerate(t *testing.T) {
+	enumerator := &common.MockEnumerator{}
+	enumerator.On("SupportedType").Return(resource.ResourceType("aws_instance"))
+	enumerator.On("Enumerate").Return([]*resource.Resource{
+		{Id: "i-1", Type: "aws_instance"},
+		nil,
+		{Id: "i-2", Type: "aws_instance"},
+	}, nil)
+
+	e := NewRegionalEnumerator("eu-west-3", enumerator)
+	assert.Equal(t, resource.ResourceType("aws_instance"), e.SupportedType())
+
+	got, err := e.Enumerate()
+	assert.NoError(t, err)
+	assert.Len(t, got, 3)
+	assert.Equal(t, "eu-west-3", got[0].Region)
+	assert.Equal(t, "eu-west-3", got[2].Region)
+}
+
+func TestRegionalEnumerator_EnumerateError(t *testing.T) {
+	listingErr := errors.New("access denied")
+	enumerator := &common.MockEnumerator{}
+	enumerator.On("Enumerate").Return(nil, listingErr)
+
+	got, err := NewRegionalEnumerator("eu-west-3", enumerator).Enumerate()
+	assert.Nil(t, got)
+	assert.Equal(t, listingErr, err)
+}
+
+func TestRegionalDetailsFetcher_ReadDetails(t *testing.T) {
+	euFetcher := &fakeDetailsFetcher{}
+	usFetcher := &fakeDetailsFetcher{}
+
+	f := NewRegionalDetailsFetcher()
+	f.AddDetailsFetcher("eu-west-3", euFetcher)
+	f.AddDetailsFetcher("us-east-1", usFetcher)
+
+	res := &resource.Resource{Id: "i-1", Type: "aws_instance", Region: "us-east-1"}
+	got, err := f.ReadDetails(res)
+	assert.NoError(t, err)
+	assert.Equal(t, "us-east-1", got.Region)
+	assert.Len(t, usFetcher.read, 1)
+	assert.Len(t, euFetcher.read, 0)
+
+	unknown := &resource.Resource{Id: "i-2", Type: "aws_instance", Region: "ap-south-1"}
+	got, err = f.ReadDetails(unknown)
+	assert.NoError(t, err)
+	assert.Same(t, unknown, got)
+}
+
+func TestRegionalResourceReader_ReadResource(t *testing.T) {
+	reader := &fakeResourceReader{}
+	r := newRegionalResourceReader(reader, "eu-west-3")
+
+	attributes := map[string]string{"foo": "bar"}
+	_, err := r.ReadResource(terraform.ReadResourceArgs{Ty: "aws_instance", ID: "i-1", Attributes: attributes})
+	assert.NoError(t, err)
+	_, err = r.ReadResource(terraform.ReadResourceArgs{Ty: "aws_s3_bucket", ID: "bucket", Attributes: map[string]string{"alias": "us-east-1"}})
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"foo": "bar", "alias": "eu-west-3"}, reader.args[0].Attributes)
+	assert.Equal(t, map[string]string{"alias": "us-east-1"}, reader.args[1].Attributes)
+	// Caller attributes must be left untouched
+	assert.Equal(t, map[string]string{"foo": "bar"}, attributes)
+}
+
+func Test_resolveRegions(t *testing.T) {
+	listingErr := errors.New("access denied")
+
+	tests := []struct {
+		name    string
+		regions []string
+		mocks   func(repo *repository.MockEC2Repository)
+		want    []string
+		wantErr error
+	}{
+		{
+			name:    "default to session region",
+			regions: []string{},
+			want:    []string{"us-east-1"},
+		},
+		{
+			name:    "explicit regions are sorted and deduplicated",
+			regions: []string{"us-east-1", "eu-west-3", "us-east-1"},
+			want:    []string{"eu-west-3", "us-east-1"},
+		},
+		{
+			name:    "all enabled regions",
+			regions: []string{AllRegions},
+			mocks: func(repo *repository.MockEC2Repository) {
+				repo.On("ListAllRegions").Return([]*ec2.Region{
+					{RegionName: awssdk.String("us-west-2")},
+					{RegionName: awssdk.String("eu-west-3")},
+				}, nil)
+			},
+			want: []string{"eu-west-3", "us-west-2"},
+		},
+		{
+			name:    "cannot list regions",
+			regions: []string{AllRegions},
+			mocks: func(repo *repository.MockEC2Repository) {
+				repo.On("ListAllRegions").Return(nil, listingErr)
+			},
+			wantErr: listingErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repository.MockEC2Repository{}
+			if tt.mocks != nil {
+				tt.mocks(repo)
+			}
+			got, err := resolveRegions(repo, "us-east-1", tt.regions)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+			repo.AssertExpectations(t)
+		})
+	}
+}
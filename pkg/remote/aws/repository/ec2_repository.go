@@ -24,6 +24,7 @@ type EC2Repository interface {
 	ListAllSecurityGroups() ([]*ec2.SecurityGroup, []*ec2.SecurityGroup, error)
 	ListAllNetworkACLs() ([]*ec2.NetworkAcl, error)
 	DescribeLaunchTemplates() ([]*ec2.LaunchTemplate, error)
+	ListAllRegions() ([]*ec2.Region, error)
 }
 
 type ec2Repository struct {
@@ -391,3 +392,19 @@ func (r *ec2Repository) DescribeLaunchTemplates() ([]*ec2.LaunchTemplate, error)
 	r.cache.Put(cacheKey, resp.LaunchTemplates)
 	return resp.LaunchTemplates, nil
 }
+
+func (r *ec2Repository) ListAllRegions() ([]*ec2.Region, error) {
+	if v := r.cache.Get("ec2ListAllRegions"); v != nil {
+		return v.([]*ec2.Region), nil
+	}
+
+	// Only regions enabled for the account are returned, opted-out ones are left aside
+	input := &ec2.DescribeRegionsInput{}
+	resp, err := r.client.DescribeRegions(input)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cache.Put("ec2ListAllRegions", resp.Regions)
+	return resp.Regions, nil
+}
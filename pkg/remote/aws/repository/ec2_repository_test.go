@@ -1355,3 +1355,70 @@ func Test_ec2Repository_DescribeLaunchTemplates(t *testing.T) {
 		})
 	}
 }
+
+func Test_ec2Repository_ListAllRegions(t *testing.T) {
+
+	testErr := errors.New("test")
+
+	tests := []struct {
+		name    string
+		mocks   func(client *awstest.MockFakeEC2)
+		want    []*ec2.Region
+		wantErr error
+	}{
+		{
+			name: "List regions",
+			mocks: func(client *awstest.MockFakeEC2) {
+				client.On("DescribeRegions", &ec2.DescribeRegionsInput{}).
+					Return(&ec2.DescribeRegionsOutput{
+						Regions: []*ec2.Region{
+							{RegionName: aws.String("eu-west-3")},
+							{RegionName: aws.String("us-east-1")},
+						},
+					}, nil).Once()
+			},
+			want: []*ec2.Region{
+				{RegionName: aws.String("eu-west-3")},
+				{RegionName: aws.String("us-east-1")},
+			},
+		},
+		{
+			name: "Error listing regions",
+			mocks: func(client *awstest.MockFakeEC2) {
+				client.On("DescribeRegions", &ec2.DescribeRegionsInput{}).
+					Return(nil, testErr).Once()
+			},
+			wantErr: testErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := cache.New(1)
+			client := &awstest.MockFakeEC2{}
+			tt.mocks(client)
+			r := &ec2Repository{
+				client: client,
+				cache:  store,
+			}
+			got, err := r.ListAllRegions()
+			assert.Equal(t, tt.wantErr, err)
+
+			if err == nil {
+				// Check that results were cached
+				cachedData, err := r.ListAllRegions()
+				assert.NoError(t, err)
+				assert.Equal(t, got, cachedData)
+				assert.IsType(t, []*ec2.Region{}, store.Get("ec2ListAllRegions"))
+			}
+
+			changelog, err := diff.Diff(got, tt.want)
+			assert.Nil(t, err)
+			if len(changelog) > 0 {
+				for _, change := range changelog {
+					t.Errorf("%s: %s -> %s", strings.Join(change.Path, "."), change.From, change.To)
+				}
+				t.Fail()
+			}
+		})
+	}
+}
@@ -242,6 +242,29 @@ func (_m *MockEC2Repository) ListAllNetworkACLs() ([]*ec2.NetworkAcl, error) {
 	return r0, r1
 }
 
+// ListAllRegions provides a mock function with given fields:
+func (_m *MockEC2Repository) ListAllRegions() ([]*ec2.Region, error) {
+	ret := _m.Called()
+
+	var r0 []*ec2.Region
+	if rf, ok := ret.Get(0).(func() []*ec2.Region); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*ec2.Region)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListAllRouteTables provides a mock function with given fields:
 func (_m *MockEC2Repository) ListAllRouteTables() ([]*ec2.RouteTable, error) {
 	ret := _m.Called()
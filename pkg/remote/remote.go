@@ -35,10 +35,11 @@ func Activate(remote, version string, alerter *alerter.Alerter,
 	progress output.Progress,
 	resourceSchemaRepository *resource.SchemaRepository,
 	factory resource.ResourceFactory,
-	configDir string) error {
+	configDir string,
+	awsOptions aws.Options) error {
 	switch remote {
 	case common.RemoteAWSTerraform:
-		return aws.Init(version, alerter, providerLibrary, remoteLibrary, progress, resourceSchemaRepository, factory, configDir)
+		return aws.Init(version, alerter, providerLibrary, remoteLibrary, progress, resourceSchemaRepository, factory, configDir, awsOptions)
 	case common.RemoteGithubTerraform:
 		return github.Init(version, alerter, providerLibrary, remoteLibrary, progress, resourceSchemaRepository, factory, configDir)
 	case common.RemoteGoogleTerraform:
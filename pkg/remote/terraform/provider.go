@@ -165,6 +165,7 @@ func (p *TerraformProvider) ReadResource(args tf.ReadResourceArgs) (*cty.Value,
 	if p.grpcProviders[alias] == nil {
 		err := p.configure(alias)
 		if err != nil {
+			p.lock.Unlock()
 			return nil, err
 		}
 	}
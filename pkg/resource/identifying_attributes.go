@@ -0,0 +1,66 @@
+package resource
+
+// IdentifyingAttributes tells how to recognise resources declared in configuration or plans before they are applied
+type IdentifyingAttributes struct {
+	// Attribute used by the provider as resource identifier, empty when the identifier is generated on creation
+	Identifier string
+	// Attributes matched against remote resources while the identifier is unknown, all of them are required
+	Attributes []string
+}
+
+func identifiedBy(attribute string) IdentifyingAttributes {
+	return IdentifyingAttributes{Identifier: attribute, Attributes: []string{attribute}}
+}
+
+func matchedOn(attributes ...string) IdentifyingAttributes {
+	return IdentifyingAttributes{Attributes: attributes}
+}
+
+var identifyingAttributes = map[string]IdentifyingAttributes{
+	"aws_api_gateway_rest_api":        matchedOn("name"),
+	"aws_db_instance":                 identifiedBy("identifier"),
+	"aws_db_subnet_group":             matchedOn("name"),
+	"aws_dynamodb_table":              identifiedBy("name"),
+	"aws_ecr_repository":              identifiedBy("name"),
+	"aws_iam_policy":                  matchedOn("name"),
+	"aws_iam_role":                    identifiedBy("name"),
+	"aws_iam_role_policy":             matchedOn("role", "name"),
+	"aws_iam_role_policy_attachment":  matchedOn("role", "policy_arn"),
+	"aws_iam_user":                    identifiedBy("name"),
+	"aws_iam_user_policy":             matchedOn("user", "name"),
+	"aws_iam_user_policy_attachment":  matchedOn("user", "policy_arn"),
+	"aws_internet_gateway":            matchedOn("vpc_id"),
+	"aws_key_pair":                    identifiedBy("key_name"),
+	"aws_kms_alias":                   identifiedBy("name"),
+	"aws_lambda_event_source_mapping": matchedOn("function_name", "event_source_arn"),
+	"aws_lambda_function":             identifiedBy("function_name"),
+	"aws_nat_gateway":                 matchedOn("subnet_id", "allocation_id"),
+	"aws_route_table_association":     matchedOn("subnet_id", "route_table_id"),
+	"aws_s3_bucket":                   identifiedBy("bucket"),
+	"aws_s3_bucket_policy":            identifiedBy("bucket"),
+	"aws_security_group":              matchedOn("vpc_id", "name"),
+	"aws_sns_topic":                   matchedOn("name"),
+	"aws_sns_topic_subscription":      matchedOn("topic_arn", "protocol", "endpoint"),
+	"aws_sqs_queue":                   matchedOn("name"),
+	"aws_subnet":                      matchedOn("vpc_id", "cidr_block"),
+	"azurerm_resource_group":          matchedOn("name"),
+	"azurerm_storage_account":         matchedOn("resource_group_name", "name"),
+	"azurerm_subnet":                  matchedOn("resource_group_name", "virtual_network_name", "name"),
+	"azurerm_virtual_network":         matchedOn("resource_group_name", "name"),
+	"github_branch_protection":        matchedOn("repository_id", "pattern"),
+	"github_membership":               matchedOn("username"),
+	"github_repository":               identifiedBy("name"),
+	"github_team":                     matchedOn("name"),
+	"github_team_membership":          matchedOn("team_id", "username"),
+	"google_compute_firewall":         matchedOn("name"),
+	"google_compute_instance":         matchedOn("zone", "name"),
+	"google_compute_network":          matchedOn("name"),
+	"google_compute_subnetwork":       matchedOn("region", "name"),
+	"google_storage_bucket":           identifiedBy("name"),
+}
+
+// GetIdentifyingAttributes returns how resources of the type are recognised before apply, false when they cannot be
+func GetIdentifyingAttributes(ty string) (IdentifyingAttributes, bool) {
+	attributes, exist := identifyingAttributes[ty]
+	return attributes, exist
+}
@@ -20,16 +20,36 @@ type SerializableSource struct {
 	S    string `json:"source"`
 	Ns   string `json:"namespace"`
 	Name string `json:"internal_name"`
+	// Only serialized in the version 2 of the JSON output
+	Key string `json:"key,omitempty"`
+}
+
+func (s *SerializableSource) Source() string {
+	return s.S
+}
+
+func (s *SerializableSource) Namespace() string {
+	return s.Ns
+}
+
+func (s *SerializableSource) InternalName() string {
+	return s.Name
+}
+
+func (s *SerializableSource) InstanceKey() string {
+	return s.Key
 }
 
 type TerraformStateSource struct {
 	State  string
 	Module string
 	Name   string
+	// Instance key of resources declared with count or for_each, e.g. [0] or ["key"]
+	Key string
 }
 
 func NewTerraformStateSource(state, module, name string) *TerraformStateSource {
-	return &TerraformStateSource{state, module, name}
+	return &TerraformStateSource{State: state, Module: module, Name: name}
 }
 
 func (s *TerraformStateSource) Source() string {
@@ -44,12 +64,38 @@ func (s *TerraformStateSource) InternalName() string {
 	return s.Name
 }
 
+func (s *TerraformStateSource) InstanceKey() string {
+	return s.Key
+}
+
+// TerraformConfigSource locates a resource declared in Terraform configuration files
+type TerraformConfigSource struct {
+	TerraformStateSource
+}
+
+func NewTerraformConfigSource(config, module, name string) *TerraformConfigSource {
+	return &TerraformConfigSource{TerraformStateSource{State: config, Module: module, Name: name}}
+}
+
+// TerraformPlanSource locates a resource planned in a Terraform JSON plan
+type TerraformPlanSource struct {
+	TerraformStateSource
+}
+
+func NewTerraformPlanSource(plan, module, name string) *TerraformPlanSource {
+	return &TerraformPlanSource{TerraformStateSource{State: plan, Module: module, Name: name}}
+}
+
 type Resource struct {
 	Id     string
 	Type   string
 	Attrs  *Attributes
 	Sch    *Schema `json:"-" diff:"-"`
 	Source Source  `json:"-"`
+	// Region the resource was enumerated from, empty for global resources
+	Region string `json:"-" diff:"-"`
+	// Account the resource belongs to, empty unless accounts are explicitly scanned or bound to a state
+	Account string `json:"-" diff:"-"`
 }
 
 func (r *Resource) Schema() *Schema {
@@ -82,8 +128,41 @@ func (r *Resource) SourceString() string {
 	return fmt.Sprintf("%s.%s.%s", r.Source.Namespace(), r.ResourceType(), r.Source.InternalName())
 }
 
+// Address returns the full Terraform address of the resource including its instance key, it is empty for resources
+// without IaC source
+func (r *Resource) Address() string {
+	if r.Source == nil {
+		return ""
+	}
+	address := r.SourceString()
+	if src, ok := r.Source.(interface{ InstanceKey() string }); ok {
+		address += src.InstanceKey()
+	}
+	return address
+}
+
 func (r *Resource) Equal(res *Resource) bool {
-	if r.ResourceId() != res.ResourceId() || r.ResourceType() != res.ResourceType() {
+	if r.ResourceType() != res.ResourceType() {
+		return false
+	}
+
+	// Resources declared in configuration or plans may not know their identifier before apply,
+	// fallback on their identifying attributes to find the corresponding resource
+	if r.IsPending() || res.IsPending() {
+		if !r.hasSameIdentifyingAttributes(res) {
+			return false
+		}
+	} else if r.ResourceId() != res.ResourceId() {
+		return false
+	}
+
+	// The same identifier can exist in several accounts
+	if r.Account != "" && res.Account != "" && r.Account != res.Account {
+		return false
+	}
+
+	// Names of regional resources are only unique within a region
+	if r.Region != "" && res.Region != "" && r.Region != res.Region {
 		return false
 	}
 
@@ -94,14 +173,64 @@ func (r *Resource) Equal(res *Resource) bool {
 	return true
 }
 
+// IsPending returns true for resources declared in configuration or plans whose identifier is only known after apply
+func (r *Resource) IsPending() bool {
+	if r.ResourceId() != "" {
+		return false
+	}
+	switch r.Source.(type) {
+	case *TerraformConfigSource, *TerraformPlanSource:
+		return true
+	}
+	return false
+}
+
+// IsUnidentifiable returns true for pending resources of types without identifying attributes, they cannot be matched
+// against remote resources
+func (r *Resource) IsUnidentifiable() bool {
+	_, exist := identifyingAttributes[r.ResourceType()]
+	return r.IsPending() && !exist
+}
+
+// hasSameIdentifyingAttributes compares every identifying attribute of the resource type,
+// all of them are required to be set on both resources
+func (r *Resource) hasSameIdentifyingAttributes(res *Resource) bool {
+	attributes, exist := identifyingAttributes[r.ResourceType()]
+	if !exist || r.Attrs == nil || res.Attrs == nil {
+		return false
+	}
+
+	for _, key := range attributes.Attributes {
+		value, exist := (*r.Attrs)[key]
+		if !exist || value == nil {
+			return false
+		}
+		other, exist := (*res.Attrs)[key]
+		if !exist || other == nil {
+			return false
+		}
+		if fmt.Sprint(value) != fmt.Sprint(other) {
+			return false
+		}
+	}
+
+	return true
+}
+
 type ResourceFactory interface {
 	CreateAbstractResource(ty, id string, data map[string]interface{}) *Resource
 }
 
 type SerializableResource struct {
-	Id     string              `json:"id"`
-	Type   string              `json:"type"`
-	Source *SerializableSource `json:"source,omitempty"`
+	Id      string              `json:"id"`
+	Type    string              `json:"type"`
+	Source  *SerializableSource `json:"source,omitempty"`
+	Region  string              `json:"region,omitempty"`
+	Account string              `json:"account,omitempty"`
+	// Only set for unmanaged resources read in deep mode, and for every resource in the version 2 of the JSON output
+	Attributes *Attributes `json:"attributes,omitempty"`
+	// Full Terraform address, only set in the version 2 of the JSON output
+	Address string `json:"address,omitempty"`
 }
 
 func NewSerializableResource(res *Resource) *SerializableResource {
@@ -114,9 +243,11 @@ func NewSerializableResource(res *Resource) *SerializableResource {
 		}
 	}
 	return &SerializableResource{
-		Id:     res.ResourceId(),
-		Type:   res.ResourceType(),
-		Source: src,
+		Id:      res.ResourceId(),
+		Type:    res.ResourceType(),
+		Source:  src,
+		Region:  res.Region,
+		Account: res.Account,
 	}
 }
 
@@ -73,3 +73,129 @@ func TestSanitizeDefaults(t *testing.T) {
 		})
 	}
 }
+
+func TestResource_Equal(t *testing.T) {
+	tests := []struct {
+		name  string
+		left  *Resource
+		right *Resource
+		want  bool
+	}{
+		{
+			name:  "same resource",
+			left:  &Resource{Id: "admin", Type: "aws_iam_role"},
+			right: &Resource{Id: "admin", Type: "aws_iam_role", Account: "111111111111"},
+			want:  true,
+		},
+		{
+			name:  "different id",
+			left:  &Resource{Id: "admin", Type: "aws_iam_role"},
+			right: &Resource{Id: "readonly", Type: "aws_iam_role"},
+			want:  false,
+		},
+		{
+			name:  "same id in different accounts",
+			left:  &Resource{Id: "admin", Type: "aws_iam_role", Account: "222222222222"},
+			right: &Resource{Id: "admin", Type: "aws_iam_role", Account: "111111111111"},
+			want:  false,
+		},
+		{
+			name:  "same name in different regions",
+			left:  &Resource{Id: "alias/aws/ebs", Type: "aws_kms_alias", Region: "us-east-1"},
+			right: &Resource{Id: "alias/aws/ebs", Type: "aws_kms_alias", Region: "eu-west-3"},
+			want:  false,
+		},
+		{
+			name:  "same name with unknown region",
+			left:  &Resource{Id: "alias/aws/ebs", Type: "aws_kms_alias"},
+			right: &Resource{Id: "alias/aws/ebs", Type: "aws_kms_alias", Region: "eu-west-3"},
+			want:  true,
+		},
+		{
+			name:  "unknown id with same identifying attributes",
+			left:  &Resource{Type: "github_team_membership", Source: NewTerraformConfigSource("tfconfig://.", "", "bot"), Attrs: &Attributes{"id": "", "team_id": "4567", "username": "bot", "etag": nil}},
+			right: &Resource{Id: "4567:bot", Type: "github_team_membership", Attrs: &Attributes{"id": "4567:bot", "team_id": "4567", "username": "bot", "role": "member"}},
+			want:  true,
+		},
+		{
+			name:  "unknown id of planned resource compared from remote side",
+			left:  &Resource{Id: "4567:bot", Type: "github_team_membership", Attrs: &Attributes{"id": "4567:bot", "team_id": "4567", "username": "bot", "role": "member"}},
+			right: &Resource{Type: "github_team_membership", Source: NewTerraformPlanSource("tfplan://plan.json", "", "bot"), Attrs: &Attributes{"team_id": "4567", "username": "bot"}},
+			want:  true,
+		},
+		{
+			name:  "unknown id with different identifying attributes",
+			left:  &Resource{Type: "github_team_membership", Source: NewTerraformConfigSource("tfconfig://.", "", "bot"), Attrs: &Attributes{"team_id": "4567", "username": "bot"}},
+			right: &Resource{Id: "4567:admin", Type: "github_team_membership", Attrs: &Attributes{"team_id": "4567", "username": "admin"}},
+			want:  false,
+		},
+		{
+			name:  "unknown id with only some identifying attributes",
+			left:  &Resource{Type: "github_team_membership", Source: NewTerraformConfigSource("tfconfig://.", "", "bot"), Attrs: &Attributes{"username": "bot", "role": "member"}},
+			right: &Resource{Id: "4567:bot", Type: "github_team_membership", Attrs: &Attributes{"team_id": "4567", "username": "bot", "role": "member"}},
+			want:  false,
+		},
+		{
+			name:  "unknown id of type without identifying attributes",
+			left:  &Resource{Type: "aws_instance", Source: NewTerraformConfigSource("tfconfig://.", "", "web"), Attrs: &Attributes{"instance_type": "t3.micro"}},
+			right: &Resource{Id: "i-0123456789", Type: "aws_instance", Attrs: &Attributes{"instance_type": "t3.micro"}},
+			want:  false,
+		},
+		{
+			name:  "unknown id of state resource",
+			left:  &Resource{Type: "github_team_membership", Source: NewTerraformStateSource("tfstate://terraform.tfstate", "", "bot"), Attrs: &Attributes{"team_id": "4567", "username": "bot"}},
+			right: &Resource{Id: "4567:bot", Type: "github_team_membership", Attrs: &Attributes{"team_id": "4567", "username": "bot"}},
+			want:  false,
+		},
+		{
+			name:  "unknown id of another type",
+			left:  &Resource{Type: "github_membership", Source: NewTerraformConfigSource("tfconfig://.", "", "bot"), Attrs: &Attributes{"username": "bot"}},
+			right: &Resource{Id: "org:bot", Type: "github_team_membership", Attrs: &Attributes{"username": "bot"}},
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.left.Equal(tt.right))
+		})
+	}
+}
+
+func TestResource_Address(t *testing.T) {
+	tests := []struct {
+		name string
+		res  *Resource
+		want string
+	}{
+		{
+			name: "resource without source",
+			res:  &Resource{Id: "user", Type: "aws_iam_user"},
+			want: "",
+		},
+		{
+			name: "resource of the root module",
+			res:  &Resource{Id: "user", Type: "aws_iam_user", Source: NewTerraformStateSource("tfstate://terraform.tfstate", "", "admin")},
+			want: "aws_iam_user.admin",
+		},
+		{
+			name: "resource declared with count in a module",
+			res: &Resource{Id: "user", Type: "aws_iam_user", Source: &TerraformStateSource{
+				State:  "tfstate://terraform.tfstate",
+				Module: "module.users",
+				Name:   "admin",
+				Key:    "[1]",
+			}},
+			want: "module.users.aws_iam_user.admin[1]",
+		},
+		{
+			name: "deserialized resource declared with for_each",
+			res:  &Resource{Id: "user", Type: "aws_iam_user", Source: &SerializableSource{S: "tfstate://terraform.tfstate", Name: "admin", Key: `["john"]`}},
+			want: `aws_iam_user.admin["john"]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.res.Address())
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package resource
 
+import "strings"
+
 type ResourceType string
 
 var supportedTypes = map[string]ResourceTypeMeta{
@@ -239,6 +241,12 @@ func (ty ResourceType) String() string {
 	return string(ty)
 }
 
+// Provider returns the name of the terraform provider of the type,
+// resource types are prefixed by their provider name
+func (ty ResourceType) Provider() string {
+	return strings.SplitN(string(ty), "_", 2)[0]
+}
+
 func GetMeta(ty ResourceType) ResourceTypeMeta {
 	return supportedTypes[ty.String()]
 }
@@ -0,0 +1,324 @@
+package serve
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/pkg/cmd/scan/output"
+)
+
+type Status string
+
+const (
+	StatusRunning Status = "running"
+	StatusDone    Status = "done"
+	StatusFailed  Status = "failed"
+
+	// Scan ID that always refers to the most recent successful scan
+	latestScanID = "latest"
+)
+
+var ErrScanInProgress = errors.New("a scan is already in progress")
+
+// ScanFunc runs a single scan, it should stop as soon as possible once ctx is done
+type ScanFunc func(ctx context.Context) (*analyser.Analysis, error)
+
+type Scan struct {
+	ID         string            `json:"id"`
+	Status     Status            `json:"status"`
+	StartedAt  time.Time         `json:"started_at"`
+	FinishedAt *time.Time        `json:"finished_at,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	Coverage   *int              `json:"coverage,omitempty"`
+	Summary    *analyser.Summary `json:"summary,omitempty"`
+	analysis   *analyser.Analysis
+}
+
+type Options struct {
+	// Number of scans kept in memory, the oldest ones are dropped first
+	KeepResults int
+	// Interval between two scheduled scans, scans are only run on demand when zero
+	Interval time.Duration
+	// Bearer token required on every request, requests are not authenticated when empty
+	Token string
+}
+
+// Server runs scans one at a time and serves their results over HTTP
+type Server struct {
+	ctx     context.Context
+	scan    ScanFunc
+	opts    Options
+	mu      sync.RWMutex
+	scans   []*Scan
+	lastID  int
+	running bool
+	wg      sync.WaitGroup
+}
+
+func NewServer(ctx context.Context, scan ScanFunc, opts Options) *Server {
+	if opts.KeepResults < 1 {
+		opts.KeepResults = 1
+	}
+	return &Server{
+		ctx:   ctx,
+		scan:  scan,
+		opts:  opts,
+		scans: make([]*Scan, 0, opts.KeepResults),
+	}
+}
+
+// Trigger starts a scan in background, only one scan can run at a time
+func (s *Server) Trigger() (Scan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		return Scan{}, ErrScanInProgress
+	}
+	s.running = true
+	s.lastID++
+
+	scan := &Scan{
+		ID:        strconv.Itoa(s.lastID),
+		Status:    StatusRunning,
+		StartedAt: time.Now(),
+	}
+	s.scans = append(s.scans, scan)
+	if len(s.scans) > s.opts.KeepResults {
+		s.scans = s.scans[len(s.scans)-s.opts.KeepResults:]
+	}
+
+	s.wg.Add(1)
+	go s.run(scan)
+
+	return *scan, nil
+}
+
+func (s *Server) run(scan *Scan) {
+	defer s.wg.Done()
+
+	logrus.WithField("scan", scan.ID).Info("Starting scan")
+	analysis, err := s.runScan()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.running = false
+	finishedAt := time.Now()
+	scan.FinishedAt = &finishedAt
+	if err != nil {
+		logrus.WithField("scan", scan.ID).Errorf("Scan failed: %s", err)
+		scan.Status = StatusFailed
+		scan.Error = err.Error()
+		return
+	}
+
+	logrus.WithField("scan", scan.ID).Info("Scan done")
+	coverage := analysis.Coverage()
+	summary := analysis.Summary()
+	scan.Status = StatusDone
+	scan.Coverage = &coverage
+	scan.Summary = &summary
+	scan.analysis = analysis
+}
+
+// runScan turns a panicking scan into a failed one, the server keeps serving results and running next scans
+func (s *Server) runScan() (analysis *analyser.Analysis, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			sentry.CurrentHub().Recover(r)
+			logrus.Debugf("Scan panicked: %s\n%s", r, debug.Stack())
+			err = errors.Errorf("scan panicked: %s", r)
+		}
+	}()
+	return s.scan(s.ctx)
+}
+
+// Schedule triggers a scan right away then at each interval until ctx is done
+func (s *Server) Schedule() {
+	if s.opts.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.opts.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.Trigger(); err != nil {
+			logrus.Warnf("Skipping scheduled scan: %s", err)
+		}
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Wait blocks until the running scan is over
+func (s *Server) Wait() {
+	s.wg.Wait()
+}
+
+// Scans returns scans kept in memory, most recent first
+func (s *Server) Scans() []Scan {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	scans := make([]Scan, 0, len(s.scans))
+	for i := len(s.scans) - 1; i >= 0; i-- {
+		scans = append(scans, *s.scans[i])
+	}
+	return scans
+}
+
+// Get returns a scan from its ID, latest refers to the most recent successful scan
+func (s *Server) Get(id string) (Scan, bool) {
+	for _, scan := range s.Scans() {
+		if scan.ID == id || (id == latestScanID && scan.Status == StatusDone) {
+			return scan, true
+		}
+	}
+	return Scan{}, false
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/scans", s.handleScans)
+	mux.HandleFunc("/api/scans/", s.handleScan)
+	mux.HandleFunc("/api/coverage", s.handleCoverage)
+	mux.HandleFunc("/", s.handleReport)
+	return s.authenticate(mux)
+}
+
+// authenticate rejects requests without the bearer token when one is configured
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	if s.opts.Token == "" {
+		return next
+	}
+	expected := []byte("Bearer " + s.opts.Token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="driftctl"`)
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// GET lists scans, POST triggers a new one
+func (s *Server) handleScans(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.Scans())
+	case http.MethodPost:
+		scan, err := s.Trigger()
+		if err != nil {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+		writeJSON(w, http.StatusAccepted, scan)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errors.Errorf("method %s is not allowed", r.Method))
+	}
+}
+
+// Serves /api/scans/{id} as JSON and /api/scans/{id}/html as an HTML report
+func (s *Server) handleScan(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+
+	path := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/scans/"), "/")
+	if len(path) > 2 || (len(path) == 2 && path[1] != "html") {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+
+	scan, exists := s.Get(path[0])
+	if !exists {
+		writeError(w, http.StatusNotFound, errors.Errorf("scan %s not found", path[0]))
+		return
+	}
+	if scan.analysis == nil {
+		writeError(w, http.StatusNotFound, errors.Errorf("scan %s has no result, its status is %s", scan.ID, scan.Status))
+		return
+	}
+
+	if len(path) == 2 {
+		writeHTML(w, scan.analysis)
+		return
+	}
+	writeJSON(w, http.StatusOK, scan.analysis)
+}
+
+func (s *Server) handleCoverage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+
+	scan, exists := s.Get(latestScanID)
+	if !exists {
+		writeError(w, http.StatusNotFound, errors.New("no scan has completed yet"))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"scan_id":  scan.ID,
+		"date":     scan.FinishedAt,
+		"coverage": scan.Coverage,
+	})
+}
+
+// Serves the HTML report of the most recent successful scan
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	scan, exists := s.Get(latestScanID)
+	if !exists {
+		http.Error(w, "No scan has completed yet, trigger one with POST /api/scans", http.StatusNotFound)
+		return
+	}
+	writeHTML(w, scan.analysis)
+}
+
+func writeHTML(w http.ResponseWriter, analysis *analyser.Analysis) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := output.RenderHTML(w, analysis); err != nil {
+		logrus.Errorf("Unable to render HTML report: %s", err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	content, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(content)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	content, _ := json.Marshal(map[string]string{"error": err.Error()})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(content)
+}
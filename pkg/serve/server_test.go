@@ -0,0 +1,238 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/snyk/driftctl/pkg/analyser"
+	"github.com/snyk/driftctl/pkg/resource"
+)
+
+func fakeAnalysis() *analyser.Analysis {
+	a := analyser.NewAnalysis(analyser.AnalyzerOptions{})
+	a.AddManaged(&resource.Resource{Id: "bucket", Type: "aws_s3_bucket"})
+	a.AddUnmanaged(&resource.Resource{Id: "user", Type: "aws_iam_user"})
+	return a
+}
+
+func newTestServer(scan ScanFunc, keepResults int) *Server {
+	return NewServer(context.Background(), scan, Options{KeepResults: keepResults})
+}
+
+func request(s *Server, method, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec
+}
+
+func TestServer_Trigger(t *testing.T) {
+	release := make(chan struct{})
+	s := newTestServer(func(ctx context.Context) (*analyser.Analysis, error) {
+		<-release
+		return fakeAnalysis(), nil
+	}, 10)
+
+	scan, err := s.Trigger()
+	assert.NoError(t, err)
+	assert.Equal(t, "1", scan.ID)
+	assert.Equal(t, StatusRunning, scan.Status)
+
+	_, err = s.Trigger()
+	assert.Equal(t, ErrScanInProgress, err)
+
+	close(release)
+	s.Wait()
+
+	got, exists := s.Get("1")
+	assert.True(t, exists)
+	assert.Equal(t, StatusDone, got.Status)
+	assert.Equal(t, 50, *got.Coverage)
+	assert.NotNil(t, got.FinishedAt)
+
+	latest, exists := s.Get("latest")
+	assert.True(t, exists)
+	assert.Equal(t, "1", latest.ID)
+}
+
+func TestServer_KeepResults(t *testing.T) {
+	calls := 0
+	s := newTestServer(func(ctx context.Context) (*analyser.Analysis, error) {
+		calls++
+		if calls == 3 {
+			return nil, errors.New("unable to scan")
+		}
+		return fakeAnalysis(), nil
+	}, 2)
+
+	for i := 0; i < 3; i++ {
+		_, err := s.Trigger()
+		assert.NoError(t, err)
+		s.Wait()
+	}
+
+	scans := s.Scans()
+	if assert.Len(t, scans, 2) {
+		assert.Equal(t, "3", scans[0].ID)
+		assert.Equal(t, StatusFailed, scans[0].Status)
+		assert.Equal(t, "unable to scan", scans[0].Error)
+		assert.Equal(t, "2", scans[1].ID)
+		assert.Equal(t, StatusDone, scans[1].Status)
+	}
+
+	_, exists := s.Get("1")
+	assert.False(t, exists)
+
+	// Latest points to the most recent successful scan
+	latest, _ := s.Get("latest")
+	assert.Equal(t, "2", latest.ID)
+}
+
+func TestServer_Handler(t *testing.T) {
+	s := newTestServer(func(ctx context.Context) (*analyser.Analysis, error) {
+		return fakeAnalysis(), nil
+	}, 10)
+
+	// Nothing was scanned yet
+	rec := request(s, http.MethodGet, "/api/coverage")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.JSONEq(t, `{"error": "no scan has completed yet"}`, rec.Body.String())
+
+	rec = request(s, http.MethodGet, "/")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = request(s, http.MethodPost, "/api/scans")
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	triggered := Scan{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &triggered))
+	assert.Equal(t, "1", triggered.ID)
+	s.Wait()
+
+	rec = request(s, http.MethodGet, "/api/scans")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	scans := []Scan{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &scans))
+	if assert.Len(t, scans, 1) {
+		assert.Equal(t, StatusDone, scans[0].Status)
+		assert.Equal(t, 2, scans[0].Summary.TotalResources)
+	}
+
+	rec = request(s, http.MethodGet, "/api/scans/1")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	analysis := &analyser.Analysis{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), analysis))
+	assert.Equal(t, 50, analysis.Coverage())
+
+	rec = request(s, http.MethodGet, "/api/scans/latest/html")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.True(t, strings.Contains(rec.Body.String(), "<html"))
+
+	rec = request(s, http.MethodGet, "/")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, strings.Contains(rec.Body.String(), "<html"))
+
+	rec = request(s, http.MethodGet, "/api/coverage")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	coverage := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &coverage))
+	assert.Equal(t, "1", coverage["scan_id"])
+	assert.Equal(t, float64(50), coverage["coverage"])
+
+	rec = request(s, http.MethodGet, "/api/scans/42")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.JSONEq(t, `{"error": "scan 42 not found"}`, rec.Body.String())
+
+	rec = request(s, http.MethodGet, "/api/scans/1/pdf")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = request(s, http.MethodDelete, "/api/scans")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestServer_PanickingScan(t *testing.T) {
+	calls := 0
+	s := newTestServer(func(ctx context.Context) (*analyser.Analysis, error) {
+		calls++
+		if calls == 1 {
+			panic("unexpected resource")
+		}
+		return fakeAnalysis(), nil
+	}, 10)
+
+	_, err := s.Trigger()
+	assert.NoError(t, err)
+	s.Wait()
+
+	failed, exists := s.Get("1")
+	assert.True(t, exists)
+	assert.Equal(t, StatusFailed, failed.Status)
+	assert.Equal(t, "scan panicked: unexpected resource", failed.Error)
+
+	// Next scans still run
+	_, err = s.Trigger()
+	assert.NoError(t, err)
+	s.Wait()
+
+	latest, exists := s.Get("latest")
+	assert.True(t, exists)
+	assert.Equal(t, "2", latest.ID)
+}
+
+func TestServer_Authentication(t *testing.T) {
+	s := NewServer(context.Background(), func(ctx context.Context) (*analyser.Analysis, error) {
+		return fakeAnalysis(), nil
+	}, Options{KeepResults: 10, Token: "secret"})
+
+	cases := []struct {
+		name          string
+		authorization string
+		expectedCode  int
+	}{
+		{
+			name:         "missing token",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:          "invalid token",
+			authorization: "Bearer wrong",
+			expectedCode:  http.StatusUnauthorized,
+		},
+		{
+			name:          "token without scheme",
+			authorization: "secret",
+			expectedCode:  http.StatusUnauthorized,
+		},
+		{
+			name:          "valid token",
+			authorization: "Bearer secret",
+			expectedCode:  http.StatusOK,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/scans", nil)
+			if c.authorization != "" {
+				req.Header.Set("Authorization", c.authorization)
+			}
+			rec := httptest.NewRecorder()
+			s.Handler().ServeHTTP(rec, req)
+			assert.Equal(t, c.expectedCode, rec.Code)
+			if c.expectedCode == http.StatusUnauthorized {
+				assert.Equal(t, `Bearer realm="driftctl"`, rec.Header().Get("WWW-Authenticate"))
+				assert.JSONEq(t, `{"error": "missing or invalid bearer token"}`, rec.Body.String())
+			}
+		})
+	}
+
+	// Scans cannot be triggered without the token
+	rec := request(s, http.MethodPost, "/api/scans")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Empty(t, s.Scans())
+}
@@ -0,0 +1,38 @@
+package terraform
+
+import (
+	"github.com/hashicorp/terraform/plugin/discovery"
+	"github.com/hashicorp/terraform/providers"
+	"github.com/pkg/errors"
+)
+
+// ReadProviderSchema installs the given provider when needed and reads the schema of its resources,
+// the provider is not configured so no credentials are required
+func ReadProviderSchema(name, version, configDir string) (map[string]providers.Schema, error) {
+	installer, err := NewProviderInstaller(ProviderConfig{
+		Key:       name,
+		Version:   version,
+		ConfigDir: configDir,
+	})
+	if err != nil {
+		return nil, err
+	}
+	providerPath, err := installer.Install()
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := NewGRPCProvider(discovery.PluginMeta{
+		Path: providerPath,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer provider.Close()
+
+	schema := provider.GetSchema()
+	if schema.Diagnostics.HasErrors() {
+		return nil, errors.Wrapf(schema.Diagnostics.Err(), "unable to read schema of provider %s", name)
+	}
+	return schema.ResourceTypes, nil
+}
@@ -3,6 +3,7 @@ package resource
 import (
 	"github.com/hashicorp/terraform/providers"
 	"github.com/snyk/driftctl/pkg/resource"
+	"github.com/snyk/driftctl/pkg/terraform"
 	"github.com/snyk/driftctl/test/schemas"
 )
 
@@ -20,3 +21,23 @@ func InitFakeSchemaRepository(provider, version string) resource.SchemaRepositor
 	_ = repo.Init("Fake", "1.0.0", schema)
 	return repo
 }
+
+type fakeProvider struct {
+	terraform.TerraformProvider
+	schema map[string]providers.Schema
+}
+
+func (p *fakeProvider) Schema() map[string]providers.Schema {
+	return p.schema
+}
+
+// InitFakeProviderLibrary returns a library holding a provider that only serves the test schema of the given version
+func InitFakeProviderLibrary(provider, version string) *terraform.ProviderLibrary {
+	schema, err := schemas.ReadTestSchema(provider, version)
+	if err != nil {
+		panic(err)
+	}
+	library := terraform.NewProviderLibrary()
+	library.AddProvider(provider, &fakeProvider{schema: schema})
+	return library
+}